@@ -16,6 +16,7 @@ import (
 	asimhttp "github.com/GoSim-25-26J-441/go-sim-backend/internal/analysis_suggestions/http"
 	httpapi "github.com/GoSim-25-26J-441/go-sim-backend/internal/api/http"
 	amgapd "github.com/GoSim-25-26J-441/go-sim-backend/internal/api/http/amg_apd"
+	amgapdservice "github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/service"
 
 	apimiddleware "github.com/GoSim-25-26J-441/go-sim-backend/internal/api/http/middleware"
 	authpkg "github.com/GoSim-25-26J-441/go-sim-backend/internal/auth"
@@ -33,6 +34,7 @@ import (
 	amgapdversion "github.com/GoSim-25-26J-441/go-sim-backend/internal/storage/amg_apd_version"
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/storage/postgres"
 	redisstorage "github.com/GoSim-25-26J-441/go-sim-backend/internal/storage/redis"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/storage/repositories"
 	s3storage "github.com/GoSim-25-26J-441/go-sim-backend/internal/storage/s3"
 
 	// Projects module (from temp branch)
@@ -125,6 +127,11 @@ func main() {
 
 	scheduler := cronjob.NewScheduler()
 	scheduler.Start()
+	if name, err := amgapdservice.LoadRankingModelFromEnv(); err != nil {
+		log.Printf("Warning: Failed to load AMG-APD ranking model: %v (detections keep the fixed weights)", err)
+	} else if name != "" {
+		log.Printf("AMG-APD ranking model %s loaded", name)
+	}
 	amgapd.Register(router, db, nil)
 
 	api := router.Group("/api/v1")
@@ -159,6 +166,7 @@ func main() {
 		projectRepo := projectrepo.NewProjectRepository(db)
 		diagramRepo := projectrepo.NewDiagramRepository(db)
 		amgApdVersionRepo := amgapdversion.NewRepo(db)
+		amgApdFeedbackRepo := repositories.NewFeedbackRepo(db)
 
 		chatRepo := chatrepo.NewChatRepository(db)
 		llmClient := chat.NewLLMClient(cfg.Upstreams.LLMSvcURL, cfg.Upstreams.LLMAPIKey)
@@ -170,6 +178,7 @@ func main() {
 		projectHandler := projecthttp.New(projectService, chatService, diagramService, s3Client)
 		projectHandler.OnProjectDeleted = func(userID, projectPublicID string) {
 			_, _ = amgApdVersionRepo.DeleteByProject(userID, projectPublicID)
+			_, _ = amgApdFeedbackRepo.DeleteByProject(userID, projectPublicID)
 		}
 		projectHandler.Register(projectsGroup)
 
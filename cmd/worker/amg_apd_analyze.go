@@ -1,16 +1,31 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/graph/export"
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/service"
 )
 
 func RunAnalyze(args []string) {
+	fs := flag.NewFlagSet("analyze", flag.ExitOnError)
+	baselinePath := fs.String("baseline", "", "previous analysis.json; only detections not in it fail the run")
+	input := fs.String("input", "yaml", "input format: yaml or one of "+strings.Join(service.ImportFormats(), ", "))
+	designed := fs.String("designed", "", "design spec.yaml to compare the analyzed (e.g. observed) architecture against")
+	configPath := fs.String("detector-config", "", "detector config JSON to analyze with instead of the built-in defaults")
+	formats := fs.String("format", "", "extra diagram formats to write next to graph.dot and graph.svg, comma-separated ("+strings.Join(export.FormatNames(), ", ")+")")
+	_ = fs.Parse(args)
+	args = fs.Args()
+
 	if len(args) < 1 {
-		log.Fatal("usage: worker analyze <yamlPath> [outDir] [title]")
+		log.Fatal("usage: worker analyze [-input compose|kubernetes|otlp|api-docs] [-designed spec.yaml] [-baseline analysis.json] [-detector-config config.json] [-format mermaid,graphml,...] <path> [outDir] [title]")
 	}
 
 	yamlPath := args[0]
@@ -25,14 +40,179 @@ func RunAnalyze(args []string) {
 		title = args[2]
 	}
 
-	res, err := service.AnalyzeYAML(yamlPath, outDir, title, os.Getenv("DOT_BIN"))
+	cfg, err := loadDetectorConfig(*configPath)
+	if err != nil {
+		log.Fatalf("detector config: %v", err)
+	}
+	if _, err := service.LoadRankingModelFromEnv(); err != nil {
+		log.Fatalf("ranking model: %v", err)
+	}
+
+	var res *service.Result
+	if *input == "" || *input == "yaml" {
+		res, err = service.AnalyzeYAML(yamlPath, outDir, title, os.Getenv("DOT_BIN"), cfg)
+	} else {
+		res, err = analyzeImported(*input, yamlPath, outDir, title, cfg)
+	}
 	if err != nil {
 		log.Fatalf("analyze failed: %v", err)
 	}
 
 	fmt.Printf("Wrote: %s, %s\n", res.DOTPath, res.SVGPath)
+	for _, is := range res.Issues {
+		fmt.Printf("warning: %s\n", is)
+	}
+	for _, name := range strings.Split(*formats, ",") {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		f, err := export.LookupFormat(name)
+		if err != nil {
+			log.Fatalf("export: %v", err)
+		}
+		path := filepath.Join(outDir, "graph."+f.Ext)
+		if path == res.DOTPath || path == res.SVGPath {
+			// The analysis already wrote this file; keep it (the SVG may come from Graphviz).
+			continue
+		}
+		out, _, err := export.Render(name, res.Graph, title, res.Detections)
+		if err != nil {
+			log.Fatalf("export: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(out), 0644); err != nil {
+			log.Fatalf("export %s: %v", f.Name, err)
+		}
+		fmt.Printf("Wrote: %s\n", path)
+	}
 	fmt.Printf("Detections (%d):\n", len(res.Detections))
 	for _, d := range res.Detections {
 		fmt.Printf(" - [%s] %s: %s\n", d.Kind, d.Title, d.Summary)
 	}
+	if len(res.Suppressed) > 0 {
+		fmt.Printf("Suppressed (%d):\n", len(res.Suppressed))
+		for _, d := range res.Suppressed {
+			fmt.Printf(" - [%s] %s\n", d.Kind, d.Title)
+		}
+	}
+
+	if *designed != "" {
+		g, err := service.LoadDesignFile(*designed)
+		if err != nil {
+			log.Fatalf("load design: %v", err)
+		}
+		printDrift(service.CompareArchitectures(g, res.Graph))
+	}
+
+	if *baselinePath == "" {
+		return
+	}
+	base, err := service.LoadBaselineFile(*baselinePath)
+	if err != nil {
+		log.Fatalf("load baseline: %v", err)
+	}
+	rep := service.CompareToBaseline(res.Detections, base)
+	fmt.Printf("Baseline: %d new, %d existing, %d resolved\n", len(rep.New), len(rep.Existing), len(rep.Resolved))
+	for _, d := range rep.New {
+		fmt.Printf(" NEW [%s] %s: %s\n", d.Kind, d.Title, d.Summary)
+	}
+	if rep.Failed {
+		os.Exit(1)
+	}
+}
+
+func printDrift(rep *service.DriftReport) {
+	if !rep.Drifted {
+		fmt.Println("Drift: none, the architecture matches the design")
+		return
+	}
+	fmt.Printf("Drift: %d missing / %d unexpected nodes, %d missing / %d unexpected edges, %d sync mismatches\n",
+		len(rep.MissingNodes), len(rep.UnexpectedNodes), len(rep.MissingEdges), len(rep.UnexpectedEdges), len(rep.SyncMismatches))
+	for _, n := range rep.MissingNodes {
+		fmt.Printf(" MISSING node %s\n", n)
+	}
+	for _, n := range rep.UnexpectedNodes {
+		fmt.Printf(" UNEXPECTED node %s\n", n)
+	}
+	for _, e := range rep.MissingEdges {
+		fmt.Printf(" MISSING %s -> %s\n", e.From, e.To)
+	}
+	for _, e := range rep.UnexpectedEdges {
+		fmt.Printf(" UNEXPECTED %s -> %s\n", e.From, e.To)
+	}
+	for _, e := range rep.SyncMismatches {
+		fmt.Printf(" SYNC %s -> %s: designed sync=%t, observed sync=%t\n", e.From, e.To, *e.DesignedSync, *e.ObservedSync)
+	}
+}
+
+// analyzeImported converts path from format to AMG/APD YAML, keeps it as outDir/spec.yaml and analyzes it.
+// path may be a directory; its .yaml, .yml and .json files are imported together.
+func analyzeImported(format, path, outDir, title string, cfg *detection.DetectorConfig) (*service.Result, error) {
+	b, err := readImportInput(path)
+	if err != nil {
+		return nil, err
+	}
+	y, err := service.ImportToYAML(format, b)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(outDir, "spec.yaml"), y, 0644); err != nil {
+		return nil, err
+	}
+	return service.AnalyzeYAMLBytesToDir(y, outDir, title, os.Getenv("DOT_BIN"), cfg)
+}
+
+// loadDetectorConfig reads a detector config JSON file (nil for the built-in defaults when path is "").
+func loadDetectorConfig(path string) (*detection.DetectorConfig, error) {
+	if path == "" {
+		return nil, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &detection.DetectorConfig{}
+	if err := json.Unmarshal(b, cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func readImportInput(path string) ([]byte, error) {
+	st, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !st.IsDir() {
+		return os.ReadFile(path)
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var docs [][]byte
+	for _, e := range entries {
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".yaml", ".yml", ".json":
+		default:
+			continue
+		}
+		if e.IsDir() {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(path, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, b)
+	}
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("%s: no .yaml, .yml or .json files", path)
+	}
+	return service.JoinDocuments(docs), nil
 }
@@ -7,7 +7,7 @@ import (
 
 func main() {
 	if len(os.Args) < 2 {
-		log.Fatal("usage: worker analyze <yamlPath> [outDir] [title]")
+		log.Fatal("usage: worker analyze [-input compose|kubernetes|otlp|api-docs] [-designed spec.yaml] [-baseline analysis.json] [-format mermaid,graphml,...] <path> [outDir] [title]")
 	}
 
 	switch os.Args[1] {
@@ -1,9 +1,18 @@
 package amg_apd
 
+import "github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection"
+
+// SuggestionPreviewRequest is the body of POST /suggestions. The suggestions come back in
+// severity order, which "idx:N" selections refer to; each has an impact whose rank orders them
+// by benefit. Sort by impact.rank client-side, or pass ?sort=benefit and select by id.
 type SuggestionPreviewRequest struct {
 	YAML   string `json:"yaml"`
 	Title  string `json:"title,omitempty"`
 	OutDir string `json:"out_dir,omitempty"`
+	// SelectedSuggestionIDs limits the returned plan to these suggestions (all when empty).
+	SelectedSuggestionIDs []string `json:"selected_suggestion_ids,omitempty"`
+	// DetectorConfig overrides the project's stored detector config for this request.
+	DetectorConfig *detection.DetectorConfig `json:"detector_config,omitempty"`
 }
 
 type SuggestionApplyRequest struct {
@@ -12,4 +21,29 @@ type SuggestionApplyRequest struct {
 	Title                 string   `json:"title,omitempty"`
 	OutDir                string   `json:"out_dir,omitempty"`
 	SelectedSuggestionIDs []string `json:"selected_suggestion_ids,omitempty"`
+	// DetectorConfig overrides the project's stored detector config for this request.
+	DetectorConfig *detection.DetectorConfig `json:"detector_config,omitempty"`
+}
+
+type SuggestionUndoRequest struct {
+	JobID string `json:"job_id,omitempty"`
+	// VersionID is the auto_fix version and FixID the applied suggestion id to revert in it.
+	VersionID string `json:"version_id"`
+	FixID     string `json:"fix_id"`
+	Title     string `json:"title,omitempty"`
+	OutDir    string `json:"out_dir,omitempty"`
+	// DetectorConfig overrides the project's stored detector config for this request.
+	DetectorConfig *detection.DetectorConfig `json:"detector_config,omitempty"`
+}
+
+type SuggestionReplayRequest struct {
+	JobID string `json:"job_id,omitempty"`
+	// VersionID and FixID name the stored fix; YAML is the architecture to apply it to.
+	VersionID string `json:"version_id"`
+	FixID     string `json:"fix_id"`
+	YAML      string `json:"yaml"`
+	Title     string `json:"title,omitempty"`
+	OutDir    string `json:"out_dir,omitempty"`
+	// DetectorConfig overrides the project's stored detector config for this request.
+	DetectorConfig *detection.DetectorConfig `json:"detector_config,omitempty"`
 }
@@ -1 +1,31 @@
-package amg_apd
\ No newline at end of file
+package amg_apd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/validator"
+)
+
+// specIssues returns the validation issues behind an analysis error, if that is what failed.
+func specIssues(err error) validator.Issues {
+	var issues validator.Issues
+	if errors.As(err, &issues) {
+		return issues
+	}
+	return nil
+}
+
+// analyzeFailed reports an analysis error. Invalid YAML is answered with JSON listing every
+// issue with its path and line/column so the editor can underline it; other failures keep
+// the plain-text response.
+func analyzeFailed(c *gin.Context, err error) {
+	if issues := specIssues(err); issues != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid yaml", "details": err.Error(), "issues": issues})
+		return
+	}
+	c.String(http.StatusBadRequest, fmt.Sprintf("analyze failed: %v", err))
+}
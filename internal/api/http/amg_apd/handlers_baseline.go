@@ -0,0 +1,132 @@
+package amg_apd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/service"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/storage/amg_apd_version"
+)
+
+type baselineReq struct {
+	VersionID string `json:"version_id"`
+}
+
+// loadBaselineDetections returns the stored detections of a baseline version (nil row = not found).
+func (h *Handlers) loadBaselineDetections(userID, projectPublicID, versionID string) ([]domain.Detection, bool, error) {
+	row, err := h.versionRepo.GetByIDForUserProject(versionID, userID, projectPublicID)
+	if err != nil || row == nil {
+		return nil, false, err
+	}
+	var g domain.Graph
+	var dets []domain.Detection
+	if err := amg_apd_version.ParseGraphAndDetections(row, &g, &dets); err != nil {
+		return nil, true, fmt.Errorf("baseline version %s has invalid detections: %w", versionID, err)
+	}
+	return dets, true, nil
+}
+
+// baselineReport compares dets with the baseline given by ?baseline_version_id=..., or the project's
+// stored baseline. Returns nil when neither is set, or when the stored baseline's version no
+// longer exists (only an explicitly requested version that is missing is an error).
+func (h *Handlers) baselineReport(c *gin.Context, userID, projectPublicID string, dets []domain.Detection) (*service.BaselineReport, error) {
+	versionID := strings.TrimSpace(c.Query("baseline_version_id"))
+	explicit := versionID != ""
+	if !explicit {
+		row, err := h.versionRepo.GetBaseline(userID, projectPublicID)
+		if err != nil || row == nil {
+			return nil, err
+		}
+		versionID = row.VersionID
+	}
+	base, found, err := h.loadBaselineDetections(userID, projectPublicID, versionID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		if !explicit {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("baseline version %s not found", versionID)
+	}
+	rep := service.CompareToBaseline(dets, base)
+	rep.BaselineVersionID = versionID
+	return rep, nil
+}
+
+// GetBaseline returns the version a project's analyses are compared against.
+func (h *Handlers) GetBaseline(c *gin.Context) {
+	projectPublicID := c.Param("project_public_id")
+	if projectPublicID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "project_public_id is required"})
+		return
+	}
+	row, err := h.versionRepo.GetBaseline(getUserID(c), projectPublicID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load baseline", "details": err.Error()})
+		return
+	}
+	if row == nil {
+		c.JSON(http.StatusOK, gin.H{"project_public_id": projectPublicID, "version_id": nil})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"project_public_id": projectPublicID,
+		"version_id":        row.VersionID,
+		"updated_at":        row.UpdatedAt,
+	})
+}
+
+// PutBaseline pins an existing version of the project as its baseline.
+func (h *Handlers) PutBaseline(c *gin.Context) {
+	projectPublicID := c.Param("project_public_id")
+	if projectPublicID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "project_public_id is required"})
+		return
+	}
+	var req baselineReq
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.VersionID) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "version_id is required"})
+		return
+	}
+	userID := getUserID(c)
+	dets, found, err := h.loadBaselineDetections(userID, projectPublicID, req.VersionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load version", "details": err.Error()})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "version not found"})
+		return
+	}
+	row, err := h.versionRepo.SetBaseline(userID, projectPublicID, req.VersionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save baseline", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"project_public_id":   projectPublicID,
+		"version_id":          row.VersionID,
+		"baseline_detections": len(dets),
+		"updated_at":          row.UpdatedAt,
+	})
+}
+
+// DeleteBaseline clears the project's baseline so every detection is reported again.
+func (h *Handlers) DeleteBaseline(c *gin.Context) {
+	projectPublicID := c.Param("project_public_id")
+	if projectPublicID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "project_public_id is required"})
+		return
+	}
+	ok, err := h.versionRepo.DeleteBaseline(getUserID(c), projectPublicID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete baseline", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "deleted": ok})
+}
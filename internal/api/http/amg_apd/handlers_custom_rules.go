@@ -0,0 +1,327 @@
+package amg_apd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/service"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/storage/amg_apd_version"
+)
+
+type customRuleView struct {
+	Scope     string               `json:"scope"`
+	Rule      detection.CustomRule `json:"rule"`
+	UpdatedAt time.Time            `json:"updated_at"`
+}
+
+type testCustomRuleReq struct {
+	Rule detection.CustomRule `json:"rule"`
+	YAML string               `json:"yaml,omitempty"`
+}
+
+// mergeCustomRules combines rule layers; a later layer replaces an earlier rule of the same kind.
+func mergeCustomRules(layers ...[]detection.CustomRule) []detection.CustomRule {
+	var out []detection.CustomRule
+	at := map[domain.AntiPatternKind]int{}
+	for _, layer := range layers {
+		for _, r := range layer {
+			if i, ok := at[r.Kind]; ok {
+				out[i] = r
+				continue
+			}
+			at[r.Kind] = len(out)
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func decodeCustomRules(rows []amg_apd_version.CustomRuleRow) ([]detection.CustomRule, error) {
+	out := make([]detection.CustomRule, 0, len(rows))
+	for _, row := range rows {
+		var r detection.CustomRule
+		if err := json.Unmarshal(row.RuleJSON, &r); err != nil {
+			return nil, fmt.Errorf("stored custom rule %q is invalid: %w", row.Kind, err)
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+// errNotOrgMember is returned when the caller names an organization they do not belong to.
+var errNotOrgMember = errors.New("caller is not a member of the organization")
+
+// checkOrgMember returns errNotOrgMember unless userID belongs to orgID; an empty orgID passes.
+func (h *Handlers) checkOrgMember(orgID, userID string) error {
+	if orgID == "" {
+		return nil
+	}
+	ok, err := h.versionRepo.IsOrgMember(userID, orgID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errNotOrgMember
+	}
+	return nil
+}
+
+// requireOrgMember writes 403 (or 500) and returns false unless the caller belongs to orgID.
+func (h *Handlers) requireOrgMember(c *gin.Context, orgID string) bool {
+	err := h.checkOrgMember(orgID, getUserID(c))
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, errNotOrgMember):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error(), "org_id": orgID})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check organization membership", "details": err.Error()})
+	}
+	return false
+}
+
+// memberOrgCustomRules returns the rules of orgID when userID is a member of it, else none.
+func (h *Handlers) memberOrgCustomRules(orgID, userID string) ([]amg_apd_version.CustomRuleRow, error) {
+	err := h.checkOrgMember(orgID, userID)
+	if errors.Is(err, errNotOrgMember) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return h.versionRepo.ListOrgCustomRules(orgID)
+}
+
+// storedCustomRules returns the org's rules overridden by the project's. The org's rules are only
+// used when userID is a member of it.
+func (h *Handlers) storedCustomRules(orgID, userID, projectPublicID string) ([]detection.CustomRule, error) {
+	orgRows, err := h.memberOrgCustomRules(orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	projRows, err := h.versionRepo.ListProjectCustomRules(userID, projectPublicID)
+	if err != nil {
+		return nil, err
+	}
+	orgRules, err := decodeCustomRules(orgRows)
+	if err != nil {
+		return nil, err
+	}
+	projRules, err := decodeCustomRules(projRows)
+	if err != nil {
+		return nil, err
+	}
+	return mergeCustomRules(orgRules, projRules), nil
+}
+
+func customRuleViews(scope string, rows []amg_apd_version.CustomRuleRow) ([]customRuleView, error) {
+	rules, err := decodeCustomRules(rows)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]customRuleView, 0, len(rows))
+	for i, r := range rules {
+		out = append(out, customRuleView{Scope: scope, Rule: r, UpdatedAt: rows[i].UpdatedAt})
+	}
+	return out, nil
+}
+
+// bindCustomRule reads and validates the rule in the body; the :kind path param wins over the body's kind.
+func bindCustomRule(c *gin.Context) (*detection.CustomRule, []byte, bool) {
+	var r detection.CustomRule
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body", "details": err.Error()})
+		return nil, nil, false
+	}
+	if kind := strings.TrimSpace(c.Param("kind")); kind != "" {
+		r.Kind = domain.AntiPatternKind(kind)
+	}
+	if _, err := r.Compile(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid custom rule", "details": err.Error()})
+		return nil, nil, false
+	}
+	b, err := json.Marshal(r)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to encode custom rule", "details": err.Error()})
+		return nil, nil, false
+	}
+	return &r, b, true
+}
+
+// ListProjectCustomRules returns the project's rules, the caller's org rules (X-Org-Id, only for
+// a member) and the effective set analyses use.
+func (h *Handlers) ListProjectCustomRules(c *gin.Context) {
+	projectPublicID := c.Param("project_public_id")
+	if projectPublicID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "project_public_id is required"})
+		return
+	}
+	userID, orgID := getUserID(c), getOrgID(c)
+	projRows, err := h.versionRepo.ListProjectCustomRules(userID, projectPublicID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list custom rules", "details": err.Error()})
+		return
+	}
+	orgRows, err := h.memberOrgCustomRules(orgID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list custom rules", "details": err.Error()})
+		return
+	}
+	views, err := customRuleViews("org", orgRows)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to decode custom rules", "details": err.Error()})
+		return
+	}
+	projViews, err := customRuleViews("project", projRows)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to decode custom rules", "details": err.Error()})
+		return
+	}
+	views = append(views, projViews...)
+	rules := make([]detection.CustomRule, 0, len(views))
+	for _, v := range views {
+		rules = append(rules, v.Rule)
+	}
+	effective := mergeCustomRules(rules) // project rules come last and win
+	c.JSON(http.StatusOK, gin.H{"project_public_id": projectPublicID, "rules": views, "effective": effective})
+}
+
+// PutProjectCustomRule validates and stores a rule for the project.
+func (h *Handlers) PutProjectCustomRule(c *gin.Context) {
+	projectPublicID := c.Param("project_public_id")
+	if projectPublicID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "project_public_id is required"})
+		return
+	}
+	r, b, ok := bindCustomRule(c)
+	if !ok {
+		return
+	}
+	row, err := h.versionRepo.UpsertProjectCustomRule(getUserID(c), projectPublicID, string(r.Kind), b)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save custom rule", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"project_public_id": projectPublicID, "rule": r, "updated_at": row.UpdatedAt})
+}
+
+// DeleteProjectCustomRule removes one of the project's rules.
+func (h *Handlers) DeleteProjectCustomRule(c *gin.Context) {
+	projectPublicID := c.Param("project_public_id")
+	kind := c.Param("kind")
+	if projectPublicID == "" || kind == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "project_public_id and kind are required"})
+		return
+	}
+	ok, err := h.versionRepo.DeleteProjectCustomRule(getUserID(c), projectPublicID, kind)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete custom rule", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "deleted": ok})
+}
+
+// ListOrgCustomRules returns the rules shared by every project of an organization. The org routes
+// are limited to its members (users.organization).
+func (h *Handlers) ListOrgCustomRules(c *gin.Context) {
+	orgID := c.Param("org_id")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "org_id is required"})
+		return
+	}
+	if !h.requireOrgMember(c, orgID) {
+		return
+	}
+	rows, err := h.versionRepo.ListOrgCustomRules(orgID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list custom rules", "details": err.Error()})
+		return
+	}
+	views, err := customRuleViews("org", rows)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to decode custom rules", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"org_id": orgID, "rules": views})
+}
+
+// PutOrgCustomRule validates and stores a rule for an organization.
+func (h *Handlers) PutOrgCustomRule(c *gin.Context) {
+	orgID := c.Param("org_id")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "org_id is required"})
+		return
+	}
+	if !h.requireOrgMember(c, orgID) {
+		return
+	}
+	r, b, ok := bindCustomRule(c)
+	if !ok {
+		return
+	}
+	row, err := h.versionRepo.UpsertOrgCustomRule(orgID, string(r.Kind), b)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save custom rule", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"org_id": orgID, "rule": r, "updated_at": row.UpdatedAt})
+}
+
+// DeleteOrgCustomRule removes one of the organization's rules.
+func (h *Handlers) DeleteOrgCustomRule(c *gin.Context) {
+	orgID := c.Param("org_id")
+	kind := c.Param("kind")
+	if orgID == "" || kind == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "org_id and kind are required"})
+		return
+	}
+	if !h.requireOrgMember(c, orgID) {
+		return
+	}
+	ok, err := h.versionRepo.DeleteOrgCustomRule(orgID, kind)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete custom rule", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "deleted": ok})
+}
+
+// TestCustomRule validates a rule and, when yaml is sent, returns what it would detect there.
+// Nothing is stored.
+func TestCustomRule(c *gin.Context) {
+	var req testCustomRuleReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body", "details": err.Error()})
+		return
+	}
+	if _, err := req.Rule.Compile(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid custom rule", "details": err.Error()})
+		return
+	}
+	if strings.TrimSpace(req.YAML) == "" {
+		c.JSON(http.StatusOK, gin.H{"valid": true})
+		return
+	}
+	cfg := &detection.DetectorConfig{CustomRules: []detection.CustomRule{req.Rule}}
+	res, _, err := service.AnalyzeYAMLBytesInMemory([]byte(req.YAML), "Custom rule test", os.Getenv("DOT_BIN"), cfg)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "analyze failed", "details": err.Error(), "issues": specIssues(err)})
+		return
+	}
+	matches := []domain.Detection{}
+	for _, d := range res.Detections {
+		if d.Kind == req.Rule.Kind {
+			matches = append(matches, d)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"valid": true, "detections": matches})
+}
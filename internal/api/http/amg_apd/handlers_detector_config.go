@@ -0,0 +1,162 @@
+package amg_apd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/service"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/storage/amg_apd_version"
+)
+
+// resolveDetectorConfig returns the detector config for an analysis: the inline config when the
+// request sends one, otherwise the config stored for the project (nil = built-in defaults).
+// The org's and project's stored custom rules are added in either case.
+func (h *Handlers) resolveDetectorConfig(orgID, userID, projectPublicID string, inline *detection.DetectorConfig) (*detection.DetectorConfig, error) {
+	stored, err := h.storedCustomRules(orgID, userID, projectPublicID)
+	if err != nil {
+		return nil, err
+	}
+	var cfg *detection.DetectorConfig
+	if inline != nil {
+		if err := inline.ValidateWith(stored); err != nil {
+			return nil, err
+		}
+		cfg = inline
+	} else {
+		row, err := h.versionRepo.GetDetectorConfig(userID, projectPublicID)
+		if err != nil {
+			return nil, err
+		}
+		if row != nil {
+			cfg = &detection.DetectorConfig{}
+			if err := json.Unmarshal(row.ConfigJSON, cfg); err != nil {
+				return nil, fmt.Errorf("stored detector config is invalid: %w", err)
+			}
+		}
+	}
+	if len(stored) == 0 {
+		return cfg, nil
+	}
+	merged := &detection.DetectorConfig{}
+	if cfg != nil {
+		*merged = *cfg
+	}
+	merged.CustomRules = mergeCustomRules(stored, merged.CustomRules)
+	return merged, nil
+}
+
+// saveExtrasFor records the effective detector config and graph metrics next to the saved version.
+func saveExtrasFor(res *service.Result) *amg_apd_version.SaveExtras {
+	if res == nil {
+		return nil
+	}
+	extras := &amg_apd_version.SaveExtras{}
+	if res.DetectorConfig != nil {
+		if b, err := json.Marshal(res.DetectorConfig); err == nil {
+			extras.DetectorConfigJSON = b
+		}
+	}
+	if res.Metrics != nil {
+		if b, err := json.Marshal(res.Metrics); err == nil {
+			extras.MetricsJSON = b
+		}
+	}
+	return extras
+}
+
+// GetDetectorConfig returns the stored detector config for a project plus the effective config it resolves to.
+func (h *Handlers) GetDetectorConfig(c *gin.Context) {
+	projectPublicID := c.Param("project_public_id")
+	if projectPublicID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "project_public_id is required"})
+		return
+	}
+	userID := getUserID(c)
+	row, err := h.versionRepo.GetDetectorConfig(userID, projectPublicID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load detector config", "details": err.Error()})
+		return
+	}
+	var stored *detection.DetectorConfig
+	if row != nil {
+		stored = &detection.DetectorConfig{}
+		if err := json.Unmarshal(row.ConfigJSON, stored); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "stored detector config is invalid", "details": err.Error()})
+			return
+		}
+	}
+	resp := gin.H{
+		"project_public_id": projectPublicID,
+		"config":            stored,
+		"effective":         detection.Effective(stored),
+	}
+	if row != nil {
+		resp["updated_at"] = row.UpdatedAt
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// PutDetectorConfig validates and stores the detector config for a project. Its rules may tune
+// the project's and the caller's org custom rules, which are stored with the custom-rules routes.
+func (h *Handlers) PutDetectorConfig(c *gin.Context) {
+	projectPublicID := c.Param("project_public_id")
+	if projectPublicID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "project_public_id is required"})
+		return
+	}
+	var cfg detection.DetectorConfig
+	if err := c.ShouldBindJSON(&cfg); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body", "details": err.Error()})
+		return
+	}
+	if len(cfg.CustomRules) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid detector config", "details": "custom rules are stored with PUT /projects/:project_public_id/custom-rules/:kind"})
+		return
+	}
+	userID := getUserID(c)
+	stored, err := h.storedCustomRules(getOrgID(c), userID, projectPublicID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load custom rules", "details": err.Error()})
+		return
+	}
+	if err := cfg.ValidateWith(stored); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid detector config", "details": err.Error()})
+		return
+	}
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to encode detector config", "details": err.Error()})
+		return
+	}
+	row, err := h.versionRepo.UpsertDetectorConfig(userID, projectPublicID, b)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save detector config", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"project_public_id": projectPublicID,
+		"config":            &cfg,
+		"effective":         detection.Effective(&cfg),
+		"updated_at":        row.UpdatedAt,
+	})
+}
+
+// DeleteDetectorConfig removes the stored config so the project falls back to defaults.
+func (h *Handlers) DeleteDetectorConfig(c *gin.Context) {
+	projectPublicID := c.Param("project_public_id")
+	if projectPublicID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "project_public_id is required"})
+		return
+	}
+	userID := getUserID(c)
+	ok, err := h.versionRepo.DeleteDetectorConfig(userID, projectPublicID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete detector config", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "deleted": ok})
+}
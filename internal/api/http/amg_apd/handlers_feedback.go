@@ -1 +1,151 @@
-package amg_apd
\ No newline at end of file
+package amg_apd
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/feedback"
+)
+
+type feedbackReq struct {
+	DetectionKey string            `json:"detection_key,omitempty"`
+	Detection    *domain.Detection `json:"detection,omitempty"`
+	Status       feedback.Status   `json:"status"`
+	Reason       string            `json:"reason,omitempty"`
+	ExpiresAt    *time.Time        `json:"expires_at,omitempty"`
+	VersionID    string            `json:"version_id,omitempty"`
+}
+
+// hideDismissed reports whether the caller asked to drop false positives / accepted risks (?hide_dismissed=true).
+func hideDismissed(c *gin.Context) bool {
+	v := strings.ToLower(strings.TrimSpace(c.Query("hide_dismissed")))
+	return v == "1" || v == "true" || v == "yes"
+}
+
+// withFeedback annotates dets with the project's feedback (and hides dismissed ones when asked).
+// Feedback is best-effort: on a store error the detections are returned unchanged.
+func (h *Handlers) withFeedback(c *gin.Context, userID, projectPublicID string, dets []domain.Detection) ([]domain.Detection, int) {
+	if h.feedback == nil {
+		return dets, 0
+	}
+	out, hidden, err := h.feedback.Annotate(userID, projectPublicID, dets, hideDismissed(c))
+	if err != nil {
+		return dets, 0
+	}
+	return out, hidden
+}
+
+// withFeedbackJSON is withFeedback for detections still in stored JSON form.
+func (h *Handlers) withFeedbackJSON(c *gin.Context, userID, projectPublicID string, detectionsJSON []byte) (interface{}, int) {
+	var dets []domain.Detection
+	if err := json.Unmarshal(detectionsJSON, &dets); err != nil {
+		var raw interface{}
+		_ = json.Unmarshal(detectionsJSON, &raw)
+		return raw, 0
+	}
+	return h.withFeedback(c, userID, projectPublicID, dets)
+}
+
+// ListFeedback returns all detection feedback recorded for a project.
+func (h *Handlers) ListFeedback(c *gin.Context) {
+	projectPublicID := c.Param("project_public_id")
+	if projectPublicID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "project_public_id is required"})
+		return
+	}
+	list, err := h.feedback.List(getUserID(c), projectPublicID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list feedback", "details": err.Error()})
+		return
+	}
+	if list == nil {
+		list = []feedback.Feedback{}
+	}
+	c.JSON(http.StatusOK, gin.H{"project_public_id": projectPublicID, "feedback": list})
+}
+
+// PutFeedback records feedback for one detection (by detection_key or the detection itself).
+func (h *Handlers) PutFeedback(c *gin.Context) {
+	projectPublicID := c.Param("project_public_id")
+	if projectPublicID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "project_public_id is required"})
+		return
+	}
+	var req feedbackReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body", "details": err.Error()})
+		return
+	}
+	saved, err := h.feedback.Record(feedback.Feedback{
+		UserID:          getUserID(c),
+		ProjectPublicID: projectPublicID,
+		DetectionKey:    req.DetectionKey,
+		Detection:       req.Detection,
+		Status:          req.Status,
+		Reason:          req.Reason,
+		ExpiresAt:       req.ExpiresAt,
+		VersionID:       req.VersionID,
+	})
+	if errors.Is(err, feedback.ErrInvalid) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid feedback", "details": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save feedback", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"feedback": saved})
+}
+
+// DeleteFeedback removes the feedback for ?detection_key=... so the detection shows normally again.
+func (h *Handlers) DeleteFeedback(c *gin.Context) {
+	projectPublicID := c.Param("project_public_id")
+	key := strings.TrimSpace(c.Query("detection_key"))
+	if projectPublicID == "" || key == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "project_public_id and detection_key are required"})
+		return
+	}
+	ok, err := h.feedback.Remove(getUserID(c), projectPublicID, key)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete feedback", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "deleted": ok})
+}
+
+// ExportFeedback exports the project's feedback as labelled training examples.
+// ?format=jsonl streams one example per line; default is a JSON array.
+func (h *Handlers) ExportFeedback(c *gin.Context) {
+	projectPublicID := c.Param("project_public_id")
+	if projectPublicID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "project_public_id is required"})
+		return
+	}
+	examples, err := h.feedback.TrainingData(getUserID(c), projectPublicID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to export feedback", "details": err.Error()})
+		return
+	}
+	if strings.EqualFold(c.Query("format"), "jsonl") {
+		var b strings.Builder
+		for _, ex := range examples {
+			line, err := json.Marshal(ex)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to encode feedback", "details": err.Error()})
+				return
+			}
+			b.Write(line)
+			b.WriteByte('\n')
+		}
+		c.Header("Content-Disposition", `attachment; filename="`+projectPublicID+`-feedback.jsonl"`)
+		c.Data(http.StatusOK, "application/x-ndjson", []byte(b.String()))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"project_public_id": projectPublicID, "examples": examples})
+}
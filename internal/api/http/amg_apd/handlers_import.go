@@ -0,0 +1,132 @@
+package amg_apd
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/service"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/storage/amg_apd_version"
+)
+
+type importReq struct {
+	// Format is the source format (see service.ImportFormats), e.g. "compose".
+	Format  string `json:"format"`
+	Content string `json:"content"`
+	// Documents is an alternative to Content for formats made of several files (one OpenAPI
+	// or AsyncAPI document per service, Kubernetes manifests).
+	Documents []string `json:"documents,omitempty"`
+	Title     string   `json:"title"`
+	// Save stores the converted YAML and its analysis as a new version.
+	Save                 bool                      `json:"save"`
+	MergePreviousDiagram *bool                     `json:"merge_previous_diagram,omitempty"`
+	DetectorConfig       *detection.DetectorConfig `json:"detector_config,omitempty"`
+	// CompareLatest diffs the imported (observed) architecture against the project's latest
+	// version and returns the result as "drift".
+	CompareLatest bool `json:"compare_latest"`
+}
+
+// ImportArchitecture converts a foreign description (docker-compose, OpenAPI, ...) to AMG/APD
+// YAML and analyzes it. With save=true the result is stored as a version like analyze-raw; with
+// compare_latest=true the response includes drift against the latest stored design.
+func (h *Handlers) ImportArchitecture(c *gin.Context) {
+	var req importReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body", "details": err.Error()})
+		return
+	}
+	if strings.TrimSpace(req.Content) == "" && len(req.Documents) > 0 {
+		docs := make([][]byte, len(req.Documents))
+		for i, d := range req.Documents {
+			docs[i] = []byte(d)
+		}
+		req.Content = string(service.JoinDocuments(docs))
+	}
+	if strings.TrimSpace(req.Content) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "content or documents is required"})
+		return
+	}
+	if req.Title == "" {
+		req.Title = "Imported"
+	}
+	yamlBytes, err := service.ImportToYAML(req.Format, []byte(req.Content))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "import failed", "details": err.Error(), "formats": service.ImportFormats()})
+		return
+	}
+	userID := getUserID(c)
+	chatID := getChatID(c)
+	cfg, err := h.resolveDetectorConfig(getOrgID(c), userID, chatID, req.DetectorConfig)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid detector config", "details": err.Error()})
+		return
+	}
+	res, dotContent, err := service.AnalyzeYAMLBytesInMemory(yamlBytes, req.Title, os.Getenv("DOT_BIN"), cfg)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "analyze failed", "details": err.Error(), "yaml": string(yamlBytes), "issues": specIssues(err)})
+		return
+	}
+	detections, hidden := h.withFeedback(c, userID, chatID, res.Detections)
+	out := gin.H{
+		"yaml":              string(yamlBytes),
+		"graph":             res.Graph,
+		"detections":        detections,
+		"hidden_detections": hidden,
+		"dot_content":       dotContent,
+		"detector_config":   res.DetectorConfig,
+		"metrics":           res.Metrics,
+		"suppressed":        res.Suppressed,
+		"issues":            res.Issues,
+	}
+	if req.CompareLatest {
+		// Compare before saving, otherwise the latest version is the import itself.
+		drift, err := h.driftAgainstLatest(userID, chatID, res.Graph)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load latest version", "details": err.Error()})
+			return
+		}
+		out["drift"] = drift
+	}
+	if !req.Save {
+		c.JSON(http.StatusOK, out)
+		return
+	}
+	graphJSON, _ := json.Marshal(res.Graph)
+	detectionsJSON, _ := json.Marshal(res.Detections)
+	mergePrev := true
+	if req.MergePreviousDiagram != nil {
+		mergePrev = *req.MergePreviousDiagram
+	}
+	row, err := h.versionRepo.Save(userID, chatID, req.Title, string(yamlBytes), graphJSON, detectionsJSON, dotContent, mergePrev, saveExtrasFor(res))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save version", "details": err.Error()})
+		return
+	}
+	out["version_id"] = row.ID
+	out["version_number"] = row.VersionNumber
+	out["created_at"] = row.CreatedAt
+	c.JSON(http.StatusOK, out)
+}
+
+// driftAgainstLatest compares observed with the latest version of the project; nil when the
+// project has no version yet.
+func (h *Handlers) driftAgainstLatest(userID, projectID string, observed *domain.Graph) (*service.DriftReport, error) {
+	row, err := h.versionRepo.GetLatestByUserProject(userID, projectID)
+	if err != nil || row == nil {
+		return nil, err
+	}
+	var designed domain.Graph
+	var dets []domain.Detection
+	if err := amg_apd_version.ParseGraphAndDetections(row, &designed, &dets); err != nil {
+		return nil, err
+	}
+	designed.RebuildOutIn()
+	rep := service.CompareArchitectures(&designed, observed)
+	rep.DesignedVersionID = row.ID
+	return rep, nil
+}
@@ -0,0 +1,137 @@
+package amg_apd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/service"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/spec"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/versioning"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/storage/amg_apd_version"
+)
+
+type mergeReq struct {
+	// BaseID is the common ancestor; OursID and TheirsID are the two versions edited from it.
+	BaseID   string `json:"base_id"`
+	OursID   string `json:"ours_id"`
+	TheirsID string `json:"theirs_id"`
+	// Resolutions settles conflicts returned by an earlier call: conflict path -> ours/theirs/base.
+	Resolutions map[string]versioning.Resolution `json:"resolutions,omitempty"`
+	Title       string                           `json:"title"`
+	// Save stores the merged YAML and its analysis as a new version with both parents recorded.
+	Save                 bool                      `json:"save"`
+	MergePreviousDiagram *bool                     `json:"merge_previous_diagram,omitempty"`
+	DetectorConfig       *detection.DetectorConfig `json:"detector_config,omitempty"`
+}
+
+// MergeVersions three-way merges the YAML of two versions edited from a common base. Changes to
+// different services, dependencies or fields merge automatically; overlapping changes are
+// returned as "conflicts" with 409 (the "yaml" preview keeps ours for them) until resolutions
+// are given. A clean merge is analyzed and, with save=true, stored as a new version.
+func (h *Handlers) MergeVersions(c *gin.Context) {
+	var req mergeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body", "details": err.Error()})
+		return
+	}
+	if req.BaseID == "" || req.OursID == "" || req.TheirsID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "base_id, ours_id and theirs_id are required"})
+		return
+	}
+	userID := getUserID(c)
+	chatID := getChatID(c)
+
+	var rows [3]*amg_apd_version.VersionRow
+	var specs [3]*parser.YSpec
+	for i, side := range []struct{ name, id string }{{"base", req.BaseID}, {"ours", req.OursID}, {"theirs", req.TheirsID}} {
+		row, err := h.versionRepo.GetByIDForUserChat(side.id, userID, chatID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to get %s version", side.name), "details": err.Error()})
+			return
+		}
+		if row == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("%s version not found", side.name)})
+			return
+		}
+		ys, err := parser.ParseYAMLString(row.YAMLContent)
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": fmt.Sprintf("%s version has invalid yaml", side.name), "details": err.Error()})
+			return
+		}
+		rows[i], specs[i] = row, ys
+	}
+
+	merged, err := versioning.MergeSpecs(specs[0], specs[1], specs[2], req.Resolutions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "merge failed", "details": err.Error()})
+		return
+	}
+	// Patch ours' document rather than re-encoding the spec so its comments, key order and keys
+	// YSpec does not know survive the merge.
+	yamlBytes, err := spec.PatchYAML([]byte(rows[1].YAMLContent), specs[1], merged.Spec)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to encode merged yaml", "details": err.Error()})
+		return
+	}
+	out := gin.H{
+		"yaml":      string(yamlBytes),
+		"conflicts": merged.Conflicts,
+		"resolved":  merged.Resolved,
+	}
+	if len(merged.Conflicts) > 0 {
+		out["error"] = "merge has conflicts"
+		c.JSON(http.StatusConflict, out)
+		return
+	}
+
+	if req.Title == "" {
+		req.Title = fmt.Sprintf("Merge of v%d and v%d", rows[1].VersionNumber, rows[2].VersionNumber)
+	}
+	cfg, err := h.resolveDetectorConfig(getOrgID(c), userID, chatID, req.DetectorConfig)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid detector config", "details": err.Error()})
+		return
+	}
+	res, dotContent, err := service.AnalyzeYAMLBytesInMemory(yamlBytes, req.Title, os.Getenv("DOT_BIN"), cfg)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "analyze failed", "details": err.Error(), "yaml": string(yamlBytes), "issues": specIssues(err)})
+		return
+	}
+	detections, hidden := h.withFeedback(c, userID, chatID, res.Detections)
+	out["graph"] = res.Graph
+	out["detections"] = detections
+	out["hidden_detections"] = hidden
+	out["dot_content"] = dotContent
+	out["detector_config"] = res.DetectorConfig
+	out["metrics"] = res.Metrics
+	out["suppressed"] = res.Suppressed
+	out["issues"] = res.Issues
+	if !req.Save {
+		c.JSON(http.StatusOK, out)
+		return
+	}
+	graphJSON, _ := json.Marshal(res.Graph)
+	detectionsJSON, _ := json.Marshal(res.Detections)
+	mergePrev := true
+	if req.MergePreviousDiagram != nil {
+		mergePrev = *req.MergePreviousDiagram
+	}
+	extras := saveExtrasFor(res)
+	extras.ParentVersionIDs = []string{rows[1].ID, rows[2].ID}
+	row, err := h.versionRepo.Save(userID, chatID, req.Title, string(yamlBytes), graphJSON, detectionsJSON, dotContent, mergePrev, extras)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save version", "details": err.Error()})
+		return
+	}
+	out["version_id"] = row.ID
+	out["version_number"] = row.VersionNumber
+	out["parent_version_ids"] = row.ParentVersionIDs
+	out["created_at"] = row.CreatedAt
+	c.JSON(http.StatusOK, out)
+}
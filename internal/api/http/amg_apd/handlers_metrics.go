@@ -0,0 +1,59 @@
+package amg_apd
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ml/features"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/storage/amg_apd_version"
+)
+
+// GetVersionMetrics returns the structural graph metrics for a version (must belong to user/chat).
+// Rows analyzed before metrics were persisted get them computed from the stored graph ("stored": false).
+func (h *Handlers) GetVersionMetrics(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "version id is required"})
+		return
+	}
+	userID := getUserID(c)
+	chatID := getChatID(c)
+	row, err := h.versionRepo.GetByIDForUserChat(id, userID, chatID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get version", "details": err.Error()})
+		return
+	}
+	if row == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "version not found"})
+		return
+	}
+
+	var metrics *features.GraphMetrics
+	stored := len(row.MetricsJSON) > 0
+	if stored {
+		metrics = &features.GraphMetrics{}
+		if err := json.Unmarshal(row.MetricsJSON, metrics); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "stored metrics are invalid", "details": err.Error()})
+			return
+		}
+	} else {
+		var graph domain.Graph
+		var detections []domain.Detection
+		if err := amg_apd_version.ParseGraphAndDetections(row, &graph, &detections); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse version", "details": err.Error()})
+			return
+		}
+		graph.RebuildOutIn()
+		metrics = features.Compute(&graph)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"version_id":     row.ID,
+		"version_number": row.VersionNumber,
+		"metrics":        metrics,
+		"stored":         stored,
+	})
+}
@@ -30,6 +30,11 @@ func (h *Handlers) GetLatestForProject(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load latest version", "details": err.Error()})
 		return
 	}
+	cfg, err := h.resolveDetectorConfig(getOrgID(c), userID, projectPublicID, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load detector config", "details": err.Error()})
+		return
+	}
 
 	if row == nil {
 		// No AMG-APD version: get latest diagram row (any source) and either update in place or create.
@@ -45,7 +50,7 @@ func (h *Handlers) GetLatestForProject(c *gin.Context) {
 		if title == "" {
 			title = "From diagram"
 		}
-		res, dotContent, errAnalyze := service.AnalyzeYAMLBytesInMemory([]byte(yamlContent), title, os.Getenv("DOT_BIN"))
+		res, dotContent, errAnalyze := service.AnalyzeYAMLBytesInMemory([]byte(yamlContent), title, os.Getenv("DOT_BIN"), cfg)
 		if errAnalyze != nil {
 			// Analysis failed; return yaml and version_id so frontend can call update-version-analysis (update in place).
 			c.JSON(http.StatusOK, gin.H{
@@ -53,6 +58,7 @@ func (h *Handlers) GetLatestForProject(c *gin.Context) {
 				"yaml_content":   yamlContent,
 				"title":          title,
 				"version_id":     diagramID,
+				"issues":         specIssues(errAnalyze),
 			})
 			return
 		}
@@ -61,52 +67,52 @@ func (h *Handlers) GetLatestForProject(c *gin.Context) {
 		if diagramID != "" {
 			// Update existing diagram row in place so we don't create a new version.
 			// YAML analyzed here is the same blob we read for this row; preserve canvas merge for editor layout.
-			if err := h.versionRepo.UpdateDiagramVersionAnalysisByID(diagramID, userID, projectPublicID, graphJSON, detectionsJSON, dotContent, yamlContent, true); err != nil {
+			if err := h.versionRepo.UpdateDiagramVersionAnalysisByID(diagramID, userID, projectPublicID, graphJSON, detectionsJSON, dotContent, yamlContent, true, saveExtrasFor(res)); err != nil {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update version analysis", "details": err.Error()})
 				return
 			}
 			updated, _ := h.versionRepo.GetByIDForUserProject(diagramID, userID, projectPublicID)
 			if updated != nil {
 				var graph interface{}
-				var detections interface{}
 				_ = json.Unmarshal(updated.GraphJSON, &graph)
-				_ = json.Unmarshal(updated.DetectionsJSON, &detections)
+				detections, hidden := h.withFeedbackJSON(c, userID, projectPublicID, updated.DetectionsJSON)
 				c.JSON(http.StatusOK, gin.H{
-					"graph":          graph,
-					"detections":     detections,
-					"dot_content":    updated.DOTContent,
-					"dot_path":       "",
-					"svg_path":       "",
-					"version_id":     updated.ID,
-					"version_number": updated.VersionNumber,
-					"created_at":     updated.CreatedAt,
-					"yaml_content":   updated.YAMLContent,
-					"title":          updated.Title,
+					"graph":             graph,
+					"detections":        detections,
+					"hidden_detections": hidden,
+					"dot_content":       updated.DOTContent,
+					"dot_path":          "",
+					"svg_path":          "",
+					"version_id":        updated.ID,
+					"version_number":    updated.VersionNumber,
+					"created_at":        updated.CreatedAt,
+					"yaml_content":      updated.YAMLContent,
+					"title":             updated.Title,
 				})
 				return
 			}
 		}
 		// No existing row id: create new AMG-APD version (legacy path).
-		row, err = h.versionRepo.Save(userID, chatID, title, yamlContent, graphJSON, detectionsJSON, dotContent, true)
+		row, err = h.versionRepo.Save(userID, chatID, title, yamlContent, graphJSON, detectionsJSON, dotContent, true, saveExtrasFor(res))
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save version", "details": err.Error()})
 			return
 		}
 		var graph interface{}
-		var detections interface{}
 		_ = json.Unmarshal(row.GraphJSON, &graph)
-		_ = json.Unmarshal(row.DetectionsJSON, &detections)
+		detections, hidden := h.withFeedbackJSON(c, userID, projectPublicID, row.DetectionsJSON)
 		c.JSON(http.StatusOK, gin.H{
-			"graph":          graph,
-			"detections":     detections,
-			"dot_content":    row.DOTContent,
-			"dot_path":       "",
-			"svg_path":       "",
-			"version_id":     row.ID,
-			"version_number": row.VersionNumber,
-			"created_at":     row.CreatedAt,
-			"yaml_content":   row.YAMLContent,
-			"title":          row.Title,
+			"graph":             graph,
+			"detections":        detections,
+			"hidden_detections": hidden,
+			"dot_content":       row.DOTContent,
+			"dot_path":          "",
+			"svg_path":          "",
+			"version_id":        row.ID,
+			"version_number":    row.VersionNumber,
+			"created_at":        row.CreatedAt,
+			"yaml_content":      row.YAMLContent,
+			"title":             row.Title,
 		})
 		return
 	}
@@ -118,7 +124,7 @@ func (h *Handlers) GetLatestForProject(c *gin.Context) {
 
 	missing := len(row.GraphJSON) == 0 || len(row.DetectionsJSON) == 0 || row.DOTContent == ""
 	if missing {
-		res, dotContent, err := service.AnalyzeYAMLBytesInMemory([]byte(row.YAMLContent), row.Title, os.Getenv("DOT_BIN"))
+		res, dotContent, err := service.AnalyzeYAMLBytesInMemory([]byte(row.YAMLContent), row.Title, os.Getenv("DOT_BIN"), cfg)
 		if err != nil {
 			// Analysis failed; return yaml and version_id so frontend can call update-version-analysis (update in place).
 			c.JSON(http.StatusOK, gin.H{
@@ -126,13 +132,14 @@ func (h *Handlers) GetLatestForProject(c *gin.Context) {
 				"yaml_content":   row.YAMLContent,
 				"title":          row.Title,
 				"version_id":     row.ID,
+				"issues":         specIssues(err),
 			})
 			return
 		}
 
 		graphJSON, _ := json.Marshal(res.Graph)
 		detectionsJSON, _ := json.Marshal(res.Detections)
-		if err := h.versionRepo.UpdateAnalysisByID(row.ID, userID, projectPublicID, graphJSON, detectionsJSON, dotContent); err != nil {
+		if err := h.versionRepo.UpdateAnalysisByID(row.ID, userID, projectPublicID, graphJSON, detectionsJSON, dotContent, saveExtrasFor(res)); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update version analysis", "details": err.Error()})
 			return
 		}
@@ -144,21 +151,20 @@ func (h *Handlers) GetLatestForProject(c *gin.Context) {
 
 	// Return same shape as analyze endpoints for frontend consumption.
 	var graph interface{}
-	var detections interface{}
 	_ = json.Unmarshal(row.GraphJSON, &graph)
-	_ = json.Unmarshal(row.DetectionsJSON, &detections)
+	detections, hidden := h.withFeedbackJSON(c, userID, projectPublicID, row.DetectionsJSON)
 
 	c.JSON(http.StatusOK, gin.H{
-		"graph":          graph,
-		"detections":     detections,
-		"dot_content":    row.DOTContent,
-		"dot_path":       "",
-		"svg_path":       "",
-		"version_id":     row.ID,
-		"version_number": row.VersionNumber,
-		"created_at":     row.CreatedAt,
-		"yaml_content":   row.YAMLContent,
-		"title":          row.Title,
+		"graph":             graph,
+		"detections":        detections,
+		"hidden_detections": hidden,
+		"dot_content":       row.DOTContent,
+		"dot_path":          "",
+		"svg_path":          "",
+		"version_id":        row.ID,
+		"version_number":    row.VersionNumber,
+		"created_at":        row.CreatedAt,
+		"yaml_content":      row.YAMLContent,
+		"title":             row.Title,
 	})
 }
-
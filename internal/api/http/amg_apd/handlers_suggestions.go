@@ -1,19 +1,30 @@
 package amg_apd
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/service"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/spec"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/suggestion"
 )
 
-func SuggestionPreview(c *gin.Context) {
+// SuggestionPreview analyzes the YAML and returns its suggestions with their fix plan and impact
+// estimates, in severity order; ?sort=benefit orders them by Impact.Rank instead.
+func (h *Handlers) SuggestionPreview(c *gin.Context) {
 	var req SuggestionPreviewRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.String(http.StatusBadRequest, "invalid json body")
 		return
 	}
+	sortBy := c.DefaultQuery("sort", "severity")
+	if sortBy != "severity" && sortBy != "benefit" {
+		c.String(http.StatusBadRequest, "sort must be severity or benefit")
+		return
+	}
 	if req.YAML == "" {
 		c.String(http.StatusBadRequest, "yaml is required")
 		return
@@ -25,16 +36,24 @@ func SuggestionPreview(c *gin.Context) {
 		req.Title = "Architecture"
 	}
 
-	res, err := service.PreviewSuggestionsYAMLString(req.YAML, req.OutDir, req.Title)
+	cfg, err := h.resolveDetectorConfig(getOrgID(c), getUserID(c), getChatID(c), req.DetectorConfig)
+	if err != nil {
+		c.String(http.StatusBadRequest, fmt.Sprintf("invalid detector config: %v", err))
+		return
+	}
+	res, err := service.PreviewSuggestionsYAMLString(req.YAML, req.OutDir, req.Title, req.SelectedSuggestionIDs, cfg)
 	if err != nil {
 		c.String(http.StatusBadRequest, "suggestion preview failed: "+err.Error())
 		return
 	}
+	if sortBy == "benefit" {
+		suggestion.SortByBenefit(res.Suggestions)
+	}
 
 	c.JSON(http.StatusOK, res)
 }
 
-func SuggestionApply(c *gin.Context) {
+func (h *Handlers) SuggestionApply(c *gin.Context) {
 	var req SuggestionApplyRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.String(http.StatusBadRequest, "invalid json body")
@@ -54,7 +73,12 @@ func SuggestionApply(c *gin.Context) {
 		req.JobID = "adhoc"
 	}
 
-	res, err := service.ApplySuggestionsYAMLString(req.JobID, req.YAML, req.OutDir, req.Title, req.SelectedSuggestionIDs)
+	cfg, err := h.resolveDetectorConfig(getOrgID(c), getUserID(c), getChatID(c), req.DetectorConfig)
+	if err != nil {
+		c.String(http.StatusBadRequest, fmt.Sprintf("invalid detector config: %v", err))
+		return
+	}
+	res, err := service.ApplySuggestionsYAMLString(req.JobID, req.YAML, req.OutDir, req.Title, req.SelectedSuggestionIDs, cfg)
 	if err != nil {
 		c.String(http.StatusBadRequest, "apply suggestions failed: "+err.Error())
 		return
@@ -62,3 +86,94 @@ func SuggestionApply(c *gin.Context) {
 
 	c.JSON(http.StatusOK, res)
 }
+
+// SuggestionUndo reverts one fix of an auto_fix version into a new undo_fix version; 409 when a
+// later fix of the same apply depends on it.
+func (h *Handlers) SuggestionUndo(c *gin.Context) {
+	var req SuggestionUndoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.String(http.StatusBadRequest, "invalid json body")
+		return
+	}
+	if req.VersionID == "" || req.FixID == "" {
+		c.String(http.StatusBadRequest, "version_id and fix_id are required")
+		return
+	}
+	if req.OutDir == "" {
+		req.OutDir = DefaultOutDir()
+	}
+	if req.Title == "" {
+		req.Title = "Architecture"
+	}
+	if req.JobID == "" {
+		req.JobID = "adhoc"
+	}
+
+	cfg, err := h.resolveDetectorConfig(getOrgID(c), getUserID(c), getChatID(c), req.DetectorConfig)
+	if err != nil {
+		c.String(http.StatusBadRequest, fmt.Sprintf("invalid detector config: %v", err))
+		return
+	}
+	res, err := service.UndoFix(req.JobID, req.OutDir, req.VersionID, req.FixID, req.Title, cfg)
+	if err != nil {
+		c.String(fixPatchStatus(err), "undo fix failed: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// SuggestionReplay applies a fix stored with an auto_fix version to the given yaml (e.g. a newer
+// version of the architecture) as a new replay_fix version; 409 when the yaml changed what the
+// fix edits.
+func (h *Handlers) SuggestionReplay(c *gin.Context) {
+	var req SuggestionReplayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.String(http.StatusBadRequest, "invalid json body")
+		return
+	}
+	if req.VersionID == "" || req.FixID == "" {
+		c.String(http.StatusBadRequest, "version_id and fix_id are required")
+		return
+	}
+	if req.YAML == "" {
+		c.String(http.StatusBadRequest, "yaml is required")
+		return
+	}
+	if req.OutDir == "" {
+		req.OutDir = DefaultOutDir()
+	}
+	if req.Title == "" {
+		req.Title = "Architecture"
+	}
+	if req.JobID == "" {
+		req.JobID = "adhoc"
+	}
+
+	cfg, err := h.resolveDetectorConfig(getOrgID(c), getUserID(c), getChatID(c), req.DetectorConfig)
+	if err != nil {
+		c.String(http.StatusBadRequest, fmt.Sprintf("invalid detector config: %v", err))
+		return
+	}
+	res, err := service.ReplayFix(req.JobID, req.OutDir, req.VersionID, req.FixID, []byte(req.YAML), req.Title, cfg)
+	if err != nil {
+		c.String(fixPatchStatus(err), "replay fix failed: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// GuidanceTemplates lists the guidance template in effect for every anti-pattern kind and where
+// it comes from. Administrators override a template by placing <kind>.md in the directory named
+// by AMG_APD_GUIDANCE_DIR; overrides that fail to parse are listed with their error.
+func GuidanceTemplates(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"templates": suggestion.GuidanceTemplates()})
+}
+
+func fixPatchStatus(err error) int {
+	if errors.Is(err, spec.ErrOpConflict) {
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
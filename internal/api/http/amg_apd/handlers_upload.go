@@ -10,8 +10,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 
-	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/service"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection"
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/service"
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/utils"
 )
 
@@ -43,11 +44,13 @@ func getIncomingDir() string {
 }
 
 type analyzeRawReq struct {
-	YAML                  string                  `json:"yaml"`
-	Title                 string                  `json:"title"`
-	OutDir                string                  `json:"out_dir"`
-	NodeLayout            map[string]nodeLayoutXY `json:"node_layout,omitempty"`
-	MergePreviousDiagram  *bool                   `json:"merge_previous_diagram,omitempty"`
+	YAML                 string                  `json:"yaml"`
+	Title                string                  `json:"title"`
+	OutDir               string                  `json:"out_dir"`
+	NodeLayout           map[string]nodeLayoutXY `json:"node_layout,omitempty"`
+	MergePreviousDiagram *bool                   `json:"merge_previous_diagram,omitempty"`
+	// DetectorConfig overrides the project's stored detector config for this analysis.
+	DetectorConfig *detection.DetectorConfig `json:"detector_config,omitempty"`
 }
 
 // AnalyzeRaw runs analysis and persists to DB (user_id/chat_id from headers or TestUser123/TestChat123).
@@ -67,9 +70,19 @@ func (h *Handlers) AnalyzeRaw(c *gin.Context) {
 	userID := getUserID(c)
 	chatID := getChatID(c)
 
-	res, dotContent, err := service.AnalyzeYAMLBytesInMemory([]byte(req.YAML), req.Title, os.Getenv("DOT_BIN"))
+	cfg, err := h.resolveDetectorConfig(getOrgID(c), userID, chatID, req.DetectorConfig)
+	if err != nil {
+		c.String(http.StatusBadRequest, fmt.Sprintf("invalid detector config: %v", err))
+		return
+	}
+	res, dotContent, err := service.AnalyzeYAMLBytesInMemory([]byte(req.YAML), req.Title, os.Getenv("DOT_BIN"), cfg)
+	if err != nil {
+		analyzeFailed(c, err)
+		return
+	}
+	baseline, err := h.baselineReport(c, userID, chatID, res.Detections)
 	if err != nil {
-		c.String(http.StatusBadRequest, fmt.Sprintf("analyze failed: %v", err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid baseline", "details": err.Error()})
 		return
 	}
 	mergeNodeLayoutIntoGraph(res.Graph, req.NodeLayout)
@@ -79,20 +92,27 @@ func (h *Handlers) AnalyzeRaw(c *gin.Context) {
 	if req.MergePreviousDiagram != nil {
 		mergePrev = *req.MergePreviousDiagram
 	}
-	row, err := h.versionRepo.Save(userID, chatID, req.Title, req.YAML, graphJSON, detectionsJSON, dotContent, mergePrev)
+	row, err := h.versionRepo.Save(userID, chatID, req.Title, req.YAML, graphJSON, detectionsJSON, dotContent, mergePrev, saveExtrasFor(res))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save version", "details": err.Error()})
 		return
 	}
+	detections, hidden := h.withFeedback(c, userID, chatID, res.Detections)
 	c.JSON(http.StatusOK, gin.H{
-		"graph":        res.Graph,
-		"detections":   res.Detections,
-		"dot_content":  dotContent,
-		"dot_path":     "",
-		"svg_path":     "",
-		"version_id":   row.ID,
-		"version_number": row.VersionNumber,
-		"created_at":   row.CreatedAt,
+		"graph":             res.Graph,
+		"detections":        detections,
+		"hidden_detections": hidden,
+		"dot_content":       dotContent,
+		"dot_path":          "",
+		"svg_path":          "",
+		"version_id":        row.ID,
+		"version_number":    row.VersionNumber,
+		"created_at":        row.CreatedAt,
+		"detector_config":   res.DetectorConfig,
+		"metrics":           res.Metrics,
+		"suppressed":        res.Suppressed,
+		"baseline":          baseline,
+		"issues":            res.Issues,
 	})
 }
 
@@ -132,9 +152,35 @@ func (h *Handlers) AnalyzeUpload(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("read file failed: %v", err)})
 		return
 	}
-	res, dotContent, err := service.AnalyzeYAMLBytesInMemory(yamlBytes, title, os.Getenv("DOT_BIN"))
+	// format=compose|kubernetes converts the upload first; the converted YAML is what gets stored.
+	if format := strings.TrimSpace(c.PostForm("format")); format != "" && format != "yaml" {
+		yamlBytes, err = service.ImportToYAML(format, yamlBytes)
+		if err != nil {
+			c.String(http.StatusBadRequest, fmt.Sprintf("import failed: %v", err))
+			return
+		}
+	}
+	var inlineCfg *detection.DetectorConfig
+	if raw := strings.TrimSpace(c.PostForm("detector_config")); raw != "" {
+		inlineCfg = &detection.DetectorConfig{}
+		if err := json.Unmarshal([]byte(raw), inlineCfg); err != nil {
+			c.String(http.StatusBadRequest, fmt.Sprintf("invalid detector config: %v", err))
+			return
+		}
+	}
+	cfg, err := h.resolveDetectorConfig(getOrgID(c), userID, chatID, inlineCfg)
+	if err != nil {
+		c.String(http.StatusBadRequest, fmt.Sprintf("invalid detector config: %v", err))
+		return
+	}
+	res, dotContent, err := service.AnalyzeYAMLBytesInMemory(yamlBytes, title, os.Getenv("DOT_BIN"), cfg)
+	if err != nil {
+		analyzeFailed(c, err)
+		return
+	}
+	baseline, err := h.baselineReport(c, userID, chatID, res.Detections)
 	if err != nil {
-		c.String(http.StatusBadRequest, fmt.Sprintf("analyze failed: %v", err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid baseline", "details": err.Error()})
 		return
 	}
 	graphJSON, _ := json.Marshal(res.Graph)
@@ -143,20 +189,27 @@ func (h *Handlers) AnalyzeUpload(c *gin.Context) {
 	if v := strings.TrimSpace(c.PostForm("merge_previous_diagram")); v == "0" || strings.EqualFold(v, "false") {
 		mergePrev = false
 	}
-	row, err := h.versionRepo.Save(userID, chatID, title, string(yamlBytes), graphJSON, detectionsJSON, dotContent, mergePrev)
+	row, err := h.versionRepo.Save(userID, chatID, title, string(yamlBytes), graphJSON, detectionsJSON, dotContent, mergePrev, saveExtrasFor(res))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save version", "details": err.Error()})
 		return
 	}
+	detections, hidden := h.withFeedback(c, userID, chatID, res.Detections)
 	c.JSON(http.StatusOK, gin.H{
-		"graph":          res.Graph,
-		"detections":     res.Detections,
-		"dot_content":    dotContent,
-		"dot_path":       "",
-		"svg_path":       "",
-		"version_id":     row.ID,
-		"version_number": row.VersionNumber,
-		"created_at":     row.CreatedAt,
+		"graph":             res.Graph,
+		"detections":        detections,
+		"hidden_detections": hidden,
+		"dot_content":       dotContent,
+		"dot_path":          "",
+		"svg_path":          "",
+		"version_id":        row.ID,
+		"version_number":    row.VersionNumber,
+		"created_at":        row.CreatedAt,
+		"detector_config":   res.DetectorConfig,
+		"metrics":           res.Metrics,
+		"suppressed":        res.Suppressed,
+		"baseline":          baseline,
+		"issues":            res.Issues,
 	})
 }
 
@@ -203,9 +256,19 @@ func (h *Handlers) UpdateVersionAnalysis(c *gin.Context) {
 		title = "From diagram"
 	}
 
-	res, dotContent, errAnalyze := service.AnalyzeYAMLBytesInMemory([]byte(yamlContent), title, os.Getenv("DOT_BIN"))
+	cfg, err := h.resolveDetectorConfig(getOrgID(c), userID, chatID, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load detector config", "details": err.Error()})
+		return
+	}
+	res, dotContent, errAnalyze := service.AnalyzeYAMLBytesInMemory([]byte(yamlContent), title, os.Getenv("DOT_BIN"), cfg)
 	if errAnalyze != nil {
-		c.String(http.StatusBadRequest, fmt.Sprintf("analyze failed: %v", errAnalyze))
+		analyzeFailed(c, errAnalyze)
+		return
+	}
+	baseline, err := h.baselineReport(c, userID, chatID, res.Detections)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid baseline", "details": err.Error()})
 		return
 	}
 	mergeNodeLayoutIntoGraph(res.Graph, req.NodeLayout)
@@ -218,7 +281,7 @@ func (h *Handlers) UpdateVersionAnalysis(c *gin.Context) {
 		return strings.ReplaceAll(strings.TrimSpace(s), "\r\n", "\n")
 	}
 	preserveCanvasMerge := normalizeYAML(yamlContent) == normalizeYAML(row.YAMLContent)
-	if err := h.versionRepo.UpdateDiagramVersionAnalysisByID(req.VersionID, userID, chatID, graphJSON, detectionsJSON, dotContent, yamlContent, preserveCanvasMerge); err != nil {
+	if err := h.versionRepo.UpdateDiagramVersionAnalysisByID(req.VersionID, userID, chatID, graphJSON, detectionsJSON, dotContent, yamlContent, preserveCanvasMerge, saveExtrasFor(res)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update version", "details": err.Error()})
 		return
 	}
@@ -228,17 +291,20 @@ func (h *Handlers) UpdateVersionAnalysis(c *gin.Context) {
 		return
 	}
 	var graph interface{}
-	var detections interface{}
 	_ = json.Unmarshal(updated.GraphJSON, &graph)
-	_ = json.Unmarshal(updated.DetectionsJSON, &detections)
+	detections, hidden := h.withFeedbackJSON(c, userID, chatID, updated.DetectionsJSON)
 	c.JSON(http.StatusOK, gin.H{
-		"graph":          graph,
-		"detections":     detections,
-		"dot_content":    updated.DOTContent,
-		"version_id":     updated.ID,
-		"version_number": updated.VersionNumber,
-		"created_at":     updated.CreatedAt,
-		"yaml_content":   updated.YAMLContent,
-		"title":          updated.Title,
+		"graph":             graph,
+		"detections":        detections,
+		"hidden_detections": hidden,
+		"dot_content":       updated.DOTContent,
+		"version_id":        updated.ID,
+		"version_number":    updated.VersionNumber,
+		"created_at":        updated.CreatedAt,
+		"yaml_content":      updated.YAMLContent,
+		"title":             updated.Title,
+		"suppressed":        res.Suppressed,
+		"baseline":          baseline,
+		"issues":            res.Issues,
 	})
 }
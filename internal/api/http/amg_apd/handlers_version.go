@@ -1,12 +1,17 @@
 package amg_apd
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/feedback"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/graph/export"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/service"
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/storage/amg_apd_version"
 )
 
@@ -21,6 +26,13 @@ func getUserID(c *gin.Context) string {
 	return defaultUserID
 }
 
+// getOrgID returns the organization the caller names (X-Org-Id); empty when the caller has none.
+// The header is not trusted: analyses ignore the rules of an org the caller is not a member of,
+// and the org routes answer 403.
+func getOrgID(c *gin.Context) string {
+	return strings.TrimSpace(c.GetHeader("X-Org-Id"))
+}
+
 func getChatID(c *gin.Context) string {
 	if v := c.GetHeader("X-Chat-Id"); v != "" {
 		return v
@@ -40,7 +52,9 @@ func (h *Handlers) ListVersions(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"versions": summaries})
 }
 
-// GetVersion returns a single version by id (must belong to user/chat).
+// GetVersion returns a single version by id (must belong to user/chat). With ?format= (dot, svg,
+// mermaid, plantuml, graphml, cytoscape) the graph is returned as that diagram instead of JSON,
+// with visible detections highlighted; add download=true for an attachment.
 func (h *Handlers) GetVersion(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
@@ -65,19 +79,43 @@ func (h *Handlers) GetVersion(c *gin.Context) {
 		return
 	}
 	graph.RebuildOutIn()
+	detections, hidden := h.withFeedback(c, userID, chatID, detections)
+	if format := c.Query("format"); format != "" && format != "json" {
+		out, f, err := export.Render(format, &graph, row.Title, detections)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format", "details": err.Error()})
+			return
+		}
+		if c.Query("download") == "true" || c.Query("download") == "1" {
+			c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="version-%d.%s"`, row.VersionNumber, f.Ext))
+		}
+		c.Data(http.StatusOK, f.ContentType, []byte(out))
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{
-		"id":             row.ID,
-		"version_number": row.VersionNumber,
-		"title":          row.Title,
-		"source":         row.Source,
-		"yaml_content":   row.YAMLContent,
-		"graph":          &graph,
-		"dot_content":    row.DOTContent,
-		"detections":     detections,
-		"created_at":     row.CreatedAt,
+		"id":                 row.ID,
+		"version_number":     row.VersionNumber,
+		"title":              row.Title,
+		"source":             row.Source,
+		"yaml_content":       row.YAMLContent,
+		"graph":              &graph,
+		"dot_content":        row.DOTContent,
+		"detections":         detections,
+		"hidden_detections":  hidden,
+		"created_at":         row.CreatedAt,
+		"detector_config":    detectorConfigRaw(row),
+		"parent_version_ids": row.ParentVersionIDs,
 	})
 }
 
+// detectorConfigRaw returns the stored effective detector config (null for rows analyzed before it was recorded).
+func detectorConfigRaw(row *amg_apd_version.VersionRow) json.RawMessage {
+	if row == nil || len(row.DetectorConfigJSON) == 0 {
+		return nil
+	}
+	return json.RawMessage(row.DetectorConfigJSON)
+}
+
 // DeleteVersion deletes a version by id (must belong to user/chat).
 func (h *Handlers) DeleteVersion(c *gin.Context) {
 	id := c.Param("id")
@@ -132,7 +170,9 @@ func (h *Handlers) PatchVersion(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"ok": true, "id": id, "title": title})
 }
 
-// CompareVersions returns two versions for side-by-side compare.
+// CompareVersions returns two versions for side-by-side compare, plus "diff": what changed from
+// left to right (nodes, edges, attributes, detections, risk delta) and the merged graph as
+// annotated DOT and SVG (added green, removed red/dashed, changed blue).
 // Query: ?left=<id>&right=<id> or JSON body { "left_id": "", "right_id": "" }.
 func (h *Handlers) CompareVersions(c *gin.Context) {
 	var leftID, rightID string
@@ -181,7 +221,13 @@ func (h *Handlers) CompareVersions(c *gin.Context) {
 	_ = amg_apd_version.ParseGraphAndDetections(right, &rightGraph, &rightDet)
 	leftGraph.RebuildOutIn()
 	rightGraph.RebuildOutIn()
+	diff := service.DiffArchitectures(&leftGraph, leftDet, &rightGraph, rightDet)
+	diffGraph, marks := service.DiffGraph(&leftGraph, &rightGraph, diff)
+	diffTitle := fmt.Sprintf("v%d -> v%d", left.VersionNumber, right.VersionNumber)
 	c.JSON(http.StatusOK, gin.H{
+		"diff":     diff,
+		"diff_dot": export.ToDiffDOT(diffGraph, diffTitle, marks),
+		"diff_svg": export.ToDiffSVG(diffGraph, diffTitle, marks),
 		"left": gin.H{
 			"id":             left.ID,
 			"version_number": left.VersionNumber,
@@ -207,13 +253,13 @@ func (h *Handlers) CompareVersions(c *gin.Context) {
 	})
 }
 
-// Handlers holds dependencies for AMG-APD HTTP handlers (e.g. version repo).
+// Handlers holds dependencies for AMG-APD HTTP handlers (e.g. version repo, detection feedback).
 type Handlers struct {
 	versionRepo *amg_apd_version.Repo
+	feedback    *feedback.Service
 }
 
-// NewHandlers builds AMG-APD handlers with the given version repo.
-func NewHandlers(versionRepo *amg_apd_version.Repo) *Handlers {
-	return &Handlers{versionRepo: versionRepo}
+// NewHandlers builds AMG-APD handlers with the given version repo and feedback service (may be nil).
+func NewHandlers(versionRepo *amg_apd_version.Repo, fb *feedback.Service) *Handlers {
+	return &Handlers{versionRepo: versionRepo, feedback: fb}
 }
-
@@ -5,7 +5,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/feedback"
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/storage/amg_apd_version"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/storage/repositories"
 )
 
 // Register mounts AMG-APD routes. Pass db for versioning/storage (uses Postgres from .env).
@@ -15,19 +17,42 @@ func Register(r *gin.Engine, db *sql.DB, versionRepo *amg_apd_version.Repo) {
 		versionRepo = amg_apd_version.NewRepo(db)
 	}
 	v1 := r.Group("/api/v1/amg-apd")
-	h := NewHandlers(versionRepo)
+	h := NewHandlers(versionRepo, feedback.NewService(repositories.NewFeedbackRepo(db)))
 
 	v1.POST("/analyze-raw", h.AnalyzeRaw)
 	v1.POST("/analyze", h.AnalyzeUpload)
 	v1.POST("/update-version-analysis", h.UpdateVersionAnalysis)
+	v1.POST("/import", h.ImportArchitecture)
 
 	v1.GET("/versions", h.ListVersions)
 	v1.GET("/versions/compare", h.CompareVersions)
+	v1.POST("/versions/merge", h.MergeVersions)
 	v1.GET("/versions/:id", h.GetVersion)
+	v1.GET("/versions/:id/metrics", h.GetVersionMetrics)
 	v1.PATCH("/versions/:id", h.PatchVersion)
 	v1.DELETE("/versions/:id", h.DeleteVersion)
 	v1.GET("/projects/:project_public_id/latest", h.GetLatestForProject)
+	v1.GET("/projects/:project_public_id/detector-config", h.GetDetectorConfig)
+	v1.PUT("/projects/:project_public_id/detector-config", h.PutDetectorConfig)
+	v1.DELETE("/projects/:project_public_id/detector-config", h.DeleteDetectorConfig)
+	v1.GET("/projects/:project_public_id/feedback", h.ListFeedback)
+	v1.POST("/projects/:project_public_id/feedback", h.PutFeedback)
+	v1.DELETE("/projects/:project_public_id/feedback", h.DeleteFeedback)
+	v1.GET("/projects/:project_public_id/feedback/export", h.ExportFeedback)
+	v1.GET("/projects/:project_public_id/baseline", h.GetBaseline)
+	v1.PUT("/projects/:project_public_id/baseline", h.PutBaseline)
+	v1.DELETE("/projects/:project_public_id/baseline", h.DeleteBaseline)
+	v1.GET("/projects/:project_public_id/custom-rules", h.ListProjectCustomRules)
+	v1.PUT("/projects/:project_public_id/custom-rules/:kind", h.PutProjectCustomRule)
+	v1.DELETE("/projects/:project_public_id/custom-rules/:kind", h.DeleteProjectCustomRule)
+	v1.GET("/orgs/:org_id/custom-rules", h.ListOrgCustomRules)
+	v1.PUT("/orgs/:org_id/custom-rules/:kind", h.PutOrgCustomRule)
+	v1.DELETE("/orgs/:org_id/custom-rules/:kind", h.DeleteOrgCustomRule)
+	v1.POST("/custom-rules/test", TestCustomRule)
 
-	v1.POST("/suggestions", SuggestionPreview)
-	v1.POST("/apply-suggestions", SuggestionApply)
+	v1.POST("/suggestions", h.SuggestionPreview)
+	v1.POST("/apply-suggestions", h.SuggestionApply)
+	v1.POST("/apply-suggestions/undo", h.SuggestionUndo)
+	v1.POST("/apply-suggestions/replay", h.SuggestionReplay)
+	v1.GET("/guidance-templates", GuidanceTemplates)
 }
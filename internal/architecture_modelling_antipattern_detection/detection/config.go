@@ -0,0 +1,149 @@
+package detection
+
+import (
+	"fmt"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+// DetectorConfig tunes detectors per project: enable/disable, thresholds and severity overrides.
+// A nil *DetectorConfig (or missing rule entry) means "use the built-in defaults".
+type DetectorConfig struct {
+	Rules map[domain.AntiPatternKind]RuleConfig `json:"rules,omitempty" yaml:"rules,omitempty"`
+	// CustomRules are user-defined expression detectors run alongside the built-in ones. Stored
+	// project and org rules live in their own table; here they come inline with a request.
+	CustomRules []CustomRule `json:"custom_rules,omitempty" yaml:"custom_rules,omitempty"`
+}
+
+// RuleConfig is the per-anti-pattern part of DetectorConfig.
+type RuleConfig struct {
+	Enabled    *bool              `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Thresholds map[string]float64 `json:"thresholds,omitempty" yaml:"thresholds,omitempty"`
+	Severity   domain.Severity    `json:"severity,omitempty" yaml:"severity,omitempty"`
+}
+
+// ThresholdProvider is implemented by detectors that expose tunable thresholds.
+// DefaultThresholds returns the values used when the config does not override them.
+type ThresholdProvider interface {
+	DefaultThresholds() map[string]float64
+}
+
+// Enabled reports whether detections of the given kind should be produced.
+func (c *DetectorConfig) Enabled(kind domain.AntiPatternKind) bool {
+	if c == nil {
+		return true
+	}
+	rc, ok := c.Rules[kind]
+	if !ok || rc.Enabled == nil {
+		return true
+	}
+	return *rc.Enabled
+}
+
+// Threshold returns the configured threshold for kind/key, falling back to defaults[key].
+func (c *DetectorConfig) Threshold(kind domain.AntiPatternKind, key string, defaults map[string]float64) float64 {
+	if c != nil {
+		if rc, ok := c.Rules[kind]; ok {
+			if v, ok := rc.Thresholds[key]; ok {
+				return v
+			}
+		}
+	}
+	return defaults[key]
+}
+
+// IntThreshold is Threshold rounded down to an int (for counts like degree or edges).
+func (c *DetectorConfig) IntThreshold(kind domain.AntiPatternKind, key string, defaults map[string]float64) int {
+	return int(c.Threshold(kind, key, defaults))
+}
+
+// SeverityFor returns the override for kind, or sev when none is configured.
+func (c *DetectorConfig) SeverityFor(kind domain.AntiPatternKind, sev domain.Severity) domain.Severity {
+	if c == nil {
+		return sev
+	}
+	if rc, ok := c.Rules[kind]; ok && rc.Severity != "" {
+		return rc.Severity
+	}
+	return sev
+}
+
+// Validate rejects unknown anti-pattern kinds, unknown threshold keys, non-positive thresholds,
+// unsupported severities and invalid custom rules so bad project configs fail on save rather
+// than during analysis.
+func (c *DetectorConfig) Validate() error {
+	return c.ValidateWith(nil)
+}
+
+// ValidateWith is Validate for a config the analysis adds stored custom rules to: Rules may also
+// enable, disable or re-rate the kinds in stored.
+func (c *DetectorConfig) ValidateWith(stored []CustomRule) error {
+	if c == nil {
+		return nil
+	}
+	if err := ValidateCustomRules(c.CustomRules); err != nil {
+		return fmt.Errorf("detector config: %w", err)
+	}
+	known := map[domain.AntiPatternKind]Detector{}
+	for _, d := range All() {
+		known[domain.AntiPatternKind(d.Name())] = d
+	}
+	custom := map[domain.AntiPatternKind]bool{}
+	for _, r := range append(append([]CustomRule(nil), stored...), c.CustomRules...) {
+		custom[r.Kind] = true
+	}
+	for kind, rc := range c.Rules {
+		d, ok := known[kind]
+		if !ok && !custom[kind] {
+			return fmt.Errorf("detector config: unknown anti-pattern kind %q", kind)
+		}
+		switch rc.Severity {
+		case "", domain.SeverityLow, domain.SeverityMedium, domain.SeverityHigh:
+		default:
+			return fmt.Errorf("detector config: %s: unsupported severity %q", kind, rc.Severity)
+		}
+		var defaults map[string]float64
+		if tp, ok := d.(ThresholdProvider); ok {
+			defaults = tp.DefaultThresholds()
+		}
+		for key, v := range rc.Thresholds {
+			if _, ok := defaults[key]; !ok {
+				return fmt.Errorf("detector config: %s: unknown threshold %q", kind, key)
+			}
+			if v <= 0 {
+				return fmt.Errorf("detector config: %s: threshold %q must be > 0", kind, key)
+			}
+		}
+	}
+	return nil
+}
+
+// Effective returns a fully populated copy of c: every registered detector gets an entry with
+// explicit enabled flag and thresholds, so a stored config reproduces the analysis exactly.
+func Effective(c *DetectorConfig) *DetectorConfig {
+	out := &DetectorConfig{Rules: map[domain.AntiPatternKind]RuleConfig{}}
+	for _, d := range All() {
+		kind := domain.AntiPatternKind(d.Name())
+		enabled := c.Enabled(kind)
+		rc := RuleConfig{Enabled: &enabled}
+		if tp, ok := d.(ThresholdProvider); ok {
+			defaults := tp.DefaultThresholds()
+			rc.Thresholds = make(map[string]float64, len(defaults))
+			for key := range defaults {
+				rc.Thresholds[key] = c.Threshold(kind, key, defaults)
+			}
+		}
+		if c != nil {
+			rc.Severity = c.Rules[kind].Severity
+		}
+		out.Rules[kind] = rc
+	}
+	if c != nil && len(c.CustomRules) > 0 {
+		out.CustomRules = append([]CustomRule(nil), c.CustomRules...)
+		for _, r := range c.CustomRules {
+			enabled := c.Enabled(r.Kind)
+			out.Rules[r.Kind] = RuleConfig{Enabled: &enabled, Severity: c.Rules[r.Kind].Severity}
+		}
+	}
+	return out
+}
@@ -0,0 +1,302 @@
+package detection
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection/expr"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ml/features"
+)
+
+// Custom rule targets: the expression runs once per node, once per edge, or once for the whole graph.
+const (
+	TargetNode  = "node"
+	TargetEdge  = "edge"
+	TargetGraph = "graph"
+)
+
+// CustomRule is a user-defined detector: every target for which Expr is true becomes a detection
+// of Kind. Expr uses the detection/expr language with these variables:
+//
+//	node   (target node)  id, name, kind, attrs, labels, out, in, fan_in, fan_out,
+//	                      instability, betweenness, sync_depth
+//	edge   (target edge)  index, kind, from, to, sync, dep_kind, attrs
+//	graph  (all targets)  nodes, edges, node_count, edge_count, density, scc_count,
+//	                      cyclic_scc_count, max_sync_depth, avg_fan_out, max_betweenness
+//
+// Example: a payment service that calls an external system synchronously.
+//
+//	node.kind == "SERVICE" && node.labels.kind == "payment" &&
+//	  node.out.exists(e, e.sync && e.to.kind == "EXTERNAL_SYSTEM")
+type CustomRule struct {
+	Kind     domain.AntiPatternKind `json:"kind" yaml:"kind"`
+	Title    string                 `json:"title" yaml:"title"`
+	Summary  string                 `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Severity domain.Severity        `json:"severity,omitempty" yaml:"severity,omitempty"`
+	Target   string                 `json:"target,omitempty" yaml:"target,omitempty"`
+	Expr     string                 `json:"expr" yaml:"expr"`
+}
+
+var customKindRe = regexp.MustCompile(`^[a-z][a-z0-9_]{1,63}$`)
+
+func (r CustomRule) target() string {
+	if r.Target == "" {
+		return TargetNode
+	}
+	return r.Target
+}
+
+func (r CustomRule) severity() domain.Severity {
+	if r.Severity == "" {
+		return domain.SeverityMedium
+	}
+	return r.Severity
+}
+
+// Compile validates r and compiles its expression.
+func (r CustomRule) Compile() (*expr.Program, error) {
+	if !customKindRe.MatchString(string(r.Kind)) {
+		return nil, fmt.Errorf("custom rule %q: kind must be lower_snake_case (2-64 chars)", r.Kind)
+	}
+	if _, builtin := registered[string(r.Kind)]; builtin {
+		return nil, fmt.Errorf("custom rule %q: kind clashes with a built-in detector", r.Kind)
+	}
+	if strings.TrimSpace(r.Title) == "" {
+		return nil, fmt.Errorf("custom rule %q: title is required", r.Kind)
+	}
+	switch r.Severity {
+	case "", domain.SeverityLow, domain.SeverityMedium, domain.SeverityHigh:
+	default:
+		return nil, fmt.Errorf("custom rule %q: unsupported severity %q", r.Kind, r.Severity)
+	}
+	vars := []string{"graph"}
+	switch r.target() {
+	case TargetNode:
+		vars = append(vars, "node")
+	case TargetEdge:
+		vars = append(vars, "edge")
+	case TargetGraph:
+	default:
+		return nil, fmt.Errorf("custom rule %q: target must be %s, %s or %s", r.Kind, TargetNode, TargetEdge, TargetGraph)
+	}
+	p, err := expr.Compile(r.Expr, vars...)
+	if err != nil {
+		return nil, fmt.Errorf("custom rule %q: %w", r.Kind, err)
+	}
+	return p, nil
+}
+
+// ValidateCustomRules compiles every rule and rejects duplicate kinds.
+func ValidateCustomRules(rules []CustomRule) error {
+	seen := map[domain.AntiPatternKind]bool{}
+	for _, r := range rules {
+		if _, err := r.Compile(); err != nil {
+			return err
+		}
+		if seen[r.Kind] {
+			return fmt.Errorf("custom rule %q is defined twice", r.Kind)
+		}
+		seen[r.Kind] = true
+	}
+	return nil
+}
+
+// ruleEnv exposes a graph (plus its metrics) to rule expressions. Node and edge maps are shared,
+// so edge.from == node compares by identity.
+type ruleEnv struct {
+	graph map[string]any
+	nodes []map[string]any
+	edges []map[string]any
+}
+
+func newRuleEnv(g *domain.Graph, m *features.GraphMetrics) *ruleEnv {
+	if m == nil {
+		m = features.Compute(g)
+	}
+	env := &ruleEnv{}
+	byID := make(map[string]map[string]any, len(g.Nodes))
+
+	ids := make([]string, 0, len(g.Nodes))
+	for id := range g.Nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	nodes := make([]any, 0, len(ids))
+	for _, id := range ids {
+		n := g.Nodes[id]
+		attrs := map[string]any(n.Attrs)
+		if attrs == nil {
+			attrs = map[string]any{}
+		}
+		labels, _ := attrs["labels"]
+		if labels == nil {
+			labels = map[string]any{}
+		}
+		nm := map[string]any{
+			"id":     n.ID,
+			"name":   n.Name,
+			"kind":   string(n.Kind),
+			"attrs":  attrs,
+			"labels": labels,
+			"out":    []any{},
+			"in":     []any{},
+		}
+		if nmx := m.NodeByID(id); nmx != nil {
+			nm["fan_in"] = float64(nmx.FanIn)
+			nm["fan_out"] = float64(nmx.FanOut)
+			nm["instability"] = nmx.Instability
+			nm["betweenness"] = nmx.Betweenness
+			nm["sync_depth"] = float64(nmx.SyncDepth)
+		}
+		byID[id] = nm
+		env.nodes = append(env.nodes, nm)
+		nodes = append(nodes, nm)
+	}
+
+	edges := make([]any, 0, len(g.Edges))
+	for i, e := range g.Edges {
+		from, to := byID[e.From], byID[e.To]
+		if from == nil || to == nil {
+			continue
+		}
+		attrs := map[string]any(e.Attrs)
+		if attrs == nil {
+			attrs = map[string]any{}
+		}
+		sync, _ := attrs["sync"].(bool)
+		depKind, _ := attrs["dep_kind"].(string)
+		em := map[string]any{
+			"index":    float64(i),
+			"kind":     string(e.Kind),
+			"from":     from,
+			"to":       to,
+			"sync":     sync,
+			"dep_kind": depKind,
+			"attrs":    attrs,
+		}
+		from["out"] = append(from["out"].([]any), em)
+		to["in"] = append(to["in"].([]any), em)
+		env.edges = append(env.edges, em)
+		edges = append(edges, em)
+	}
+
+	env.graph = map[string]any{
+		"nodes":            nodes,
+		"edges":            edges,
+		"node_count":       float64(m.NodeCount),
+		"edge_count":       float64(m.EdgeCount),
+		"density":          m.Density,
+		"scc_count":        float64(m.SCCCount),
+		"cyclic_scc_count": float64(m.CyclicSCCCount),
+		"max_sync_depth":   float64(m.MaxSyncDepth),
+		"avg_fan_out":      m.AvgFanOut,
+		"max_betweenness":  m.MaxBetweenness,
+	}
+	return env
+}
+
+// runCustomRules evaluates cfg's custom rules on g with metrics m (nil computes them). Disabled
+// kinds are skipped; a rule that does not compile or fails while evaluating contributes no
+// detections and is returned as a RuleError.
+func runCustomRules(g *domain.Graph, cfg *DetectorConfig, m *features.GraphMetrics) ([]domain.Detection, []RuleError) {
+	if cfg == nil || len(cfg.CustomRules) == 0 {
+		return nil, nil
+	}
+	var env *ruleEnv
+	var out []domain.Detection
+	var failed []RuleError
+	for _, r := range cfg.CustomRules {
+		if !cfg.Enabled(r.Kind) {
+			continue
+		}
+		p, err := r.Compile()
+		if err != nil {
+			failed = append(failed, RuleError{Kind: r.Kind, Message: err.Error()})
+			continue
+		}
+		if env == nil {
+			env = newRuleEnv(g, m)
+		}
+		ds, err := r.run(p, env)
+		if err != nil {
+			failed = append(failed, RuleError{Kind: r.Kind, Message: err.Error()})
+			continue
+		}
+		out = append(out, ds...)
+	}
+	return out, failed
+}
+
+func (r CustomRule) run(p *expr.Program, env *ruleEnv) ([]domain.Detection, error) {
+	var out []domain.Detection
+	switch r.target() {
+	case TargetGraph:
+		ok, err := p.EvalBool(expr.Vars{"graph": env.graph})
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out = append(out, r.detection(nil, nil, ""))
+		}
+	case TargetEdge:
+		for _, em := range env.edges {
+			ok, err := p.EvalBool(expr.Vars{"graph": env.graph, "edge": em})
+			if err != nil {
+				return nil, fmt.Errorf("edge %s -> %s: %w", em["from"].(map[string]any)["id"], em["to"].(map[string]any)["id"], err)
+			}
+			if !ok {
+				continue
+			}
+			from := em["from"].(map[string]any)
+			to := em["to"].(map[string]any)
+			label := fmt.Sprintf("%s -> %s", from["name"], to["name"])
+			out = append(out, r.detection([]string{from["id"].(string), to["id"].(string)}, []int{int(em["index"].(float64))}, label))
+		}
+	default:
+		for _, nm := range env.nodes {
+			ok, err := p.EvalBool(expr.Vars{"graph": env.graph, "node": nm})
+			if err != nil {
+				return nil, fmt.Errorf("node %s: %w", nm["id"], err)
+			}
+			if ok {
+				out = append(out, r.detection([]string{nm["id"].(string)}, nil, nm["name"].(string)))
+			}
+		}
+	}
+	return out, nil
+}
+
+// detection builds the result for one match; "{target}" in Title/Summary is replaced by the
+// node name (or "from -> to" for edges).
+func (r CustomRule) detection(nodes []string, edges []int, label string) domain.Detection {
+	expand := func(s string) string { return strings.ReplaceAll(s, "{target}", label) }
+	summary := expand(r.Summary)
+	if summary == "" {
+		summary = fmt.Sprintf("Custom rule %q matched %s.", r.Kind, label)
+		if label == "" {
+			summary = fmt.Sprintf("Custom rule %q matched the architecture.", r.Kind)
+		}
+	}
+	if nodes == nil {
+		nodes = []string{}
+	}
+	if edges == nil {
+		edges = []int{}
+	}
+	return domain.Detection{
+		Kind:     r.Kind,
+		Severity: r.severity(),
+		Title:    expand(r.Title),
+		Summary:  summary,
+		Nodes:    nodes,
+		Edges:    edges,
+		Evidence: domain.Attrs{
+			"custom_rule": true,
+			"target":      r.target(),
+			"expr":        r.Expr,
+		},
+	}
+}
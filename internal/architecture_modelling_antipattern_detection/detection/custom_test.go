@@ -0,0 +1,168 @@
+package detection
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ml/features"
+)
+
+func paymentGraph() *domain.Graph {
+	g := domain.NewGraph()
+	g.AddNode(&domain.Node{ID: "SERVICE:pay", Name: "pay", Kind: domain.NodeService, Attrs: domain.Attrs{"labels": map[string]any{"kind": "payment"}}})
+	g.AddNode(&domain.Node{ID: "SERVICE:orders", Name: "orders", Kind: domain.NodeService})
+	g.AddNode(&domain.Node{ID: "EXTERNAL_SYSTEM:stripe", Name: "stripe", Kind: domain.NodeExternalSystem})
+	g.AddEdge(&domain.Edge{From: "SERVICE:orders", To: "SERVICE:pay", Kind: domain.EdgeCalls, Attrs: domain.Attrs{"sync": true}})
+	g.AddEdge(&domain.Edge{From: "SERVICE:pay", To: "EXTERNAL_SYSTEM:stripe", Kind: domain.EdgeCalls, Attrs: domain.Attrs{"sync": true}})
+	return g
+}
+
+func TestRunAll_CustomRules(t *testing.T) {
+	cfg := &DetectorConfig{CustomRules: []CustomRule{
+		{
+			Kind:     "payment_sync_external",
+			Title:    "Payment service {target} calls an external system synchronously",
+			Severity: domain.SeverityHigh,
+			Expr:     `node.kind == "SERVICE" && node.labels.kind == "payment" && node.out.exists(e, e.kind == "CALLS" && e.sync && e.to.kind == "EXTERNAL_SYSTEM")`,
+		},
+		{
+			Kind:   "sync_into_payment",
+			Title:  "Sync call {target}",
+			Target: TargetEdge,
+			Expr:   `edge.sync && edge.to.labels.kind == "payment"`,
+		},
+		{
+			Kind:   "too_dense",
+			Title:  "Graph is dense",
+			Target: TargetGraph,
+			Expr:   `graph.density > 0.1 && graph.node_count >= 3`,
+		},
+	}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	dets, err := RunAll(paymentGraph(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byKind := map[domain.AntiPatternKind][]domain.Detection{}
+	for _, d := range dets {
+		byKind[d.Kind] = append(byKind[d.Kind], d)
+	}
+	pay := byKind["payment_sync_external"]
+	if len(pay) != 1 || pay[0].Nodes[0] != "SERVICE:pay" || pay[0].Severity != domain.SeverityHigh || !strings.Contains(pay[0].Title, "pay") {
+		t.Fatalf("payment rule: %+v", pay)
+	}
+	edge := byKind["sync_into_payment"]
+	if len(edge) != 1 || len(edge[0].Edges) != 1 || edge[0].Edges[0] != 0 || edge[0].Severity != domain.SeverityMedium {
+		t.Fatalf("edge rule: %+v", edge)
+	}
+	if len(byKind["too_dense"]) != 1 {
+		t.Fatalf("graph rule: %+v", byKind["too_dense"])
+	}
+
+	off := false
+	cfg.Rules = map[domain.AntiPatternKind]RuleConfig{"too_dense": {Enabled: &off}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	dets, _ = RunAll(paymentGraph(), cfg)
+	for _, d := range dets {
+		if d.Kind == "too_dense" {
+			t.Fatal("disabled custom rule still ran")
+		}
+	}
+	if eff := Effective(cfg); len(eff.CustomRules) != 3 || *eff.Rules["too_dense"].Enabled {
+		t.Fatalf("effective config should carry custom rules: %+v", eff)
+	}
+}
+
+func TestCustomRule_Validate(t *testing.T) {
+	cases := map[string]CustomRule{
+		"lower_snake_case": {Kind: "Bad Kind", Title: "x", Expr: "true"},
+		"built-in":         {Kind: "builtin_stub", Title: "x", Expr: "true"},
+		"title":            {Kind: "no_title", Expr: "true"},
+		"target":           {Kind: "bad_target", Title: "x", Target: "db", Expr: "true"},
+		"undeclared":       {Kind: "graph_only", Title: "x", Target: TargetGraph, Expr: `node.kind == "SERVICE"`},
+	}
+	Register(builtinStub{})
+	defer delete(registered, "builtin_stub")
+	for want, r := range cases {
+		err := ValidateCustomRules([]CustomRule{r})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%s: want error containing %q, got %v", r.Kind, want, err)
+		}
+	}
+	dup := CustomRule{Kind: "dup", Title: "x", Expr: "true"}
+	if err := ValidateCustomRules([]CustomRule{dup, dup}); err == nil {
+		t.Fatal("expected duplicate kind error")
+	}
+}
+
+type builtinStub struct{}
+
+func (builtinStub) Name() string { return "builtin_stub" }
+func (builtinStub) Detect(*domain.Graph, *DetectorConfig) ([]domain.Detection, error) {
+	return nil, nil
+}
+
+func TestRunAllReport_FailingCustomRuleKeepsOthers(t *testing.T) {
+	cfg := &DetectorConfig{CustomRules: []CustomRule{
+		{Kind: "divides_by_zero", Title: "Broken", Target: TargetGraph, Expr: `graph.node_count / 0 > 1`},
+		{Kind: "too_dense", Title: "Graph is dense", Target: TargetGraph, Expr: `graph.node_count >= 3`},
+	}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	dets, ruleErrs, err := RunAllReport(paymentGraph(), cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ruleErrs) != 1 || ruleErrs[0].Kind != "divides_by_zero" || !strings.Contains(ruleErrs[0].Message, "division by zero") {
+		t.Fatalf("rule errors: %+v", ruleErrs)
+	}
+	found := false
+	for _, d := range dets {
+		if d.Kind == "divides_by_zero" {
+			t.Fatalf("failed rule produced a detection: %+v", d)
+		}
+		found = found || d.Kind == "too_dense"
+	}
+	if !found {
+		t.Fatalf("other custom rule was dropped: %+v", dets)
+	}
+	if _, err := RunAll(paymentGraph(), cfg); err != nil {
+		t.Fatalf("RunAll failed on a broken custom rule: %v", err)
+	}
+}
+
+func TestValidateWith_StoredRuleKinds(t *testing.T) {
+	sev := &DetectorConfig{Rules: map[domain.AntiPatternKind]RuleConfig{"org_rule": {Severity: domain.SeverityHigh}}}
+	if err := sev.Validate(); err == nil {
+		t.Fatal("a kind nothing defines must be rejected")
+	}
+	stored := []CustomRule{{Kind: "org_rule", Title: "Org rule", Target: TargetGraph, Expr: `graph.node_count > 10`}}
+	if err := sev.ValidateWith(stored); err != nil {
+		t.Fatalf("a stored rule's kind must be configurable: %v", err)
+	}
+}
+
+func TestRunAllReport_UsesGivenMetrics(t *testing.T) {
+	g := paymentGraph()
+	m := features.Compute(g)
+	m.MaxBetweenness = 42
+	cfg := &DetectorConfig{CustomRules: []CustomRule{
+		{Kind: "given_metrics", Title: "Given metrics", Target: TargetGraph, Expr: `graph.max_betweenness == 42`},
+	}}
+	dets, _, err := RunAllReport(g, cfg, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range dets {
+		if d.Kind == "given_metrics" {
+			return
+		}
+	}
+	t.Fatal("custom rules did not see the metrics passed in")
+}
@@ -2,22 +2,60 @@ package detection
 
 import (
 	"fmt"
+	"log"
 
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ml/features"
 )
 
-func RunAll(g *domain.Graph) ([]domain.Detection, error) {
+// RuleError is a custom rule that failed on a graph. The rule's detections are dropped; the other
+// detectors still run.
+type RuleError struct {
+	Kind    domain.AntiPatternKind `json:"kind" yaml:"kind"`
+	Message string                 `json:"message" yaml:"message"`
+}
+
+// RunAll runs every registered detector, then cfg's custom rules, on g. cfg may be nil (built-in
+// defaults); disabled kinds are skipped and severity overrides are applied to the results.
+// A failing custom rule is logged and skipped; use RunAllReport to get the failures.
+func RunAll(g *domain.Graph, cfg *DetectorConfig) ([]domain.Detection, error) {
+	dets, ruleErrs, err := RunAllReport(g, cfg, nil)
+	for _, re := range ruleErrs {
+		log.Printf("detection: custom rule %q failed: %s", re.Kind, re.Message)
+	}
+	return dets, err
+}
+
+// RunAllReport is RunAll returning the custom rules that failed instead of logging them. Only a
+// failing built-in detector is an error. m is g's metrics for the custom rules; when nil they are
+// computed if a rule needs them.
+func RunAllReport(g *domain.Graph, cfg *DetectorConfig, m *features.GraphMetrics) ([]domain.Detection, []RuleError, error) {
 	if g == nil {
-		return nil, fmt.Errorf("detection: graph is nil")
+		return nil, nil, fmt.Errorf("detection: graph is nil")
 	}
 
 	var out []domain.Detection
 	for _, det := range All() {
-		ds, err := det.Detect(g)
+		if !cfg.Enabled(domain.AntiPatternKind(det.Name())) {
+			continue
+		}
+		ds, err := det.Detect(g, cfg)
 		if err != nil {
-			return nil, fmt.Errorf("detector %q failed: %w", det.Name(), err)
+			return nil, nil, fmt.Errorf("detector %q failed: %w", det.Name(), err)
+		}
+		for _, d := range ds {
+			if !cfg.Enabled(d.Kind) {
+				continue
+			}
+			d.Severity = cfg.SeverityFor(d.Kind, d.Severity)
+			out = append(out, d)
 		}
-		out = append(out, ds...)
 	}
-	return out, nil
+
+	custom, ruleErrs := runCustomRules(g, cfg, m)
+	for _, d := range custom {
+		d.Severity = cfg.SeverityFor(d.Kind, d.Severity)
+		out = append(out, d)
+	}
+	return out, ruleErrs, nil
 }
@@ -0,0 +1,550 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// scope is a chain of variable bindings (the root holds the program's Vars, macros push one level).
+type scope struct {
+	name   string
+	val    any
+	vars   Vars
+	parent *scope
+}
+
+func (s *scope) lookup(name string) (any, bool) {
+	for c := s; c != nil; c = c.parent {
+		if c.vars != nil {
+			v, ok := c.vars[name]
+			return v, ok
+		}
+		if c.name == name {
+			return c.val, true
+		}
+	}
+	return nil, false
+}
+
+type evaluator struct {
+	regexps map[string]*regexp.Regexp
+}
+
+func (ev *evaluator) eval(n node, s *scope) (any, error) {
+	switch n := n.(type) {
+	case *litNode:
+		return n.v, nil
+	case *identNode:
+		v, ok := s.lookup(n.name)
+		if !ok {
+			return nil, fmt.Errorf("variable %q is not set", n.name)
+		}
+		return normalize(v), nil
+	case *listNode:
+		out := make([]any, 0, len(n.elems))
+		for _, e := range n.elems {
+			v, err := ev.eval(e, s)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	case *unaryNode:
+		x, err := ev.eval(n.x, s)
+		if err != nil {
+			return nil, err
+		}
+		if n.op == "!" {
+			b, ok := x.(bool)
+			if !ok {
+				return nil, fmt.Errorf("! needs a bool, got %s", typeName(x))
+			}
+			return !b, nil
+		}
+		f, ok := x.(float64)
+		if !ok {
+			return nil, fmt.Errorf("unary - needs a number, got %s", typeName(x))
+		}
+		return -f, nil
+	case *binaryNode:
+		return ev.binary(n, s)
+	case *memberNode:
+		x, err := ev.eval(n.x, s)
+		if err != nil {
+			return nil, err
+		}
+		return field(x, n.name)
+	case *hasNode:
+		x, err := ev.eval(n.m.x, s)
+		if err != nil {
+			return nil, err
+		}
+		m, ok := x.(map[string]any)
+		if !ok {
+			return false, nil
+		}
+		_, ok = m[n.m.name]
+		return ok, nil
+	case *indexNode:
+		x, err := ev.eval(n.x, s)
+		if err != nil {
+			return nil, err
+		}
+		i, err := ev.eval(n.i, s)
+		if err != nil {
+			return nil, err
+		}
+		switch x := x.(type) {
+		case []any:
+			f, ok := i.(float64)
+			if !ok || f != math.Trunc(f) || f < 0 || int(f) >= len(x) {
+				return nil, fmt.Errorf("index %v out of range", i)
+			}
+			return normalize(x[int(f)]), nil
+		case map[string]any:
+			k, ok := i.(string)
+			if !ok {
+				return nil, fmt.Errorf("map key must be a string, got %s", typeName(i))
+			}
+			return normalize(x[k]), nil
+		case nil:
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cannot index %s", typeName(x))
+	case *callNode:
+		return ev.call(n, s)
+	case *macroNode:
+		return ev.macro(n, s)
+	}
+	return nil, fmt.Errorf("unsupported expression %T", n)
+}
+
+func (ev *evaluator) binary(n *binaryNode, s *scope) (any, error) {
+	l, err := ev.eval(n.l, s)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "&&" || n.op == "||" {
+		lb, ok := l.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s needs bools, got %s", n.op, typeName(l))
+		}
+		if (n.op == "&&" && !lb) || (n.op == "||" && lb) {
+			return lb, nil
+		}
+		r, err := ev.eval(n.r, s)
+		if err != nil {
+			return nil, err
+		}
+		rb, ok := r.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s needs bools, got %s", n.op, typeName(r))
+		}
+		return rb, nil
+	}
+	r, err := ev.eval(n.r, s)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return equal(l, r), nil
+	case "!=":
+		return !equal(l, r), nil
+	case "in":
+		switch c := r.(type) {
+		case []any:
+			for _, e := range c {
+				if equal(l, normalize(e)) {
+					return true, nil
+				}
+			}
+			return false, nil
+		case map[string]any:
+			k, ok := l.(string)
+			if !ok {
+				return false, nil
+			}
+			_, ok = c[k]
+			return ok, nil
+		case nil:
+			return false, nil
+		}
+		return nil, fmt.Errorf("in needs a list or map, got %s", typeName(r))
+	case "<", "<=", ">", ">=":
+		// Comparisons with null (e.g. a missing attribute) are false rather than an error.
+		if l == nil || r == nil {
+			return false, nil
+		}
+		c, err := compare(l, r)
+		if err != nil {
+			return nil, err
+		}
+		switch n.op {
+		case "<":
+			return c < 0, nil
+		case "<=":
+			return c <= 0, nil
+		case ">":
+			return c > 0, nil
+		default:
+			return c >= 0, nil
+		}
+	case "+":
+		switch lv := l.(type) {
+		case string:
+			if rv, ok := r.(string); ok {
+				return lv + rv, nil
+			}
+		case []any:
+			if rv, ok := r.([]any); ok {
+				return append(append([]any{}, lv...), rv...), nil
+			}
+		}
+	}
+	lf, lok := l.(float64)
+	rf, rok := r.(float64)
+	if !lok || !rok {
+		return nil, fmt.Errorf("%s needs numbers, got %s and %s", n.op, typeName(l), typeName(r))
+	}
+	switch n.op {
+	case "+":
+		return lf + rf, nil
+	case "-":
+		return lf - rf, nil
+	case "*":
+		return lf * rf, nil
+	case "/":
+		if rf == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return lf / rf, nil
+	case "%":
+		if rf == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return math.Mod(lf, rf), nil
+	}
+	return nil, fmt.Errorf("unsupported operator %s", n.op)
+}
+
+func (ev *evaluator) call(n *callNode, s *scope) (any, error) {
+	args := make([]any, len(n.args))
+	for i, a := range n.args {
+		v, err := ev.eval(a, s)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	if n.recv == nil {
+		return ev.function(n.fn, args[0])
+	}
+	recv, err := ev.eval(n.recv, s)
+	if err != nil {
+		return nil, err
+	}
+	if n.fn == "size" {
+		return ev.function("size", recv)
+	}
+	str, ok := recv.(string)
+	if !ok {
+		if recv == nil {
+			return false, nil
+		}
+		return nil, fmt.Errorf("%s needs a string receiver, got %s", n.fn, typeName(recv))
+	}
+	switch n.fn {
+	case "lowerAscii":
+		return strings.ToLower(str), nil
+	case "upperAscii":
+		return strings.ToUpper(str), nil
+	}
+	arg, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("%s needs a string argument, got %s", n.fn, typeName(args[0]))
+	}
+	switch n.fn {
+	case "startsWith":
+		return strings.HasPrefix(str, arg), nil
+	case "endsWith":
+		return strings.HasSuffix(str, arg), nil
+	case "contains":
+		return strings.Contains(str, arg), nil
+	case "matches":
+		re, err := ev.regexp(arg)
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString(str), nil
+	}
+	return nil, fmt.Errorf("unknown method %q", n.fn)
+}
+
+func (ev *evaluator) function(fn string, x any) (any, error) {
+	switch fn {
+	case "size":
+		switch v := x.(type) {
+		case string:
+			return float64(len(v)), nil
+		case []any:
+			return float64(len(v)), nil
+		case map[string]any:
+			return float64(len(v)), nil
+		case nil:
+			return float64(0), nil
+		}
+		return nil, fmt.Errorf("size of %s", typeName(x))
+	case "int", "double":
+		var f float64
+		switch v := x.(type) {
+		case float64:
+			f = v
+		case bool:
+			if v {
+				f = 1
+			}
+		case string:
+			p, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s(%q): not a number", fn, v)
+			}
+			f = p
+		default:
+			return nil, fmt.Errorf("%s of %s", fn, typeName(x))
+		}
+		if fn == "int" {
+			f = math.Trunc(f)
+		}
+		return f, nil
+	case "string":
+		switch v := x.(type) {
+		case string:
+			return v, nil
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		case bool:
+			return strconv.FormatBool(v), nil
+		case nil:
+			return "", nil
+		}
+		return nil, fmt.Errorf("string of %s", typeName(x))
+	}
+	return nil, fmt.Errorf("unknown function %q", fn)
+}
+
+func (ev *evaluator) macro(n *macroNode, s *scope) (any, error) {
+	recv, err := ev.eval(n.recv, s)
+	if err != nil {
+		return nil, err
+	}
+	var items []any
+	switch r := recv.(type) {
+	case []any:
+		items = r
+	case map[string]any:
+		for k := range r {
+			items = append(items, k)
+		}
+	case nil:
+	default:
+		return nil, fmt.Errorf("%s needs a list, got %s", n.fn, typeName(recv))
+	}
+	var (
+		count  int
+		mapped []any
+	)
+	for _, it := range items {
+		v, err := ev.eval(n.body, &scope{name: n.v, val: it, parent: s})
+		if err != nil {
+			return nil, err
+		}
+		if n.fn == "map" {
+			mapped = append(mapped, v)
+			continue
+		}
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s predicate must be a bool, got %s", n.fn, typeName(v))
+		}
+		switch n.fn {
+		case "exists":
+			if b {
+				return true, nil
+			}
+		case "all":
+			if !b {
+				return false, nil
+			}
+		case "filter":
+			if b {
+				mapped = append(mapped, it)
+			}
+		case "exists_one":
+			if b {
+				count++
+			}
+		}
+	}
+	switch n.fn {
+	case "exists":
+		return false, nil
+	case "all":
+		return true, nil
+	case "exists_one":
+		return count == 1, nil
+	}
+	if mapped == nil {
+		mapped = []any{}
+	}
+	return mapped, nil
+}
+
+func (ev *evaluator) regexp(pattern string) (*regexp.Regexp, error) {
+	if re, ok := ev.regexps[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("matches: %w", err)
+	}
+	if ev.regexps == nil {
+		ev.regexps = map[string]*regexp.Regexp{}
+	}
+	ev.regexps[pattern] = re
+	return re, nil
+}
+
+// field selects name from a map; a missing key (or a null receiver) yields null.
+func field(x any, name string) (any, error) {
+	switch m := x.(type) {
+	case map[string]any:
+		return normalize(m[name]), nil
+	case nil:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("cannot select .%s on %s", name, typeName(x))
+}
+
+// normalize maps Go values onto the expression types: null, bool, float64, string, []any, map[string]any.
+func normalize(v any) any {
+	switch x := v.(type) {
+	case nil, bool, float64, string, []any, map[string]any:
+		return v
+	case int:
+		return float64(x)
+	case int32:
+		return float64(x)
+	case int64:
+		return float64(x)
+	case float32:
+		return float64(x)
+	case uint:
+		return float64(x)
+	case uint64:
+		return float64(x)
+	case []string:
+		out := make([]any, len(x))
+		for i, s := range x {
+			out[i] = s
+		}
+		return out
+	case map[string]string:
+		out := make(map[string]any, len(x))
+		for k, s := range x {
+			out[k] = s
+		}
+		return out
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String()
+	case reflect.Map:
+		if rv.Type().Key().Kind() == reflect.String {
+			out := make(map[string]any, rv.Len())
+			iter := rv.MapRange()
+			for iter.Next() {
+				out[iter.Key().String()] = iter.Value().Interface()
+			}
+			return out
+		}
+	case reflect.Slice:
+		out := make([]any, rv.Len())
+		for i := range out {
+			out[i] = rv.Index(i).Interface()
+		}
+		return out
+	}
+	return v
+}
+
+// equal compares scalars and lists by value and maps by identity (node and edge maps are shared).
+func equal(a, b any) bool {
+	switch av := a.(type) {
+	case []any:
+		bv, ok := b.([]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !equal(normalize(av[i]), normalize(bv[i])) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		return ok && reflect.ValueOf(av).Pointer() == reflect.ValueOf(bv).Pointer()
+	}
+	if _, ok := b.(map[string]any); ok {
+		return false
+	}
+	if _, ok := b.([]any); ok {
+		return false
+	}
+	return a == b
+}
+
+func compare(a, b any) (int, error) {
+	switch av := a.(type) {
+	case float64:
+		if bv, ok := b.(float64); ok {
+			switch {
+			case av < bv:
+				return -1, nil
+			case av > bv:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	case string:
+		if bv, ok := b.(string); ok {
+			return strings.Compare(av, bv), nil
+		}
+	}
+	return 0, fmt.Errorf("cannot compare %s with %s", typeName(a), typeName(b))
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "list"
+	case map[string]any:
+		return "map"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,74 @@
+// Package expr is a small CEL-like expression language for user-defined detection rules.
+//
+// Supported syntax:
+//
+//	literals     "s", 's', 1, 2.5, true, false, null, [a, b]
+//	operators    ! - * / % + - == != < <= > >= in && ||
+//	selection    node.kind, node.attrs.team, node.attrs["team"], list[0]
+//	has()        has(node.attrs.team) reports whether the field is present
+//	functions    size(x), int(x), double(x), string(x)
+//	methods      x.size(), s.startsWith(p), s.endsWith(p), s.contains(p), s.matches(re),
+//	             s.lowerAscii(), s.upperAscii()
+//	macros       list.exists(v, pred), list.all(v, pred), list.exists_one(v, pred),
+//	             list.filter(v, pred), list.map(v, expr)
+//
+// All numbers are float64. Selecting a missing field yields null, and ordering comparisons
+// involving null are false, so rules over optional attributes do not need has() guards.
+package expr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Vars binds top-level variable names to values (maps, lists, scalars).
+type Vars map[string]any
+
+// Program is a compiled expression.
+type Program struct {
+	src  string
+	root node
+}
+
+// Compile parses src and checks that it only references the declared variables and known
+// functions. Errors are meant to be shown to the rule author.
+func Compile(src string, declared ...string) (*Program, error) {
+	if strings.TrimSpace(src) == "" {
+		return nil, fmt.Errorf("expression is empty")
+	}
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks, scope: append([]string(nil), declared...)}
+	root, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tEOF {
+		return nil, p.errorf("unexpected trailing input")
+	}
+	return &Program{src: src, root: root}, nil
+}
+
+// String returns the source the program was compiled from.
+func (p *Program) String() string { return p.src }
+
+// Eval evaluates the program with vars bound.
+func (p *Program) Eval(vars Vars) (any, error) {
+	var ev evaluator
+	return ev.eval(p.root, &scope{vars: vars})
+}
+
+// EvalBool evaluates the program and requires a bool result.
+func (p *Program) EvalBool(vars Vars) (bool, error) {
+	v, err := p.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression must evaluate to a bool, got %s", typeName(v))
+	}
+	return b, nil
+}
@@ -0,0 +1,77 @@
+package expr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEval(t *testing.T) {
+	svc := map[string]any{"kind": "SERVICE", "name": "pay", "attrs": map[string]any{"rate": 12}}
+	ext := map[string]any{"kind": "EXTERNAL_SYSTEM", "name": "stripe"}
+	svc["out"] = []any{map[string]any{"sync": true, "to": ext}}
+	vars := Vars{"node": svc}
+
+	cases := []struct {
+		src  string
+		want any
+	}{
+		{`node.kind == "SERVICE" && node.out.exists(e, e.sync && e.to.kind == "EXTERNAL_SYSTEM")`, true},
+		{`node.attrs.rate > 10 && node.attrs.rate <= 12`, true},
+		{`node.attrs.missing > 1`, false},
+		{`has(node.attrs.rate) && !has(node.attrs.team)`, true},
+		{`node.name in ["pay", "orders"]`, true},
+		{`node.name.startsWith("pa") && node.name.matches("^p.y$")`, true},
+		{`size(node.out) + node.out.size()`, 2.0},
+		{`node.out.filter(e, e.sync).size() == 1`, true},
+		{`node.out.map(e, e.to.name)[0]`, "stripe"},
+		{`node.out.all(e, e.to == node)`, false},
+		{`int("7.9") * 2 - 1`, 13.0},
+		{`string(3) + "x"`, "3x"},
+	}
+	for _, tc := range cases {
+		p, err := Compile(tc.src, "node")
+		if err != nil {
+			t.Fatalf("%s: compile: %v", tc.src, err)
+		}
+		got, err := p.Eval(vars)
+		if err != nil {
+			t.Fatalf("%s: eval: %v", tc.src, err)
+		}
+		if got != tc.want {
+			t.Fatalf("%s: got %#v, want %#v", tc.src, got, tc.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	cases := map[string]string{
+		`edge.kind == "CALLS"`:     "undeclared variable",
+		`node.kind ==`:             "unexpected token",
+		`node.name.frobnicate()`:   "unknown function",
+		`size()`:                   "expects 1 argument",
+		`node.name.matches("(")`:   "matches",
+		`node.kind == "SERVICE" )`: "trailing input",
+		`"unterminated`:            "unterminated",
+		``:                         "empty",
+	}
+	for src, want := range cases {
+		_, err := Compile(src, "node")
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%q: want error containing %q, got %v", src, want, err)
+		}
+	}
+}
+
+func TestEvalBoolRequiresBool(t *testing.T) {
+	p, err := Compile(`node.name`, "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.EvalBool(Vars{"node": map[string]any{"name": "x"}}); err == nil {
+		t.Fatal("expected non-bool error")
+	}
+	p, _ = Compile(`node.name < 3`, "node")
+	if _, err := p.EvalBool(Vars{"node": map[string]any{"name": "x"}}); err == nil {
+		t.Fatal("expected type error comparing string with number")
+	}
+}
@@ -0,0 +1,390 @@
+package expr
+
+import (
+	"fmt"
+	"regexp"
+)
+
+type node interface{}
+
+type (
+	litNode   struct{ v any }
+	identNode struct{ name string }
+	listNode  struct{ elems []node }
+	unaryNode struct {
+		op string
+		x  node
+	}
+	binaryNode struct {
+		op   string
+		l, r node
+	}
+	memberNode struct {
+		x    node
+		name string
+	}
+	indexNode struct{ x, i node }
+	// callNode is a function (recv == nil) or method call.
+	callNode struct {
+		recv node
+		fn   string
+		args []node
+	}
+	// macroNode is recv.exists(v, body) and friends; body is evaluated with v bound to each element.
+	macroNode struct {
+		recv node
+		fn   string
+		v    string
+		body node
+	}
+	hasNode struct{ m *memberNode }
+)
+
+var macros = map[string]bool{"exists": true, "all": true, "exists_one": true, "filter": true, "map": true}
+
+// methods maps each method name to its argument count.
+var methods = map[string]int{
+	"size": 0, "startsWith": 1, "endsWith": 1, "contains": 1, "matches": 1,
+	"lowerAscii": 0, "upperAscii": 0,
+}
+
+// functions maps each global function name to its argument count.
+var functions = map[string]int{"size": 1, "int": 1, "double": 1, "string": 1}
+
+type parser struct {
+	toks  []token
+	pos   int
+	scope []string
+}
+
+func (p *parser) peek() token { return p.toks[p.pos] }
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isOp(op string) bool {
+	t := p.peek()
+	return t.kind == tOp && t.text == op
+}
+
+func (p *parser) accept(op string) bool {
+	if p.isOp(op) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(op string) error {
+	if !p.accept(op) {
+		return p.errorf("expected %q", op)
+	}
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	t := p.peek()
+	where := "end of expression"
+	if t.kind != tEOF {
+		where = fmt.Sprintf("%q at %d", t.text, t.pos)
+	}
+	return fmt.Errorf("%s (near %s)", fmt.Sprintf(format, args...), where)
+}
+
+func (p *parser) declared(name string) bool {
+	for i := len(p.scope) - 1; i >= 0; i-- {
+		if p.scope[i] == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) parseExpr() (node, error) { return p.parseOr() }
+
+func (p *parser) parseOr() (node, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = &binaryNode{op: "||", l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	l, err := p.parseRel()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		r, err := p.parseRel()
+		if err != nil {
+			return nil, err
+		}
+		l = &binaryNode{op: "&&", l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseRel() (node, error) {
+	l, err := p.parseAdd()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	op := ""
+	switch {
+	case t.kind == tOp && (t.text == "==" || t.text == "!=" || t.text == "<" || t.text == "<=" || t.text == ">" || t.text == ">="):
+		op = t.text
+	case t.kind == tIdent && t.text == "in":
+		op = "in"
+	default:
+		return l, nil
+	}
+	p.next()
+	r, err := p.parseAdd()
+	if err != nil {
+		return nil, err
+	}
+	return &binaryNode{op: op, l: l, r: r}, nil
+}
+
+func (p *parser) parseAdd() (node, error) {
+	l, err := p.parseMul()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("+") || p.isOp("-") {
+		op := p.next().text
+		r, err := p.parseMul()
+		if err != nil {
+			return nil, err
+		}
+		l = &binaryNode{op: op, l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseMul() (node, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("*") || p.isOp("/") || p.isOp("%") {
+		op := p.next().text
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l = &binaryNode{op: op, l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.isOp("!") || p.isOp("-") {
+		op := p.next().text
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: op, x: x}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (node, error) {
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.accept("."):
+			t := p.next()
+			if t.kind != tIdent {
+				return nil, p.errorf("expected field or method name")
+			}
+			if !p.isOp("(") {
+				x = &memberNode{x: x, name: t.text}
+				continue
+			}
+			p.next()
+			if macros[t.text] {
+				x, err = p.parseMacro(x, t.text)
+			} else {
+				x, err = p.parseCall(x, t.text)
+			}
+			if err != nil {
+				return nil, err
+			}
+		case p.accept("["):
+			i, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			x = &indexNode{x: x, i: i}
+		default:
+			return x, nil
+		}
+	}
+}
+
+// parseMacro parses "v, body)" after recv.fn( .
+func (p *parser) parseMacro(recv node, fn string) (node, error) {
+	t := p.next()
+	if t.kind != tIdent {
+		return nil, p.errorf("%s: expected a variable name", fn)
+	}
+	if err := p.expect(","); err != nil {
+		return nil, err
+	}
+	p.scope = append(p.scope, t.text)
+	body, err := p.parseExpr()
+	p.scope = p.scope[:len(p.scope)-1]
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return &macroNode{recv: recv, fn: fn, v: t.text, body: body}, nil
+}
+
+// parseCall parses "args)" after fn( ; recv is nil for global functions.
+func (p *parser) parseCall(recv node, fn string) (node, error) {
+	var args []node
+	if !p.accept(")") {
+		for {
+			a, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, a)
+			if p.accept(")") {
+				break
+			}
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+	}
+	want, ok := functions[fn]
+	if recv != nil {
+		want, ok = methods[fn]
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q", fn)
+	}
+	if len(args) != want {
+		return nil, fmt.Errorf("%s expects %d argument(s), got %d", fn, want, len(args))
+	}
+	if lit, ok := firstLit(args); ok && fn == "matches" {
+		if s, ok := lit.(string); ok {
+			if _, err := regexp.Compile(s); err != nil {
+				return nil, fmt.Errorf("matches: %w", err)
+			}
+		}
+	}
+	return &callNode{recv: recv, fn: fn, args: args}, nil
+}
+
+func firstLit(args []node) (any, bool) {
+	if len(args) == 0 {
+		return nil, false
+	}
+	l, ok := args[0].(*litNode)
+	if !ok {
+		return nil, false
+	}
+	return l.v, true
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	start := p.pos
+	t := p.next()
+	switch t.kind {
+	case tNumber:
+		return &litNode{v: t.num}, nil
+	case tString:
+		return &litNode{v: t.text}, nil
+	case tIdent:
+		switch t.text {
+		case "true":
+			return &litNode{v: true}, nil
+		case "false":
+			return &litNode{v: false}, nil
+		case "null":
+			return &litNode{v: nil}, nil
+		case "has":
+			if err := p.expect("("); err != nil {
+				return nil, err
+			}
+			x, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			m, ok := x.(*memberNode)
+			if !ok {
+				return nil, fmt.Errorf("has() needs a field selection like has(node.attrs.team)")
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return &hasNode{m: m}, nil
+		}
+		if p.accept("(") {
+			return p.parseCall(nil, t.text)
+		}
+		if !p.declared(t.text) {
+			return nil, fmt.Errorf("undeclared variable %q", t.text)
+		}
+		return &identNode{name: t.text}, nil
+	case tOp:
+		switch t.text {
+		case "(":
+			x, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return x, nil
+		case "[":
+			var elems []node
+			if !p.accept("]") {
+				for {
+					e, err := p.parseExpr()
+					if err != nil {
+						return nil, err
+					}
+					elems = append(elems, e)
+					if p.accept("]") {
+						break
+					}
+					if err := p.expect(","); err != nil {
+						return nil, err
+					}
+				}
+			}
+			return &listNode{elems: elems}, nil
+		}
+	}
+	p.pos = start
+	return nil, p.errorf("unexpected token")
+}
@@ -0,0 +1,150 @@
+package rules
+
+import (
+	"sort"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+type chatty struct{}
+
+func (c chatty) Name() string { return "chatty_service" }
+
+// edgeRatePerMin reads rate_per_min (int from the mapper, float64 after a JSON round-trip).
+func edgeRatePerMin(e *domain.Edge) int {
+	if e == nil || e.Attrs == nil {
+		return 0
+	}
+	switch v := e.Attrs["rate_per_min"].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+func edgePerItem(e *domain.Edge) bool {
+	if e == nil || e.Attrs == nil {
+		return false
+	}
+	b, _ := e.Attrs["per_item"].(bool)
+	return b
+}
+
+func (c chatty) DefaultThresholds() map[string]float64 {
+	return map[string]float64{
+		"min_rate_per_min": 600,
+		"min_fanout":       3,
+	}
+}
+
+// Detect flags callers with high-rate or per-item synchronous calls (chatty APIs / N+1 calls).
+// One detection per caller; a caller issuing per-item calls to min_fanout or more targets is a fan-out N+1 loop.
+func (c chatty) Detect(g *domain.Graph, cfg *detection.DetectorConfig) ([]domain.Detection, error) {
+	defaults := c.DefaultThresholds()
+	minRate := cfg.IntThreshold(domain.APChattyService, "min_rate_per_min", defaults)
+	minFanout := cfg.IntThreshold(domain.APChattyService, "min_fanout", defaults)
+
+	isCaller := func(n *domain.Node) bool {
+		if n == nil {
+			return false
+		}
+		switch n.Kind {
+		case domain.NodeService, domain.NodeAPIGateway, domain.NodeClient:
+			return true
+		}
+		return false
+	}
+
+	var ids []string
+	for id, n := range g.Nodes {
+		if isCaller(n) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var out []domain.Detection
+	for _, id := range ids {
+		targets := []string{}
+		seen := map[string]bool{}
+		perItemSeen := map[string]bool{}
+		perItemIDs := []string{}
+		var calls []domain.Attrs
+		totalRate, highRateCalls := 0, 0
+
+		for _, e := range g.Out[id] {
+			if e == nil || e.Kind != domain.EdgeCalls || !edgeIsSync(e) {
+				continue
+			}
+			rate := edgeRatePerMin(e)
+			perItem := edgePerItem(e)
+			if !perItem && rate < minRate {
+				continue
+			}
+			calls = append(calls, domain.Attrs{"to": e.To, "rate_per_min": rate, "per_item": perItem})
+			totalRate += rate
+			if perItem && !perItemSeen[e.To] {
+				perItemSeen[e.To] = true
+				perItemIDs = append(perItemIDs, e.To)
+			}
+			if !perItem {
+				highRateCalls++
+			}
+			if !seen[e.To] {
+				seen[e.To] = true
+				targets = append(targets, e.To)
+			}
+		}
+		if len(calls) == 0 {
+			continue
+		}
+		perItemTargets := len(perItemSeen)
+
+		fanoutLoop := perItemTargets >= minFanout
+		sev := domain.SeverityMedium
+		title := "Chatty communication"
+		summary := "Service makes high-rate synchronous calls; batch, cache or make them async"
+		if perItemTargets > 0 {
+			sev = domain.SeverityHigh
+			title = "Chatty communication (N+1 calls)"
+			summary = "Service issues one synchronous call per item instead of a batched call"
+		}
+		if fanoutLoop {
+			summary = "Service fans out per-item synchronous calls to several services (N+1 fan-out loop)"
+		}
+
+		out = append(out, domain.Detection{
+			Kind:     domain.APChattyService,
+			Severity: sev,
+			Title:    title,
+			Summary:  summary,
+			Nodes:    append([]string{id}, targets...),
+			Edges:    chattyEdges(g, id, minRate),
+			Evidence: domain.Attrs{
+				"caller":              id,
+				"calls":               calls,
+				"total_rate":          totalRate,
+				"per_item_targets":    perItemTargets,
+				"per_item_target_ids": perItemIDs,
+				"high_rate_calls":     highRateCalls,
+				"fanout_loop":         fanoutLoop,
+				"min_rate_per_min":    minRate,
+				"min_fanout":          minFanout,
+			},
+		})
+	}
+	return out, nil
+}
+
+// chattyEdges returns the caller's sync CALLS edges that are per-item or at/above minRate.
+func chattyEdges(g *domain.Graph, caller string, minRate int) []int {
+	return collectEdges(g, func(e *domain.Edge) bool {
+		return e.Kind == domain.EdgeCalls && e.From == caller && edgeIsSync(e) &&
+			(edgePerItem(e) || edgeRatePerMin(e) >= minRate)
+	})
+}
+
+func init() { detection.Register(chatty{}) }
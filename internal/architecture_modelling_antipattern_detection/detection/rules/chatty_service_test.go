@@ -0,0 +1,80 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+func chattyGraph() *domain.Graph {
+	g := domain.NewGraph()
+	for _, n := range []string{"orders", "catalog", "pricing", "stock", "audit"} {
+		g.AddNode(&domain.Node{ID: "SERVICE:" + n, Name: n, Kind: domain.NodeService})
+	}
+	call := func(to string, attrs domain.Attrs) {
+		attrs["sync"] = true
+		g.AddEdge(&domain.Edge{From: "SERVICE:orders", To: "SERVICE:" + to, Kind: domain.EdgeCalls, Attrs: attrs})
+	}
+	call("catalog", domain.Attrs{"per_item": true})
+	call("pricing", domain.Attrs{"rate_per_min": 1200})
+	call("stock", domain.Attrs{"rate_per_min": 10})
+	g.AddEdge(&domain.Edge{From: "SERVICE:orders", To: "SERVICE:audit", Kind: domain.EdgeCalls,
+		Attrs: domain.Attrs{"sync": false, "rate_per_min": 5000.0}})
+	return g
+}
+
+func TestChattyService_FlagsPerItemAndHighRateSyncCalls(t *testing.T) {
+	dets, err := chatty{}.Detect(chattyGraph(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dets) != 1 {
+		t.Fatalf("expected one detection for orders, got %#v", dets)
+	}
+	d := dets[0]
+	if d.Severity != domain.SeverityHigh {
+		t.Fatalf("per_item call should make it HIGH, got %s", d.Severity)
+	}
+	want := []string{"SERVICE:orders", "SERVICE:catalog", "SERVICE:pricing"}
+	if len(d.Nodes) != len(want) {
+		t.Fatalf("nodes: want %v, got %v", want, d.Nodes)
+	}
+	for i := range want {
+		if d.Nodes[i] != want[i] {
+			t.Fatalf("nodes: want %v, got %v", want, d.Nodes)
+		}
+	}
+}
+
+func TestChattyService_ThresholdFromConfig(t *testing.T) {
+	g := chattyGraph()
+	cfg := &detection.DetectorConfig{Rules: map[domain.AntiPatternKind]detection.RuleConfig{
+		domain.APChattyService: {Thresholds: map[string]float64{"min_rate_per_min": 5}},
+	}}
+	dets, err := chatty{}.Detect(g, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dets) != 1 || len(dets[0].Nodes) != 4 {
+		t.Fatalf("lower min_rate_per_min should also flag stock (async audit stays out), got %#v", dets)
+	}
+}
+
+func TestChattyService_PerItemCallAfterHighRateCallToSameTarget(t *testing.T) {
+	g := domain.NewGraph()
+	for _, n := range []string{"orders", "pricing"} {
+		g.AddNode(&domain.Node{ID: "SERVICE:" + n, Name: n, Kind: domain.NodeService})
+	}
+	for _, attrs := range []domain.Attrs{{"rate_per_min": 1200}, {"per_item": true}} {
+		attrs["sync"] = true
+		g.AddEdge(&domain.Edge{From: "SERVICE:orders", To: "SERVICE:pricing", Kind: domain.EdgeCalls, Attrs: attrs})
+	}
+	dets, err := chatty{}.Detect(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dets) != 1 || dets[0].Severity != domain.SeverityHigh || dets[0].Evidence["per_item_targets"] != 1 {
+		t.Fatalf("the per-item call must count although pricing was already seen: %#v", dets)
+	}
+}
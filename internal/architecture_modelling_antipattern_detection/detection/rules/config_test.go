@@ -0,0 +1,98 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+func hubGraph(spokes int) *domain.Graph {
+	g := domain.NewGraph()
+	g.AddNode(&domain.Node{ID: "SERVICE:hub", Name: "hub", Kind: domain.NodeService})
+	for i := 0; i < spokes; i++ {
+		id := "SERVICE:s" + string(rune('a'+i))
+		g.AddNode(&domain.Node{ID: id, Name: id, Kind: domain.NodeService})
+		g.AddEdge(&domain.Edge{From: "SERVICE:hub", To: id, Kind: domain.EdgeCalls, Attrs: domain.Attrs{"sync": false}})
+	}
+	return g
+}
+
+func countKind(dets []domain.Detection, k domain.AntiPatternKind) int {
+	n := 0
+	for _, d := range dets {
+		if d.Kind == k {
+			n++
+		}
+	}
+	return n
+}
+
+func TestRunAll_DetectorConfigThresholdAndSeverity(t *testing.T) {
+	g := hubGraph(3)
+
+	dets, err := detection.RunAll(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if countKind(dets, domain.APGodService) != 0 {
+		t.Fatalf("default min_degree=4 should not flag a degree-3 hub")
+	}
+
+	cfg := &detection.DetectorConfig{Rules: map[domain.AntiPatternKind]detection.RuleConfig{
+		domain.APGodService: {Thresholds: map[string]float64{"min_degree": 3}, Severity: domain.SeverityHigh},
+	}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	dets, err = detection.RunAll(g, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if countKind(dets, domain.APGodService) != 1 {
+		t.Fatalf("expected god_service with min_degree=3, got %#v", dets)
+	}
+	for _, d := range dets {
+		if d.Kind == domain.APGodService && d.Severity != domain.SeverityHigh {
+			t.Fatalf("expected severity override HIGH, got %s", d.Severity)
+		}
+	}
+}
+
+func TestRunAll_DetectorConfigDisable(t *testing.T) {
+	g := hubGraph(4)
+	off := false
+	cfg := &detection.DetectorConfig{Rules: map[domain.AntiPatternKind]detection.RuleConfig{
+		domain.APGodService: {Enabled: &off},
+	}}
+	dets, err := detection.RunAll(g, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if countKind(dets, domain.APGodService) != 0 {
+		t.Fatalf("disabled god_service still reported: %#v", dets)
+	}
+}
+
+func TestDetectorConfig_ValidateAndEffective(t *testing.T) {
+	bad := []*detection.DetectorConfig{
+		{Rules: map[domain.AntiPatternKind]detection.RuleConfig{"no_such_kind": {}}},
+		{Rules: map[domain.AntiPatternKind]detection.RuleConfig{domain.APGodService: {Thresholds: map[string]float64{"nope": 1}}}},
+		{Rules: map[domain.AntiPatternKind]detection.RuleConfig{domain.APGodService: {Thresholds: map[string]float64{"min_degree": 0}}}},
+		{Rules: map[domain.AntiPatternKind]detection.RuleConfig{domain.APGodService: {Severity: "CRITICAL"}}},
+	}
+	for i, c := range bad {
+		if err := c.Validate(); err == nil {
+			t.Errorf("case %d: expected validation error", i)
+		}
+	}
+
+	eff := detection.Effective(nil)
+	rc, ok := eff.Rules[domain.APSyncCallChain]
+	if !ok || rc.Enabled == nil || !*rc.Enabled {
+		t.Fatalf("expected sync_call_chain enabled in effective config, got %#v", rc)
+	}
+	if rc.Thresholds["min_edges"] != 4 {
+		t.Fatalf("expected default min_edges=4, got %v", rc.Thresholds["min_edges"])
+	}
+}
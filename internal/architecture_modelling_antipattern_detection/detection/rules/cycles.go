@@ -9,82 +9,46 @@ type cycles struct{}
 
 func (c cycles) Name() string { return "cycles" }
 
-func (c cycles) Detect(g *domain.Graph) ([]domain.Detection, error) {
-	index := 0
-	stack := []string{}
-	onStack := map[string]bool{}
-	id := map[string]int{}
-	low := map[string]int{}
-	var dets []domain.Detection
-
-	var nodes []string
-	for _, n := range g.Nodes {
-		if n.Kind == domain.NodeService {
-			nodes = append(nodes, n.ID)
+func (c cycles) Detect(g *domain.Graph, _ *detection.DetectorConfig) ([]domain.Detection, error) {
+	adj := serviceCalls(g, func(*domain.Edge) bool { return true })
+
+	var comps [][]string
+	compOf := map[string]int{}
+	for _, comp := range domain.StronglyConnected(serviceIDs(g), adj) {
+		if len(comp) < 2 {
+			continue
 		}
+		for _, v := range comp {
+			compOf[v] = len(comps)
+		}
+		comps = append(comps, comp)
 	}
 
-	isSvc := func(nodeID string) bool {
-		n, ok := g.Nodes[nodeID]
-		return ok && n.Kind == domain.NodeService
-	}
-
-	var dfs func(v string)
-	dfs = func(v string) {
-		index++
-		id[v], low[v] = index, index
-		stack = append(stack, v)
-		onStack[v] = true
-
-		for _, e := range g.Out[v] {
-			if e.Kind != domain.EdgeCalls {
-				continue
-			}
-			w := e.To
-			if !isSvc(w) {
-				continue
-			}
-
-			if _, seen := id[w]; !seen {
-				dfs(w)
-				if low[w] < low[v] {
-					low[v] = low[w]
-				}
-			} else if onStack[w] && id[w] < low[v] {
-				low[v] = id[w]
-			}
+	// One pass over the edges for all components (edgesAmong per component is quadratic).
+	edges := make([][]int, len(comps))
+	for i, e := range g.Edges {
+		if e == nil || e.Kind != domain.EdgeCalls {
+			continue
 		}
-
-		if low[v] == id[v] {
-			comp := []string{}
-			for {
-				w := stack[len(stack)-1]
-				stack = stack[:len(stack)-1]
-				onStack[w] = false
-				comp = append(comp, w)
-				if w == v {
-					break
-				}
-			}
-			if len(comp) > 1 {
-				dets = append(dets, domain.Detection{
-					Kind:     domain.APCycles,
-					Severity: domain.SeverityHigh,
-					Title:    "Cyclic dependency",
-					Summary:  "A loop of service dependencies was detected",
-					Nodes:    comp,
-					Evidence: domain.Attrs{"cycle_size": len(comp)},
-				})
-			}
+		from, ok1 := compOf[e.From]
+		to, ok2 := compOf[e.To]
+		if ok1 && ok2 && from == to {
+			edges[from] = append(edges[from], i)
 		}
 	}
 
-	for _, v := range nodes {
-		if _, seen := id[v]; !seen {
-			dfs(v)
-		}
+	var dets []domain.Detection
+	for i, comp := range comps {
+		dets = append(dets, domain.Detection{
+			Kind:     domain.APCycles,
+			Severity: domain.SeverityHigh,
+			Title:    "Cyclic dependency",
+			Summary:  "A loop of service dependencies was detected",
+			Nodes:    comp,
+			Edges:    edges[i],
+			Evidence: domain.Attrs{"cycle_size": len(comp)},
+		})
 	}
-
 	return dets, nil
 }
 
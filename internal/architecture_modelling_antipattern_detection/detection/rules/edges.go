@@ -0,0 +1,38 @@
+package rules
+
+import "github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+
+// Detection.Edges holds indexes into g.Edges. These helpers collect them for the rules.
+
+// collectEdges returns the indexes (ascending) of the edges of g for which keep is true.
+func collectEdges(g *domain.Graph, keep func(e *domain.Edge) bool) []int {
+	out := []int{}
+	for i, e := range g.Edges {
+		if e != nil && keep(e) {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// edgesAmong returns the CALLS edges whose both endpoints are in ids.
+func edgesAmong(g *domain.Graph, ids ...string) []int {
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return collectEdges(g, func(e *domain.Edge) bool {
+		return e.Kind == domain.EdgeCalls && set[e.From] && set[e.To]
+	})
+}
+
+// edgesBetween returns the CALLS edges from `from` to any of `to`.
+func edgesBetween(g *domain.Graph, from string, to ...string) []int {
+	set := make(map[string]bool, len(to))
+	for _, id := range to {
+		set[id] = true
+	}
+	return collectEdges(g, func(e *domain.Edge) bool {
+		return e.Kind == domain.EdgeCalls && e.From == from && set[e.To]
+	})
+}
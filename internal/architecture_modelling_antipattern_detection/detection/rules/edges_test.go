@@ -0,0 +1,60 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+// edgeGraph: a <-> b (sync), b -> c -> d -> e (sync chain), a/b/c all use db, plus an unrelated async a -> x.
+func edgeGraph() *domain.Graph {
+	g := domain.NewGraph()
+	for _, n := range []string{"a", "b", "c", "d", "e", "x"} {
+		g.AddNode(&domain.Node{ID: "SERVICE:" + n, Name: n, Kind: domain.NodeService})
+	}
+	g.AddNode(&domain.Node{ID: "DATABASE:db", Name: "db", Kind: domain.NodeDB})
+	call := func(from, to string, sync bool) {
+		g.AddEdge(&domain.Edge{From: from, To: to, Kind: domain.EdgeCalls, Attrs: domain.Attrs{"sync": sync}})
+	}
+	call("SERVICE:a", "SERVICE:b", true)   // 0
+	call("SERVICE:b", "SERVICE:a", true)   // 1
+	call("SERVICE:b", "SERVICE:c", true)   // 2
+	call("SERVICE:c", "SERVICE:d", true)   // 3
+	call("SERVICE:d", "SERVICE:e", true)   // 4
+	call("SERVICE:a", "DATABASE:db", true) // 5
+	call("SERVICE:b", "DATABASE:db", true) // 6
+	call("SERVICE:c", "DATABASE:db", true) // 7
+	call("SERVICE:a", "SERVICE:x", false)  // 8
+	return g
+}
+
+func TestDetectors_ReportEdges(t *testing.T) {
+	dets, err := detection.RunAll(edgeGraph(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[domain.AntiPatternKind][]int{
+		domain.APCycles:             {0, 1},
+		domain.APPingPongDependency: {0, 1},
+		domain.APSharedDatabase:     {5, 6, 7},
+		domain.APSyncCallChain:      {0, 2, 3, 4},
+	}
+	got := map[domain.AntiPatternKind][]int{}
+	for _, d := range dets {
+		if _, ok := want[d.Kind]; ok {
+			got[d.Kind] = d.Edges
+		}
+	}
+	for k, w := range want {
+		g := got[k]
+		if len(g) != len(w) {
+			t.Fatalf("%s: edges %v, want %v", k, g, w)
+		}
+		for i := range w {
+			if g[i] != w[i] {
+				t.Fatalf("%s: edges %v, want %v", k, g, w)
+			}
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package rules
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
+)
+
+// Topic edges: service → topic publishes (unless dep kind says subscribe/consume),
+// topic → service delivers to a consumer.
+
+// IsSubscribeDepKind reports whether a dependency kind marks service → topic as a subscription
+// (used by suggestion auto-fix).
+func IsSubscribeDepKind(kind string) bool {
+	switch strings.ToLower(strings.TrimSpace(kind)) {
+	case "subscribe", "subscribes", "consume", "consumes", "consumer", "listen", "listens":
+		return true
+	}
+	return false
+}
+
+func isSubscribeKind(e *domain.Edge) bool {
+	if e == nil || e.Attrs == nil {
+		return false
+	}
+	k, _ := e.Attrs["dep_kind"].(string)
+	return IsSubscribeDepKind(k)
+}
+
+// topicEdges returns the ids publishing to and consuming from topic, sorted and deduped.
+func topicEdges(g *domain.Graph, topic string) (producers, consumers []string) {
+	p := map[string]bool{}
+	c := map[string]bool{}
+	for _, e := range g.In[topic] {
+		if e == nil || e.Kind != domain.EdgeCalls || e.From == topic {
+			continue
+		}
+		if isSubscribeKind(e) {
+			c[e.From] = true
+		} else {
+			p[e.From] = true
+		}
+	}
+	for _, e := range g.Out[topic] {
+		if e == nil || e.Kind != domain.EdgeCalls || e.To == topic {
+			continue
+		}
+		c[e.To] = true
+	}
+	return parser.SortedSet(p), parser.SortedSet(c)
+}
+
+func topicIDs(g *domain.Graph) []string {
+	var ids []string
+	for id, n := range g.Nodes {
+		if n != nil && n.Kind == domain.NodeEventTopic {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+type orphanTopic struct{}
+
+func (o orphanTopic) Name() string { return "orphan_event_topic" }
+
+// Detect flags topics that are published to but never consumed, or consumed but never published to.
+func (o orphanTopic) Detect(g *domain.Graph, _ *detection.DetectorConfig) ([]domain.Detection, error) {
+	var out []domain.Detection
+	for _, t := range topicIDs(g) {
+		producers, consumers := topicEdges(g, t)
+		var reason, title, summary string
+		sev := domain.SeverityMedium
+		switch {
+		case len(producers) > 0 && len(consumers) == 0:
+			reason, title = "no_consumers", "Event topic without consumers"
+			summary = "Events are published to this topic but nothing consumes them"
+		case len(producers) == 0 && len(consumers) > 0:
+			reason, title = "no_producers", "Event topic without producers"
+			summary = "Services wait on this topic but nothing publishes to it"
+		case len(producers) == 0 && len(consumers) == 0:
+			reason, title = "unused", "Unused event topic"
+			summary = "Topic is declared but has no producers or consumers"
+			sev = domain.SeverityLow
+		default:
+			continue
+		}
+		nodes := append([]string{t}, producers...)
+		nodes = append(nodes, consumers...)
+		out = append(out, domain.Detection{
+			Kind:     domain.APOrphanEventTopic,
+			Severity: sev,
+			Title:    title,
+			Summary:  summary,
+			Nodes:    nodes,
+			Edges:    collectEdges(g, func(e *domain.Edge) bool { return e.Kind == domain.EdgeCalls && (e.From == t || e.To == t) }),
+			Evidence: domain.Attrs{
+				"topic":     t,
+				"reason":    reason,
+				"producers": producers,
+				"consumers": consumers,
+			},
+		})
+	}
+	return out, nil
+}
+
+type selfConsuming struct{}
+
+func (s selfConsuming) Name() string { return "self_consuming_service" }
+
+// Detect flags services that publish to and consume from the same topic.
+func (s selfConsuming) Detect(g *domain.Graph, _ *detection.DetectorConfig) ([]domain.Detection, error) {
+	var out []domain.Detection
+	for _, t := range topicIDs(g) {
+		producers, consumers := topicEdges(g, t)
+		isConsumer := map[string]bool{}
+		for _, c := range consumers {
+			isConsumer[c] = true
+		}
+		for _, p := range producers {
+			if !isConsumer[p] {
+				continue
+			}
+			out = append(out, domain.Detection{
+				Kind:     domain.APSelfConsumingService,
+				Severity: domain.SeverityMedium,
+				Title:    "Service consumes its own events",
+				Summary:  "Service publishes to and consumes from the same topic; use an in-process call or split the consumer",
+				Nodes:    []string{p, t},
+				Edges:    edgesAmong(g, p, t),
+				Evidence: domain.Attrs{"service": p, "topic": t},
+			})
+		}
+	}
+	return out, nil
+}
+
+type eventLoop struct{}
+
+func (l eventLoop) Name() string { return "event_loop" }
+
+// Detect flags dependency cycles between services that only close through event topics.
+// A producer reaches every consumer of a topic it publishes to; cycles already closed by
+// direct service calls are left to the cycles rule.
+func (l eventLoop) Detect(g *domain.Graph, _ *detection.DetectorConfig) ([]domain.Detection, error) {
+	isSvc := func(id string) bool {
+		n, ok := g.Nodes[id]
+		return ok && n != nil && n.Kind != domain.NodeEventTopic && n.Kind != domain.NodeDB
+	}
+
+	callAdj := map[string][]string{}
+	adj := map[string][]string{}
+	via := map[[2]string][]string{}
+	var ids []string
+	for id := range g.Nodes {
+		if isSvc(id) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	for _, v := range ids {
+		for _, e := range g.Out[v] {
+			if e == nil || e.Kind != domain.EdgeCalls || !isSvc(e.To) || e.To == v {
+				continue
+			}
+			callAdj[v] = append(callAdj[v], e.To)
+			adj[v] = append(adj[v], e.To)
+		}
+	}
+	for _, t := range topicIDs(g) {
+		producers, consumers := topicEdges(g, t)
+		for _, p := range producers {
+			for _, c := range consumers {
+				if p == c || !isSvc(p) || !isSvc(c) {
+					continue
+				}
+				key := [2]string{p, c}
+				if len(via[key]) == 0 {
+					adj[p] = append(adj[p], c)
+				}
+				via[key] = append(via[key], t)
+			}
+		}
+	}
+
+	callComp := map[string]int{}
+	for i, comp := range domain.StronglyConnected(ids, callAdj) {
+		for _, v := range comp {
+			callComp[v] = i
+		}
+	}
+
+	var out []domain.Detection
+	for _, comp := range domain.StronglyConnected(ids, adj) {
+		if len(comp) < 2 {
+			continue
+		}
+		sameCallSCC := true
+		for _, v := range comp[1:] {
+			if callComp[v] != callComp[comp[0]] {
+				sameCallSCC = false
+				break
+			}
+		}
+		if sameCallSCC {
+			continue
+		}
+
+		topics := map[string]bool{}
+		var legs []domain.Attrs
+		for _, p := range comp {
+			for _, c := range comp {
+				for _, t := range via[[2]string{p, c}] {
+					topics[t] = true
+					legs = append(legs, domain.Attrs{"from": p, "topic": t, "to": c})
+				}
+			}
+		}
+		sort.Strings(comp)
+		topicList := parser.SortedSet(topics)
+		out = append(out, domain.Detection{
+			Kind:     domain.APEventLoop,
+			Severity: domain.SeverityHigh,
+			Title:    "Event loop through topics",
+			Summary:  "Services trigger each other in a loop via event topics; one event can cascade indefinitely",
+			Nodes:    append(append([]string{}, comp...), topicList...),
+			Edges:    edgesAmong(g, append(append([]string{}, comp...), topicList...)...),
+			Evidence: domain.Attrs{
+				"services":   comp,
+				"topics":     topicList,
+				"event_legs": legs,
+				"loop_size":  len(comp),
+			},
+		})
+	}
+	return out, nil
+}
+
+func init() {
+	detection.Register(orphanTopic{})
+	detection.Register(selfConsuming{})
+	detection.Register(eventLoop{})
+}
@@ -0,0 +1,95 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/mapper"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
+)
+
+const eventSpec = `
+services:
+  - name: orders
+  - name: billing
+  - name: audit
+topics:
+  - name: order-created
+  - name: invoice-paid
+  - name: audit-log
+  - name: legacy-feed
+dependencies:
+  - from: orders
+    to: order-created
+    kind: event
+  - from: order-created
+    to: billing
+    kind: event
+  - from: billing
+    to: invoice-paid
+    kind: event
+  - from: orders
+    to: invoice-paid
+    kind: subscribe
+  - from: audit
+    to: audit-log
+    kind: event
+  - from: audit
+    to: audit-log
+    kind: consume
+  - from: orders
+    to: legacy-feed
+    kind: event
+`
+
+func eventGraph(t *testing.T) *domain.Graph {
+	t.Helper()
+	spec, err := parser.ParseYAMLString(eventSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mapper.ToGraph(spec)
+}
+
+func TestEventTopics_OrphanAndSelfConsumption(t *testing.T) {
+	g := eventGraph(t)
+
+	dets, err := orphanTopic{}.Detect(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dets) != 1 || dets[0].Nodes[0] != "EVENT_TOPIC:legacy-feed" || dets[0].Evidence["reason"] != "no_consumers" {
+		t.Fatalf("expected legacy-feed without consumers, got %#v", dets)
+	}
+
+	dets, err = selfConsuming{}.Detect(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dets) != 1 || dets[0].Nodes[0] != "SERVICE:audit" {
+		t.Fatalf("expected audit consuming audit-log, got %#v", dets)
+	}
+}
+
+func TestEventTopics_LoopClosedOnlyThroughTopics(t *testing.T) {
+	g := eventGraph(t)
+
+	dets, err := eventLoop{}.Detect(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dets) != 1 {
+		t.Fatalf("expected one event loop, got %#v", dets)
+	}
+	if topics, _ := dets[0].Evidence["topics"].([]string); len(topics) != 2 {
+		t.Fatalf("expected loop through order-created and invoice-paid, got %#v", dets[0].Evidence)
+	}
+
+	cyc, err := cycles{}.Detect(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cyc) != 0 {
+		t.Fatalf("cycles rule should not see an event-only loop, got %#v", cyc)
+	}
+}
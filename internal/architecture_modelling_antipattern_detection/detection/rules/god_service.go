@@ -1,8 +1,6 @@
 package rules
 
 import (
-	"os"
-	"strconv"
 	"strings"
 
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection"
@@ -24,57 +22,55 @@ func isDatastoreLike(n *domain.Node) bool {
 		strings.Contains(s, " db")
 }
 
-func degreeSvcOnly(gr *domain.Graph, id string) int {
+// svcDegrees counts, per node, the CALLS edges to or from other services (datastore-like services
+// excluded) and collects those edges, in one pass over the graph. A self-call counts in both
+// directions but is listed once.
+func svcDegrees(gr *domain.Graph) (map[string]int, map[string][]int) {
 	isSvc := func(nodeID string) bool {
 		n, ok := gr.Nodes[nodeID]
-		return ok && n.Kind == domain.NodeService && !isDatastoreLike(n)
+		return ok && n != nil && n.Kind == domain.NodeService && !isDatastoreLike(n)
 	}
-
-	cnt := 0
-	for _, e := range gr.Out[id] {
+	degree := map[string]int{}
+	edges := map[string][]int{}
+	for i, e := range gr.Edges {
 		if e == nil || e.Kind != domain.EdgeCalls {
 			continue
 		}
 		if isSvc(e.To) {
-			cnt++
-		}
-	}
-	for _, e := range gr.In[id] {
-		if e == nil || e.Kind != domain.EdgeCalls {
-			continue
+			degree[e.From]++
+			edges[e.From] = append(edges[e.From], i)
 		}
 		if isSvc(e.From) {
-			cnt++
+			degree[e.To]++
+			if l := edges[e.To]; len(l) == 0 || l[len(l)-1] != i {
+				edges[e.To] = append(edges[e.To], i)
+			}
 		}
 	}
-	return cnt
+	return degree, edges
 }
 
-func (g god) Detect(gr *domain.Graph) ([]domain.Detection, error) {
-	thr := 4
-	if v := os.Getenv("DETECT_GOD_DEGREE"); v != "" {
-		if n, err := strconv.Atoi(v); err == nil && n > 0 {
-			thr = n
-		}
-	}
+func (g god) DefaultThresholds() map[string]float64 {
+	return map[string]float64{"min_degree": 4}
+}
+
+func (g god) Detect(gr *domain.Graph, cfg *detection.DetectorConfig) ([]domain.Detection, error) {
+	thr := cfg.IntThreshold(domain.APGodService, "min_degree", g.DefaultThresholds())
 
+	degree, edges := svcDegrees(gr)
 	var out []domain.Detection
-	for id, n := range gr.Nodes {
-		if n == nil || n.Kind != domain.NodeService {
+	for _, id := range serviceIDs(gr) {
+		if isDatastoreLike(gr.Nodes[id]) {
 			continue
 		}
-		if isDatastoreLike(n) {
-			continue
-		}
-
-		d := degreeSvcOnly(gr, id)
-		if d >= thr {
+		if d := degree[id]; d >= thr {
 			out = append(out, domain.Detection{
 				Kind:     domain.APGodService,
 				Severity: domain.SeverityMedium,
 				Title:    "God service (high centrality)",
 				Summary:  "Service has unusually high incoming/outgoing dependencies",
 				Nodes:    []string{id},
+				Edges:    edges[id],
 				Evidence: domain.Attrs{"degree": d, "threshold": thr},
 			})
 		}
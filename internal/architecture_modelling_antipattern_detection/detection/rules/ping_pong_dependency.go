@@ -9,7 +9,7 @@ type pingPong struct{}
 
 func (p pingPong) Name() string { return "ping_pong_dependency" }
 
-func (p pingPong) Detect(g *domain.Graph) ([]domain.Detection, error) {
+func (p pingPong) Detect(g *domain.Graph, _ *detection.DetectorConfig) ([]domain.Detection, error) {
 	isSvc := func(id string) bool {
 		n, ok := g.Nodes[id]
 		return ok && n != nil && n.Kind == domain.NodeService
@@ -63,6 +63,7 @@ func (p pingPong) Detect(g *domain.Graph) ([]domain.Detection, error) {
 			Title:    "Ping-pong dependency",
 			Summary:  "Two services depend on each other (mutual calls)",
 			Nodes:    []string{a, b},
+			Edges:    edgesAmong(g, a, b),
 			Evidence: domain.Attrs{"a": a, "b": b},
 		})
 	}
@@ -9,7 +9,7 @@ type reverseDep struct{}
 
 func (r reverseDep) Name() string { return "reverse_dependency" }
 
-func (r reverseDep) Detect(g *domain.Graph) ([]domain.Detection, error) {
+func (r reverseDep) Detect(g *domain.Graph, _ *detection.DetectorConfig) ([]domain.Detection, error) {
 	isSvc := func(id string) bool {
 		n, ok := g.Nodes[id]
 		return ok && n != nil && n.Kind == domain.NodeService
@@ -38,6 +38,7 @@ func (r reverseDep) Detect(g *domain.Graph) ([]domain.Detection, error) {
 					Title:    "Reverse dependency (backend → UI)",
 					Summary:  "Backend service depends on the UI/frontend layer",
 					Nodes:    []string{from, e.To},
+					Edges:    collectEdges(g, func(x *domain.Edge) bool { return x == e }),
 					Evidence: domain.Attrs{"from": from, "to": e.To},
 				})
 			}
@@ -0,0 +1,77 @@
+package rules
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+// syntheticGraph builds n services in layers of 20. Each service calls three services in the
+// next few layers (one call in ten async) and one in a hundred calls back to an earlier layer,
+// which creates sync cycles, like a large monorepo architecture.
+func syntheticGraph(n int, seed int64) *domain.Graph {
+	r := rand.New(rand.NewSource(seed))
+	g := domain.NewGraph()
+	id := func(i int) string { return fmt.Sprintf("SERVICE:s%05d", i) }
+	for i := 0; i < n; i++ {
+		g.AddNode(&domain.Node{ID: id(i), Name: fmt.Sprintf("s%05d", i), Kind: domain.NodeService})
+	}
+	const layer = 20
+	for i := 0; i < n; i++ {
+		next := (i/layer + 1) * layer
+		for k := 0; k < 3 && next < n; k++ {
+			to := next + r.Intn(min(3*layer, n-next))
+			g.AddEdge(&domain.Edge{From: id(i), To: id(to), Kind: domain.EdgeCalls,
+				Attrs: domain.Attrs{"sync": r.Intn(10) != 0, "count": 1 + r.Intn(3)}})
+		}
+		if i >= layer && r.Intn(100) == 0 {
+			g.AddEdge(&domain.Edge{From: id(i), To: id(r.Intn(i - i%layer)), Kind: domain.EdgeCalls,
+				Attrs: domain.Attrs{"sync": true}})
+		}
+	}
+	return g
+}
+
+func TestSyncChain_LargeGraph(t *testing.T) {
+	g := syntheticGraph(3000, 1)
+	dets, err := syncChain{}.Detect(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dets) < 2 {
+		t.Fatalf("expected several chains in a layered graph, got %d", len(dets))
+	}
+	for _, d := range dets {
+		seen := map[string]bool{}
+		for _, id := range d.Nodes {
+			if seen[id] {
+				t.Fatalf("chain revisits %s: %v", id, d.Nodes)
+			}
+			seen[id] = true
+		}
+		if len(d.Edges) < len(d.Nodes)-1 {
+			t.Fatalf("chain of %d nodes has edges %v", len(d.Nodes), d.Edges)
+		}
+	}
+}
+
+func benchmarkDetector(b *testing.B, d detection.Detector) {
+	for _, n := range []int{1000, 5000} {
+		g := syntheticGraph(n, 1)
+		b.Run(fmt.Sprintf("nodes=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := d.Detect(g, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSyncChain(b *testing.B)     { benchmarkDetector(b, syncChain{}) }
+func BenchmarkCycles(b *testing.B)        { benchmarkDetector(b, cycles{}) }
+func BenchmarkTightCoupling(b *testing.B) { benchmarkDetector(b, tight{}) }
+func BenchmarkGodService(b *testing.B)    { benchmarkDetector(b, god{}) }
@@ -0,0 +1,40 @@
+package rules
+
+import (
+	"sort"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+// serviceIDs returns the IDs of the service nodes of g, sorted so rules report deterministically.
+func serviceIDs(g *domain.Graph) []string {
+	var ids []string
+	for id, n := range g.Nodes {
+		if n != nil && n.Kind == domain.NodeService {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// serviceCalls returns, per service, the distinct services it calls (in edge order) through the
+// CALLS edges accepted by keep.
+func serviceCalls(g *domain.Graph, keep func(e *domain.Edge) bool) map[string][]string {
+	isSvc := func(id string) bool {
+		n, ok := g.Nodes[id]
+		return ok && n != nil && n.Kind == domain.NodeService
+	}
+	adj := map[string][]string{}
+	seen := map[[2]string]bool{}
+	for _, e := range g.Edges {
+		if e == nil || e.Kind != domain.EdgeCalls || !keep(e) || !isSvc(e.From) || !isSvc(e.To) {
+			continue
+		}
+		if k := [2]string{e.From, e.To}; !seen[k] {
+			seen[k] = true
+			adj[e.From] = append(adj[e.From], e.To)
+		}
+	}
+	return adj
+}
@@ -1,8 +1,6 @@
 package rules
 
 import (
-	"os"
-	"strconv"
 	"strings"
 
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection"
@@ -18,13 +16,12 @@ func isDBName(id string) bool {
 	return x == "database" || strings.Contains(x, "database") || strings.HasSuffix(x, "-db") || strings.Contains(x, " db")
 }
 
-func (s sharedDB) Detect(g *domain.Graph) ([]domain.Detection, error) {
-	minClients := 2
-	if v := os.Getenv("DETECT_SHARED_DB_MIN_CLIENTS"); v != "" {
-		if n, err := strconv.Atoi(v); err == nil && n > 0 {
-			minClients = n
-		}
-	}
+func (s sharedDB) DefaultThresholds() map[string]float64 {
+	return map[string]float64{"min_clients": 2}
+}
+
+func (s sharedDB) Detect(g *domain.Graph, cfg *detection.DetectorConfig) ([]domain.Detection, error) {
+	minClients := cfg.IntThreshold(domain.APSharedDatabase, "min_clients", s.DefaultThresholds())
 
 	isSvc := func(id string) bool {
 		n, ok := g.Nodes[id]
@@ -79,6 +76,7 @@ func (s sharedDB) Detect(g *domain.Graph) ([]domain.Detection, error) {
 				Title:    "Shared database",
 				Summary:  "Multiple services depend on the same database node",
 				Nodes:    nodes,
+				Edges:    collectEdges(g, func(e *domain.Edge) bool { return e.Kind == domain.EdgeCalls && e.To == id && clientsSet[e.From] }),
 				Evidence: domain.Attrs{"db": id, "clients": len(clients), "min_clients": minClients},
 			})
 		}
@@ -0,0 +1,294 @@
+package rules
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+type spof struct{}
+
+func (s spof) Name() string { return "single_point_of_failure" }
+
+// maxSPOFPaths caps the broken paths listed in evidence per detection.
+const maxSPOFPaths = 10
+
+func (s spof) DefaultThresholds() map[string]float64 {
+	return map[string]float64{"min_unreachable": 1}
+}
+
+func isClientNode(n *domain.Node) bool {
+	return n != nil && (n.Kind == domain.NodeClient || n.Kind == domain.NodeUserActor)
+}
+
+func isSPOFCandidate(n *domain.Node) bool {
+	if n == nil {
+		return false
+	}
+	switch n.Kind {
+	case domain.NodeService, domain.NodeAPIGateway, domain.NodeDB:
+		return true
+	}
+	return false
+}
+
+// dependencyEdge reports whether e counts as a runtime dependency; hard means the caller
+// blocks on it (sync CALLS, READS, WRITES), while async CALLS are buffered.
+func dependencyEdge(e *domain.Edge) (ok, hard bool) {
+	if e == nil {
+		return false, false
+	}
+	switch e.Kind {
+	case domain.EdgeReads, domain.EdgeWrites:
+		return true, true
+	case domain.EdgeCalls:
+		return true, edgeIsSync(e)
+	}
+	return false, false
+}
+
+// articulationPoints runs Tarjan's lowpoint DFS on the undirected view of the graph (Out + In)
+// and returns cut vertices plus bridge edge indexes into g.Edges.
+func articulationPoints(g *domain.Graph, ids []string) (map[string]bool, map[int]bool) {
+	edgeIdx := make(map[*domain.Edge]int, len(g.Edges))
+	for i, e := range g.Edges {
+		edgeIdx[e] = i
+	}
+
+	disc := map[string]int{}
+	low := map[string]int{}
+	cut := map[string]bool{}
+	bridges := map[int]bool{}
+	t := 0
+
+	var dfs func(v string, parentEdge int)
+	dfs = func(v string, parentEdge int) {
+		t++
+		disc[v], low[v] = t, t
+		children := 0
+
+		visit := func(e *domain.Edge, w string) {
+			if ok, _ := dependencyEdge(e); !ok {
+				return
+			}
+			if _, exists := g.Nodes[w]; !exists || w == v {
+				return
+			}
+			ei := edgeIdx[e]
+			if ei == parentEdge {
+				return
+			}
+			if _, seen := disc[w]; !seen {
+				children++
+				dfs(w, ei)
+				if low[w] < low[v] {
+					low[v] = low[w]
+				}
+				if parentEdge >= 0 && low[w] >= disc[v] {
+					cut[v] = true
+				}
+				if low[w] > disc[v] {
+					bridges[ei] = true
+				}
+			} else if disc[w] < low[v] {
+				low[v] = disc[w]
+			}
+		}
+
+		for _, e := range g.Out[v] {
+			visit(e, e.To)
+		}
+		for _, e := range g.In[v] {
+			visit(e, e.From)
+		}
+		if parentEdge < 0 && children > 1 {
+			cut[v] = true
+		}
+	}
+
+	for _, id := range ids {
+		if _, seen := disc[id]; !seen {
+			dfs(id, -1)
+		}
+	}
+	return cut, bridges
+}
+
+// reachFrom returns BFS parents (node -> edge used to reach it) from src along Out edges,
+// never entering skip. hardOnly restricts the walk to sync CALLS / READS / WRITES.
+func reachFrom(g *domain.Graph, src, skip string, hardOnly bool) map[string]*domain.Edge {
+	parent := map[string]*domain.Edge{src: nil}
+	queue := []string{src}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for _, e := range g.Out[v] {
+			ok, hard := dependencyEdge(e)
+			if !ok || (hardOnly && !hard) {
+				continue
+			}
+			w := e.To
+			if w == skip {
+				continue
+			}
+			if _, exists := g.Nodes[w]; !exists {
+				continue
+			}
+			if _, seen := parent[w]; seen {
+				continue
+			}
+			parent[w] = e
+			queue = append(queue, w)
+		}
+	}
+	return parent
+}
+
+func pathTo(parent map[string]*domain.Edge, dst string) []string {
+	var rev []string
+	for cur := dst; ; {
+		rev = append(rev, cur)
+		e := parent[cur]
+		if e == nil {
+			break
+		}
+		cur = e.From
+	}
+	for i, j := 0, len(rev)-1; i < j; i, j = i+1, j-1 {
+		rev[i], rev[j] = rev[j], rev[i]
+	}
+	return rev
+}
+
+// Detect flags services, gateways and datastores whose failure cuts clients off from parts of the graph.
+// Candidates are articulation points of the undirected dependency graph; each is confirmed by directed
+// reachability from every client with the candidate removed. Broken paths that only exist through
+// async calls are buffered by a broker, so a SPOF that only breaks async flows is reported as MEDIUM.
+func (s spof) Detect(g *domain.Graph, cfg *detection.DetectorConfig) ([]domain.Detection, error) {
+	minUnreachable := cfg.IntThreshold(domain.APSinglePointOfFailure, "min_unreachable", s.DefaultThresholds())
+
+	var ids, clients []string
+	for id, n := range g.Nodes {
+		ids = append(ids, id)
+		if isClientNode(n) {
+			clients = append(clients, id)
+		}
+	}
+	if len(clients) == 0 {
+		return nil, nil
+	}
+	sort.Strings(ids)
+	sort.Strings(clients)
+
+	cut, bridges := articulationPoints(g, ids)
+
+	full := map[string]map[string]*domain.Edge{}
+	hard := map[string]map[string]*domain.Edge{}
+	for _, c := range clients {
+		full[c] = reachFrom(g, c, "", false)
+		hard[c] = reachFrom(g, c, "", true)
+	}
+
+	var out []domain.Detection
+	for _, v := range ids {
+		if !cut[v] || !isSPOFCandidate(g.Nodes[v]) {
+			continue
+		}
+
+		unreachable := map[string]bool{}
+		var paths []domain.Attrs
+		pathCount := 0
+		syncBroken := false
+		var affected []string
+
+		for _, c := range clients {
+			if _, ok := full[c][v]; !ok {
+				continue
+			}
+			without := reachFrom(g, c, v, false)
+			var lost []string
+			for w := range full[c] {
+				if w == v {
+					continue
+				}
+				if _, still := without[w]; !still {
+					lost = append(lost, w)
+				}
+			}
+			if len(lost) == 0 {
+				continue
+			}
+			sort.Strings(lost)
+			affected = append(affected, c)
+			for _, w := range lost {
+				unreachable[w] = true
+				pathCount++
+				_, viaSync := hard[c][w]
+				if viaSync {
+					syncBroken = true
+				}
+				if len(paths) >= maxSPOFPaths {
+					continue
+				}
+				p := full[c]
+				if viaSync {
+					p = hard[c]
+				}
+				paths = append(paths, domain.Attrs{
+					"client": c,
+					"target": w,
+					"path":   strings.Join(pathTo(p, w), " -> "),
+					"sync":   viaSync,
+				})
+			}
+		}
+		if len(unreachable) < minUnreachable || len(affected) == 0 {
+			continue
+		}
+
+		var lostIDs []string
+		for w := range unreachable {
+			lostIDs = append(lostIDs, w)
+		}
+		sort.Strings(lostIDs)
+
+		var edges []int
+		var bridgeRefs []string
+		for i, e := range g.Edges {
+			if bridges[i] && (e.From == v || e.To == v) {
+				edges = append(edges, i)
+				bridgeRefs = append(bridgeRefs, e.From+" -> "+e.To)
+			}
+		}
+
+		sev := domain.SeverityMedium
+		summary := "Only async flows depend on this node; its failure delays but does not block clients"
+		if syncBroken {
+			sev = domain.SeverityHigh
+			summary = "Clients reach part of the system only through this node; its failure disconnects them"
+		}
+
+		out = append(out, domain.Detection{
+			Kind:     domain.APSinglePointOfFailure,
+			Severity: sev,
+			Title:    "Single point of failure",
+			Summary:  summary,
+			Nodes:    append([]string{v}, lostIDs...),
+			Edges:    edges,
+			Evidence: domain.Attrs{
+				"node":             v,
+				"affected_clients": affected,
+				"unreachable":      lostIDs,
+				"broken_paths":     paths,
+				"broken_count":     pathCount,
+				"sync_broken":      syncBroken,
+				"bridges":          bridgeRefs,
+			},
+		})
+	}
+	return out, nil
+}
+
+func init() { detection.Register(spof{}) }
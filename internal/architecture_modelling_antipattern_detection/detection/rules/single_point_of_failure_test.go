@@ -0,0 +1,80 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+func spofGraph(asyncToNotify, withDB bool) *domain.Graph {
+	g := domain.NewGraph()
+	g.AddNode(&domain.Node{ID: "CLIENT:web", Name: "web", Kind: domain.NodeClient})
+	g.AddNode(&domain.Node{ID: "API_GATEWAY:gw", Name: "gw", Kind: domain.NodeAPIGateway})
+	for _, n := range []string{"orders", "billing", "notify"} {
+		g.AddNode(&domain.Node{ID: "SERVICE:" + n, Name: n, Kind: domain.NodeService})
+	}
+
+	call := func(from, to string, sync bool) {
+		g.AddEdge(&domain.Edge{From: from, To: to, Kind: domain.EdgeCalls, Attrs: domain.Attrs{"sync": sync}})
+	}
+	call("CLIENT:web", "API_GATEWAY:gw", true)
+	call("API_GATEWAY:gw", "SERVICE:orders", true)
+	call("API_GATEWAY:gw", "SERVICE:billing", true)
+	call("SERVICE:orders", "SERVICE:notify", !asyncToNotify)
+	if withDB {
+		g.AddNode(&domain.Node{ID: "DATABASE:orders-db", Name: "orders-db", Kind: domain.NodeDB})
+		g.AddEdge(&domain.Edge{From: "SERVICE:orders", To: "DATABASE:orders-db", Kind: domain.EdgeWrites})
+	}
+	return g
+}
+
+func findSPOF(dets []domain.Detection, id string) *domain.Detection {
+	for i := range dets {
+		if dets[i].Kind == domain.APSinglePointOfFailure && dets[i].Nodes[0] == id {
+			return &dets[i]
+		}
+	}
+	return nil
+}
+
+func TestSPOF_GatewayAndServiceCutClientsOff(t *testing.T) {
+	dets, err := spof{}.Detect(spofGraph(false, true), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gw := findSPOF(dets, "API_GATEWAY:gw")
+	if gw == nil {
+		t.Fatalf("expected gateway SPOF, got %#v", dets)
+	}
+	if gw.Severity != domain.SeverityHigh {
+		t.Fatalf("gateway breaks sync paths, want HIGH, got %s", gw.Severity)
+	}
+	if len(gw.Edges) == 0 {
+		t.Fatalf("expected bridge edges on gateway detection")
+	}
+	if paths, _ := gw.Evidence["broken_paths"].([]domain.Attrs); len(paths) != 4 {
+		t.Fatalf("expected 4 broken client paths through gw, got %#v", gw.Evidence["broken_paths"])
+	}
+	if findSPOF(dets, "SERVICE:orders") == nil {
+		t.Fatalf("orders isolates notify and orders-db, expected SPOF")
+	}
+	if findSPOF(dets, "SERVICE:billing") != nil {
+		t.Fatalf("billing is a leaf and must not be reported")
+	}
+}
+
+func TestSPOF_AsyncOnlyBreakIsMedium(t *testing.T) {
+	// Without orders-db, notify (async) is the only thing behind orders.
+	g := spofGraph(true, false)
+	dets, err := spof{}.Detect(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := findSPOF(dets, "SERVICE:orders")
+	if d == nil {
+		t.Fatalf("expected orders SPOF, got %#v", dets)
+	}
+	if d.Severity != domain.SeverityMedium {
+		t.Fatalf("only async flows break behind orders, want MEDIUM, got %s", d.Severity)
+	}
+}
@@ -1,84 +1,221 @@
 package rules
 
 import (
-	"os"
-	"strconv"
+	"sort"
 
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection"
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
 )
 
+// maxExactSCC is the largest group of services in a sync cycle that is searched exhaustively for
+// its longest simple path. Larger groups are crossed by a shortest path, so a chain through one
+// is a lower bound (reported with "approximate").
+const maxExactSCC = 8
+
 type syncChain struct{}
 
 func (s syncChain) Name() string { return "sync_call_chain" }
 
-func (s syncChain) Detect(g *domain.Graph) ([]domain.Detection, error) {
-	minEdges := 4
-	if v := os.Getenv("DETECT_SYNC_CHAIN_MIN_EDGES"); v != "" {
-		if n, err := strconv.Atoi(v); err == nil && n > 0 {
-			minEdges = n
+func (s syncChain) DefaultThresholds() map[string]float64 {
+	return map[string]float64{"min_edges": 4}
+}
+
+// Detect reports every long sync chain: the longest chain overall, then the longest chain from
+// each service not already on a reported chain. Sync cycles are condensed first and the longest
+// path is computed over the resulting DAG, so this is linear in the graph outside cycles.
+func (s syncChain) Detect(g *domain.Graph, cfg *detection.DetectorConfig) ([]domain.Detection, error) {
+	minEdges := cfg.IntThreshold(domain.APSyncCallChain, "min_edges", s.DefaultThresholds())
+
+	ids := serviceIDs(g)
+	steps := longestSyncChains(ids, serviceCalls(g, edgeIsSync))
+	sort.SliceStable(ids, func(i, j int) bool { return steps[ids[i]].hops > steps[ids[j]].hops })
+
+	hops := syncHops(g)
+	var out []domain.Detection
+	covered := map[string]bool{}
+	for _, id := range ids {
+		if steps[id].hops < minEdges {
+			break
+		}
+		if covered[id] {
+			continue
+		}
+		chain, exact := chainFrom(steps, id)
+		for _, v := range chain {
+			covered[v] = true
+		}
+		ev := domain.Attrs{"edges": len(chain) - 1, "min_edges": minEdges}
+		if !exact {
+			ev["approximate"] = true
 		}
+		out = append(out, domain.Detection{
+			Kind:     domain.APSyncCallChain,
+			Severity: domain.SeverityMedium,
+			Title:    "Sync call chain",
+			Summary:  "Long synchronous dependency chain can amplify latency/failure impact",
+			Nodes:    chain,
+			Edges:    chainEdges(hops, chain),
+			Evidence: ev,
+		})
 	}
+	return out, nil
+}
+
+// chainStep is the longest sync chain starting at a service: hops edges in total, first along
+// path inside the service's strongly connected component, then on from exit (a service in a
+// component the chain has not visited; "" when the chain ends inside).
+type chainStep struct {
+	hops  int
+	path  []string
+	exit  string
+	exact bool
+}
 
-	isSvc := func(id string) bool {
-		n, ok := g.Nodes[id]
-		return ok && n != nil && n.Kind == domain.NodeService
+// longestSyncChains computes the chainStep of every service. Components come from scc in reverse
+// topological order, so the steps of every exit are known when a component is handled.
+func longestSyncChains(ids []string, adj map[string][]string) map[string]chainStep {
+	comps := domain.StronglyConnected(ids, adj)
+	compOf := make(map[string]int, len(ids))
+	for i, c := range comps {
+		for _, v := range c {
+			compOf[v] = i
+		}
+	}
+	steps := make(map[string]chainStep, len(ids))
+	// leave is the best way out of comp from w: the exit and the hops from w on (0 and "" when
+	// the chain should end at w).
+	leave := func(w string, comp int) (int, string) {
+		hops, exit := 0, ""
+		for _, x := range adj[w] {
+			if compOf[x] != comp {
+				if h := 1 + steps[x].hops; h > hops {
+					hops, exit = h, x
+				}
+			}
+		}
+		return hops, exit
 	}
+	for i, comp := range comps {
+		sort.Strings(comp)
+		if len(comp) > maxExactSCC {
+			approxComponentChains(comp, i, adj, compOf, leave, steps)
+			continue
+		}
+		for _, v := range comp {
+			paths := longestPathsWithin(v, adj, compOf)
+			ends := make([]string, 0, len(paths))
+			for w := range paths {
+				ends = append(ends, w)
+			}
+			sort.Strings(ends)
 
-	nextSync := func(from string) []string {
-		var out []string
-		for _, e := range g.Out[from] {
-			if e == nil || e.Kind != domain.EdgeCalls {
-				continue
+			best := chainStep{hops: -1}
+			for _, w := range ends {
+				p := paths[w]
+				h, exit := leave(w, i)
+				if h += len(p) - 1; h > best.hops {
+					best = chainStep{hops: h, path: p, exit: exit, exact: exit == "" || steps[exit].exact}
+				}
 			}
-			if !edgeIsSync(e) {
-				continue
+			steps[v] = best
+		}
+	}
+	return steps
+}
+
+// approxComponentChains handles a component too large for an exhaustive search in linear time:
+// every member takes its shortest path inside the component to the member with the best way out.
+func approxComponentChains(comp []string, i int, adj map[string][]string, compOf map[string]int,
+	leave func(string, int) (int, string), steps map[string]chainStep) {
+	target, targetHops, targetExit := "", -1, ""
+	rev := map[string][]string{}
+	for _, w := range comp {
+		if h, exit := leave(w, i); h > targetHops {
+			target, targetHops, targetExit = w, h, exit
+		}
+		for _, x := range adj[w] {
+			if compOf[x] == i {
+				rev[x] = append(rev[x], w)
 			}
-			if isSvc(e.To) {
-				out = append(out, e.To)
+		}
+	}
+	// Breadth-first search backwards from target: next is the following hop towards it.
+	next := map[string]string{target: ""}
+	queue := []string{target}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, u := range rev[cur] {
+			if _, seen := next[u]; !seen {
+				next[u] = cur
+				queue = append(queue, u)
 			}
 		}
-		return out
 	}
+	for _, v := range comp {
+		var path []string
+		for cur := v; cur != ""; cur = next[cur] {
+			path = append(path, cur)
+		}
+		steps[v] = chainStep{hops: len(path) - 1 + targetHops, path: path, exit: targetExit}
+	}
+}
 
-	best := []string{}
+// chainFrom expands the chain starting at id; exact is false when it crosses a component too
+// large for an exhaustive search.
+func chainFrom(steps map[string]chainStep, id string) (chain []string, exact bool) {
+	exact = steps[id].exact
+	for cur := id; cur != ""; cur = steps[cur].exit {
+		chain = append(chain, steps[cur].path...)
+	}
+	return chain, exact
+}
 
-	var dfs func(cur string, visited map[string]bool, path []string)
-	dfs = func(cur string, visited map[string]bool, path []string) {
-		if len(path) > len(best) {
-			best = append([]string{}, path...)
-		}
-		for _, nxt := range nextSync(cur) {
-			if visited[nxt] {
+// longestPathsWithin returns, for every service reachable from v without leaving v's component,
+// the longest simple path to it (exponential in the component size; see maxExactSCC).
+func longestPathsWithin(v string, adj map[string][]string, compOf map[string]int) map[string][]string {
+	out := map[string][]string{v: {v}}
+	visited := map[string]bool{v: true}
+	var dfs func(path []string)
+	dfs = func(path []string) {
+		for _, w := range adj[path[len(path)-1]] {
+			if compOf[w] != compOf[v] || visited[w] {
 				continue
 			}
-			visited[nxt] = true
-			dfs(nxt, visited, append(path, nxt))
-			delete(visited, nxt)
+			next := append(append(make([]string, 0, len(path)+1), path...), w)
+			if len(next) > len(out[w]) {
+				out[w] = next
+			}
+			visited[w] = true
+			dfs(next)
+			delete(visited, w)
 		}
 	}
+	dfs([]string{v})
+	return out
+}
 
-	for id, n := range g.Nodes {
-		if n == nil || n.Kind != domain.NodeService {
-			continue
+// syncHops indexes the sync CALLS edges by (from, to), so each chain collects its edges without
+// scanning the whole graph.
+func syncHops(g *domain.Graph) map[[2]string][]int {
+	hops := map[[2]string][]int{}
+	for i, e := range g.Edges {
+		if e != nil && e.Kind == domain.EdgeCalls && edgeIsSync(e) {
+			k := [2]string{e.From, e.To}
+			hops[k] = append(hops[k], i)
 		}
-		visited := map[string]bool{id: true}
-		dfs(id, visited, []string{id})
 	}
+	return hops
+}
 
-	edges := len(best) - 1
-	if edges >= minEdges {
-		return []domain.Detection{{
-			Kind:     domain.APSyncCallChain,
-			Severity: domain.SeverityMedium,
-			Title:    "Sync call chain",
-			Summary:  "Long synchronous dependency chain can amplify latency/failure impact",
-			Nodes:    best,
-			Evidence: domain.Attrs{"edges": edges, "min_edges": minEdges},
-		}}, nil
+// chainEdges returns (ascending) the sync CALLS edges linking consecutive nodes of path.
+func chainEdges(hops map[[2]string][]int, path []string) []int {
+	out := []int{}
+	for i := 0; i+1 < len(path); i++ {
+		out = append(out, hops[[2]string{path[i], path[i+1]}]...)
 	}
-
-	return nil, nil
+	sort.Ints(out)
+	return out
 }
 
 func init() { detection.Register(syncChain{}) }
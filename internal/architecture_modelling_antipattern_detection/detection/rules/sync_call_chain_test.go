@@ -0,0 +1,98 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+// chainGraph adds services and sync calls given as "a>b>c" paths.
+func chainGraph(paths ...string) *domain.Graph {
+	g := domain.NewGraph()
+	for _, p := range paths {
+		hops := strings.Split(p, ">")
+		for i, n := range hops {
+			if g.Nodes["SERVICE:"+n] == nil {
+				g.AddNode(&domain.Node{ID: "SERVICE:" + n, Name: n, Kind: domain.NodeService})
+			}
+			if i > 0 {
+				g.AddEdge(&domain.Edge{From: "SERVICE:" + hops[i-1], To: "SERVICE:" + n, Kind: domain.EdgeCalls, Attrs: domain.Attrs{"sync": true}})
+			}
+		}
+	}
+	return g
+}
+
+func chainNames(d domain.Detection) string {
+	names := make([]string, len(d.Nodes))
+	for i, id := range d.Nodes {
+		names[i] = strings.TrimPrefix(id, "SERVICE:")
+	}
+	return strings.Join(names, ">")
+}
+
+func TestSyncChain_ReportsEveryLongChain(t *testing.T) {
+	g := chainGraph(
+		"gw>a>b>c>d>e",  // longest: 5 edges
+		"gw>x>c",        // joins the first chain: x>c>d>e is only 3 edges
+		"p>q>r>s>t",     // separate: 4 edges
+		"short1>short2", // below threshold
+	)
+	dets, err := syncChain{}.Detect(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, d := range dets {
+		got = append(got, chainNames(d))
+	}
+	if len(got) != 2 || got[0] != "gw>a>b>c>d>e" || got[1] != "p>q>r>s>t" {
+		t.Fatalf("chains = %v", got)
+	}
+	if dets[0].Evidence["edges"] != 5 || len(dets[0].Edges) != 5 {
+		t.Fatalf("evidence %v, edges %v", dets[0].Evidence, dets[0].Edges)
+	}
+}
+
+func TestSyncChain_BranchesFromCoveredEntry(t *testing.T) {
+	// Two branches from gw each over the threshold: the second is reported from its own start.
+	g := chainGraph("gw>a>b>c>d>e", "gw>m>n>o>p>q")
+	dets, err := syncChain{}.Detect(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dets) != 2 || chainNames(dets[0]) != "gw>a>b>c>d>e" || chainNames(dets[1]) != "m>n>o>p>q" {
+		t.Fatalf("chains = %v", dets)
+	}
+}
+
+func TestSyncChain_ThroughCycle(t *testing.T) {
+	// a<->b is condensed, but the chain still walks both: a>b>c>d>e.
+	g := chainGraph("b>a>b>c>d>e")
+	dets, err := syncChain{}.Detect(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dets) != 1 || chainNames(dets[0]) != "a>b>c>d>e" {
+		t.Fatalf("chains = %v", dets)
+	}
+	if _, ok := dets[0].Evidence["approximate"]; ok {
+		t.Fatal("small cycles are searched exactly")
+	}
+}
+
+func TestSyncChain_LargeCycleIsApproximate(t *testing.T) {
+	ring := "r0>r1>r2>r3>r4>r5>r6>r7>r8>r9>r0"
+	g := chainGraph(ring, "r9>out")
+	dets, err := syncChain{}.Detect(g, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dets) == 0 || dets[0].Evidence["approximate"] != true {
+		t.Fatalf("expected an approximate chain through the ring, got %v", dets)
+	}
+	if chainNames(dets[0]) != "r0>r1>r2>r3>r4>r5>r6>r7>r8>r9>out" {
+		t.Fatalf("chain = %s", chainNames(dets[0]))
+	}
+}
@@ -1,9 +1,6 @@
 package rules
 
 import (
-	"os"
-	"strconv"
-
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection"
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
 )
@@ -39,23 +36,6 @@ func edgeIsSync(e *domain.Edge) bool {
 	return true
 }
 
-func countSync(gr *domain.Graph, from, to string) (cnt, totalOut int) {
-	for _, e := range gr.Out[from] {
-		if e == nil || e.Kind != domain.EdgeCalls {
-			continue
-		}
-		if !edgeIsSync(e) {
-			continue
-		}
-		w := edgeWeight(e)
-		if e.To == to {
-			cnt += w
-		}
-		totalOut += w
-	}
-	return
-}
-
 func max(a, b int) int {
 	if a > b {
 		return a
@@ -63,73 +43,74 @@ func max(a, b int) int {
 	return b
 }
 
-func (t tight) Detect(g *domain.Graph) ([]domain.Detection, error) {
-	minBidir := 1
-	ratio := 0.7
-
-	if v := os.Getenv("DETECT_TIGHT_COUPLING_MIN_BIDIR"); v != "" {
-		if n, err := strconv.Atoi(v); err == nil && n > 0 {
-			minBidir = n
-		}
-	}
-	if v := os.Getenv("DETECT_TIGHT_COUPLING_RATIO"); v != "" {
-		if f, err := strconv.ParseFloat(v, 64); err == nil && f > 0 {
-			ratio = f
-		}
+func (t tight) DefaultThresholds() map[string]float64 {
+	return map[string]float64{
+		"min_bidir": 1,
+		"ratio":     0.7,
 	}
+}
 
-	var out []domain.Detection
-	seen := map[string]bool{}
+func (t tight) Detect(g *domain.Graph, cfg *detection.DetectorConfig) ([]domain.Detection, error) {
+	defaults := t.DefaultThresholds()
+	minBidir := cfg.IntThreshold(domain.APTightCoupling, "min_bidir", defaults)
+	ratio := cfg.Threshold(domain.APTightCoupling, "ratio", defaults)
 
 	isSvc := func(id string) bool {
 		n, ok := g.Nodes[id]
 		return ok && n != nil && n.Kind == domain.NodeService
 	}
+	unordered := func(a, b string) [2]string {
+		if b < a {
+			a, b = b, a
+		}
+		return [2]string{a, b}
+	}
 
-	for _, a := range g.Nodes {
-		if a == nil || a.Kind != domain.NodeService {
+	// One pass: sync call weight per direction and per caller, and the edges of each pair.
+	weight := map[[2]string]int{}
+	totalOut := map[string]int{}
+	pairEdges := map[[2]string][]int{}
+	var pairs [][2]string
+	for i, e := range g.Edges {
+		if e == nil || e.Kind != domain.EdgeCalls || !edgeIsSync(e) {
 			continue
 		}
-		for _, e := range g.Out[a.ID] {
-			if e == nil || e.Kind != domain.EdgeCalls || !edgeIsSync(e) {
-				continue
-			}
-			if !isSvc(e.To) {
-				continue
-			}
-
-			fwd := a.ID + "|" + e.To
-			rev := e.To + "|" + a.ID
-			if seen[fwd] || seen[rev] {
-				continue
-			}
-
-			ab, aOut := countSync(g, a.ID, e.To)
-			ba, bOut := countSync(g, e.To, a.ID)
-
-			if ab >= minBidir && ba >= minBidir {
-				ra := float64(ab) / float64(max(1, aOut))
-				rb := float64(ba) / float64(max(1, bOut))
-				if ra >= ratio && rb >= ratio {
-					out = append(out, domain.Detection{
-						Kind:     domain.APTightCoupling,
-						Severity: domain.SeverityHigh,
-						Title:    "Tight coupling (synchronous mutual dependency)",
-						Summary:  "Services rely heavily on each other via synchronous calls",
-						Nodes:    []string{a.ID, e.To},
-						Evidence: domain.Attrs{
-							"ab": ab, "ba": ba,
-							"ra": ra, "rb": rb,
-							"min_bidir": minBidir,
-							"ratio":     ratio,
-						},
-					})
-				}
-			}
+		w := edgeWeight(e)
+		weight[[2]string{e.From, e.To}] += w
+		totalOut[e.From] += w
+		k := unordered(e.From, e.To)
+		if pairEdges[k] == nil && isSvc(e.From) && isSvc(e.To) {
+			pairs = append(pairs, [2]string{e.From, e.To})
+		}
+		pairEdges[k] = append(pairEdges[k], i)
+	}
 
-			seen[fwd] = true
-			seen[rev] = true
+	var out []domain.Detection
+	for _, p := range pairs {
+		a, b := p[0], p[1]
+		ab, ba := weight[[2]string{a, b}], weight[[2]string{b, a}]
+		if ab < minBidir || ba < minBidir {
+			continue
+		}
+		ra := float64(ab) / float64(max(1, totalOut[a]))
+		rb := float64(ba) / float64(max(1, totalOut[b]))
+		if ra < ratio || rb < ratio {
+			continue
 		}
+		out = append(out, domain.Detection{
+			Kind:     domain.APTightCoupling,
+			Severity: domain.SeverityHigh,
+			Title:    "Tight coupling (synchronous mutual dependency)",
+			Summary:  "Services rely heavily on each other via synchronous calls",
+			Nodes:    []string{a, b},
+			Edges:    pairEdges[unordered(a, b)],
+			Evidence: domain.Attrs{
+				"ab": ab, "ba": ba,
+				"ra": ra, "rb": rb,
+				"min_bidir": minBidir,
+				"ratio":     ratio,
+			},
+		})
 	}
 
 	return out, nil
@@ -1,9 +1,6 @@
 package rules
 
 import (
-	"os"
-	"strconv"
-
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection"
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
 )
@@ -12,13 +9,12 @@ type uiOrchestrator struct{}
 
 func (u uiOrchestrator) Name() string { return "ui_orchestrator" }
 
-func (u uiOrchestrator) Detect(g *domain.Graph) ([]domain.Detection, error) {
-	minOut := 2
-	if v := os.Getenv("DETECT_UI_ORCH_MIN_OUT"); v != "" {
-		if n, err := strconv.Atoi(v); err == nil && n > 0 {
-			minOut = n
-		}
-	}
+func (u uiOrchestrator) DefaultThresholds() map[string]float64 {
+	return map[string]float64{"min_out": 2}
+}
+
+func (u uiOrchestrator) Detect(g *domain.Graph, cfg *detection.DetectorConfig) ([]domain.Detection, error) {
+	minOut := cfg.IntThreshold(domain.APUIOrchestrator, "min_out", u.DefaultThresholds())
 
 	isSvc := func(id string) bool {
 		n, ok := g.Nodes[id]
@@ -69,6 +65,7 @@ func (u uiOrchestrator) Detect(g *domain.Graph) ([]domain.Detection, error) {
 				Title:    "UI orchestrator",
 				Summary:  "UI directly orchestrates multiple backend services",
 				Nodes:    nodes,
+				Edges:    edgesBetween(g, id, targets...),
 				Evidence: domain.Attrs{
 					"ui":           id,
 					"targets":      len(targetsSet),
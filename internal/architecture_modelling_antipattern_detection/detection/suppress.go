@@ -0,0 +1,85 @@
+package detection
+
+import (
+	"strings"
+	"time"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+// SuppressedKey is the Detection.Evidence key ApplySuppressions writes the matching suppression under.
+const SuppressedKey = "suppressed"
+
+// Suppression silences detections of Kind that involve all of Nodes (any detection of Kind when Nodes
+// is empty). Nodes may be graph IDs ("SERVICE:orders") or plain names ("orders").
+type Suppression struct {
+	Kind    domain.AntiPatternKind `json:"kind"`
+	Nodes   []string               `json:"nodes,omitempty"`
+	Reason  string                 `json:"reason,omitempty"`
+	Expires *time.Time             `json:"expires,omitempty"`
+}
+
+// Active reports whether s still applies at now.
+func (s Suppression) Active(now time.Time) bool {
+	return s.Expires == nil || now.Before(*s.Expires)
+}
+
+// Matches reports whether s covers d (ignoring expiry).
+func (s Suppression) Matches(d domain.Detection) bool {
+	if s.Kind != d.Kind {
+		return false
+	}
+	have := make(map[string]bool, len(d.Nodes))
+	for _, id := range d.Nodes {
+		have[nodeRef(id)] = true
+	}
+	for _, n := range s.Nodes {
+		if !have[nodeRef(n)] {
+			return false
+		}
+	}
+	return true
+}
+
+// nodeRef drops the "KIND:" prefix of a node ID and lowercases the rest.
+func nodeRef(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, ":"); i >= 0 {
+		s = s[i+1:]
+	}
+	return strings.ToLower(s)
+}
+
+// ApplySuppressions splits dets into kept and suppressed. Suppressed detections get the matching
+// suppression under Evidence[SuppressedKey]; expired suppressions are ignored. dets is not modified.
+func ApplySuppressions(dets []domain.Detection, sups []Suppression, now time.Time) (kept, suppressed []domain.Detection) {
+	kept = make([]domain.Detection, 0, len(dets))
+	for _, d := range dets {
+		var hit *Suppression
+		for i := range sups {
+			if sups[i].Active(now) && sups[i].Matches(d) {
+				hit = &sups[i]
+				break
+			}
+		}
+		if hit == nil {
+			kept = append(kept, d)
+			continue
+		}
+		ev := make(domain.Attrs, len(d.Evidence)+1)
+		for k, v := range d.Evidence {
+			ev[k] = v
+		}
+		note := domain.Attrs{}
+		if hit.Reason != "" {
+			note["reason"] = hit.Reason
+		}
+		if hit.Expires != nil {
+			note["expires"] = *hit.Expires
+		}
+		ev[SuppressedKey] = note
+		d.Evidence = ev
+		suppressed = append(suppressed, d)
+	}
+	return kept, suppressed
+}
@@ -4,5 +4,5 @@ import "github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelli
 
 type Detector interface {
 	Name() string
-	Detect(g *domain.Graph) ([]domain.Detection, error)
+	Detect(g *domain.Graph, cfg *DetectorConfig) ([]domain.Detection, error)
 }
@@ -23,14 +23,19 @@ const (
 type AntiPatternKind string
 
 const (
-	APCycles             AntiPatternKind = "cycles"
-	APGodService         AntiPatternKind = "god_service"
-	APTightCoupling      AntiPatternKind = "tight_coupling"
-	APSharedDatabase     AntiPatternKind = "shared_database"
-	APSyncCallChain      AntiPatternKind = "sync_call_chain"
-	APPingPongDependency AntiPatternKind = "ping_pong_dependency"
-	APReverseDependency  AntiPatternKind = "reverse_dependency"
-	APUIOrchestrator     AntiPatternKind = "ui_orchestrator"
+	APCycles               AntiPatternKind = "cycles"
+	APGodService           AntiPatternKind = "god_service"
+	APTightCoupling        AntiPatternKind = "tight_coupling"
+	APSharedDatabase       AntiPatternKind = "shared_database"
+	APSyncCallChain        AntiPatternKind = "sync_call_chain"
+	APPingPongDependency   AntiPatternKind = "ping_pong_dependency"
+	APReverseDependency    AntiPatternKind = "reverse_dependency"
+	APUIOrchestrator       AntiPatternKind = "ui_orchestrator"
+	APChattyService        AntiPatternKind = "chatty_service"
+	APSinglePointOfFailure AntiPatternKind = "single_point_of_failure"
+	APOrphanEventTopic     AntiPatternKind = "orphan_event_topic"
+	APSelfConsumingService AntiPatternKind = "self_consuming_service"
+	APEventLoop            AntiPatternKind = "event_loop"
 )
 
 type Severity string
@@ -0,0 +1,50 @@
+package domain
+
+// StronglyConnected returns the Tarjan strongly connected components of the graph over ids with
+// successors adj. Components are emitted in reverse topological order: every component reachable
+// from one comes before it.
+func StronglyConnected(ids []string, adj map[string][]string) [][]string {
+	index := 0
+	idx := map[string]int{}
+	low := map[string]int{}
+	onStack := map[string]bool{}
+	var stack []string
+	var comps [][]string
+
+	var dfs func(v string)
+	dfs = func(v string) {
+		index++
+		idx[v], low[v] = index, index
+		stack = append(stack, v)
+		onStack[v] = true
+		for _, w := range adj[v] {
+			if _, seen := idx[w]; !seen {
+				dfs(w)
+				if low[w] < low[v] {
+					low[v] = low[w]
+				}
+			} else if onStack[w] && idx[w] < low[v] {
+				low[v] = idx[w]
+			}
+		}
+		if low[v] == idx[v] {
+			var comp []string
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				comp = append(comp, w)
+				if w == v {
+					break
+				}
+			}
+			comps = append(comps, comp)
+		}
+	}
+	for _, v := range ids {
+		if _, seen := idx[v]; !seen {
+			dfs(v)
+		}
+	}
+	return comps
+}
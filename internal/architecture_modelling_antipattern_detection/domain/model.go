@@ -1,5 +1,7 @@
 package domain
 
+import "encoding/json"
+
 type Attrs map[string]any
 
 type Node struct {
@@ -64,6 +66,23 @@ func (g *Graph) RebuildOutIn() {
 	}
 }
 
+// Clone returns a deep copy of g with Out and In rebuilt; strategies can mutate the copy freely.
+func (g *Graph) Clone() *Graph {
+	if g == nil {
+		return NewGraph()
+	}
+	b, err := json.Marshal(g)
+	if err != nil {
+		return NewGraph()
+	}
+	var out Graph
+	if err := json.Unmarshal(b, &out); err != nil {
+		return NewGraph()
+	}
+	out.RebuildOutIn()
+	return &out
+}
+
 type Detection struct {
 	Kind     AntiPatternKind `json:"kind"`
 	Severity Severity        `json:"severity"`
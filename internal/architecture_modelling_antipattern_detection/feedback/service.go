@@ -1 +1,219 @@
-package feedback
\ No newline at end of file
+package feedback
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/suggestion"
+)
+
+type Status string
+
+const (
+	StatusFalsePositive Status = "false_positive"
+	StatusAcceptedRisk  Status = "accepted_risk"
+	StatusWillFix       Status = "will_fix"
+)
+
+// EvidenceKey is the Detection.Evidence key Annotate writes feedback under.
+const EvidenceKey = "feedback"
+
+// Feedback is a user's verdict on one detection, keyed by suggestion.DetectionKey within a project.
+type Feedback struct {
+	UserID          string                 `json:"-"`
+	ProjectPublicID string                 `json:"project_public_id"`
+	DetectionKey    string                 `json:"detection_key"`
+	Kind            domain.AntiPatternKind `json:"kind"`
+	Status          Status                 `json:"status"`
+	Reason          string                 `json:"reason,omitempty"`
+	ExpiresAt       *time.Time             `json:"expires_at,omitempty"`
+	// VersionID is the version the feedback was given on (optional).
+	VersionID string `json:"version_id,omitempty"`
+	// Detection is a snapshot of the detection at feedback time (kept for training export).
+	Detection *domain.Detection `json:"detection,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// Active reports whether f still applies at now (accepted risks lapse at ExpiresAt).
+func (f Feedback) Active(now time.Time) bool {
+	return f.ExpiresAt == nil || now.Before(*f.ExpiresAt)
+}
+
+// Hides reports whether f hides its detection when hiding is requested:
+// false positives always, accepted risks until they expire, will-fix never.
+func (f Feedback) Hides(now time.Time) bool {
+	switch f.Status {
+	case StatusFalsePositive:
+		return true
+	case StatusAcceptedRisk:
+		return f.Active(now)
+	}
+	return false
+}
+
+// Store persists feedback per user + project.
+type Store interface {
+	UpsertFeedback(f *Feedback) (*Feedback, error)
+	ListFeedback(userID, projectPublicID string) ([]Feedback, error)
+	DeleteFeedback(userID, projectPublicID, detectionKey string) (bool, error)
+}
+
+var ErrInvalid = errors.New("invalid feedback")
+
+type Service struct {
+	store Store
+	now   func() time.Time
+}
+
+func NewService(store Store) *Service {
+	return &Service{store: store, now: time.Now}
+}
+
+// Validate normalizes f and checks the status-specific rules.
+func Validate(f *Feedback, now time.Time) error {
+	f.DetectionKey = strings.TrimSpace(f.DetectionKey)
+	f.Reason = strings.TrimSpace(f.Reason)
+	if f.Detection != nil {
+		key := suggestion.DetectionKey(*f.Detection)
+		if f.DetectionKey == "" {
+			f.DetectionKey = key
+		} else if f.DetectionKey != key {
+			return fmt.Errorf("%w: detection_key does not match detection", ErrInvalid)
+		}
+		f.Kind = f.Detection.Kind
+	}
+	if f.DetectionKey == "" {
+		return fmt.Errorf("%w: detection_key or detection is required", ErrInvalid)
+	}
+	if f.Kind == "" {
+		if i := strings.Index(f.DetectionKey, "|"); i > 0 {
+			f.Kind = domain.AntiPatternKind(f.DetectionKey[:i])
+		}
+	}
+	switch f.Status {
+	case StatusFalsePositive, StatusWillFix:
+		if f.ExpiresAt != nil {
+			return fmt.Errorf("%w: expires_at only applies to %s", ErrInvalid, StatusAcceptedRisk)
+		}
+	case StatusAcceptedRisk:
+		if f.Reason == "" {
+			return fmt.Errorf("%w: reason is required for %s", ErrInvalid, StatusAcceptedRisk)
+		}
+		if f.ExpiresAt != nil && !f.ExpiresAt.After(now) {
+			return fmt.Errorf("%w: expires_at must be in the future", ErrInvalid)
+		}
+	default:
+		return fmt.Errorf("%w: status must be %s, %s or %s", ErrInvalid, StatusFalsePositive, StatusAcceptedRisk, StatusWillFix)
+	}
+	return nil
+}
+
+// Record validates and stores feedback (one entry per detection key; later feedback replaces earlier).
+func (s *Service) Record(f Feedback) (*Feedback, error) {
+	if err := Validate(&f, s.now()); err != nil {
+		return nil, err
+	}
+	return s.store.UpsertFeedback(&f)
+}
+
+func (s *Service) List(userID, projectPublicID string) ([]Feedback, error) {
+	return s.store.ListFeedback(userID, projectPublicID)
+}
+
+func (s *Service) Remove(userID, projectPublicID, detectionKey string) (bool, error) {
+	return s.store.DeleteFeedback(userID, projectPublicID, detectionKey)
+}
+
+// Annotate loads the project's feedback and applies it to dets (see Apply).
+func (s *Service) Annotate(userID, projectPublicID string, dets []domain.Detection, hide bool) ([]domain.Detection, int, error) {
+	fbs, err := s.store.ListFeedback(userID, projectPublicID)
+	if err != nil {
+		return dets, 0, err
+	}
+	out, hidden := Apply(dets, fbs, s.now(), hide)
+	return out, hidden, nil
+}
+
+// Apply writes matching feedback into each detection's Evidence[EvidenceKey]. With hide, detections
+// whose feedback Hides them are dropped; the number dropped is returned. Expired accepted risks
+// are annotated as expired and never hidden. dets is not modified.
+func Apply(dets []domain.Detection, fbs []Feedback, now time.Time, hide bool) ([]domain.Detection, int) {
+	byKey := make(map[string]Feedback, len(fbs))
+	for _, f := range fbs {
+		byKey[f.DetectionKey] = f
+	}
+	out := make([]domain.Detection, 0, len(dets))
+	hidden := 0
+	for _, d := range dets {
+		f, ok := byKey[suggestion.DetectionKey(d)]
+		if !ok {
+			out = append(out, d)
+			continue
+		}
+		if hide && f.Hides(now) {
+			hidden++
+			continue
+		}
+		ev := make(domain.Attrs, len(d.Evidence)+1)
+		for k, v := range d.Evidence {
+			ev[k] = v
+		}
+		note := domain.Attrs{"status": f.Status, "updated_at": f.UpdatedAt}
+		if f.Reason != "" {
+			note["reason"] = f.Reason
+		}
+		if f.ExpiresAt != nil {
+			note["expires_at"] = *f.ExpiresAt
+			note["expired"] = !f.Active(now)
+		}
+		ev[EvidenceKey] = note
+		d.Evidence = ev
+		out = append(out, d)
+	}
+	return out, hidden
+}
+
+// TrainingExample is one labelled detection for detector tuning. Label is 0 for false positives
+// and 1 for confirmed problems (accepted risk or will fix).
+type TrainingExample struct {
+	ProjectPublicID string                 `json:"project_public_id"`
+	DetectionKey    string                 `json:"detection_key"`
+	Kind            domain.AntiPatternKind `json:"kind"`
+	Label           int                    `json:"label"`
+	Status          Status                 `json:"status"`
+	Reason          string                 `json:"reason,omitempty"`
+	VersionID       string                 `json:"version_id,omitempty"`
+	Detection       *domain.Detection      `json:"detection,omitempty"`
+	RecordedAt      time.Time              `json:"recorded_at"`
+}
+
+// TrainingData converts the project's feedback into labelled examples.
+func (s *Service) TrainingData(userID, projectPublicID string) ([]TrainingExample, error) {
+	fbs, err := s.store.ListFeedback(userID, projectPublicID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]TrainingExample, 0, len(fbs))
+	for _, f := range fbs {
+		label := 1
+		if f.Status == StatusFalsePositive {
+			label = 0
+		}
+		out = append(out, TrainingExample{
+			ProjectPublicID: f.ProjectPublicID,
+			DetectionKey:    f.DetectionKey,
+			Kind:            f.Kind,
+			Label:           label,
+			Status:          f.Status,
+			Reason:          f.Reason,
+			VersionID:       f.VersionID,
+			Detection:       f.Detection,
+			RecordedAt:      f.UpdatedAt,
+		})
+	}
+	return out, nil
+}
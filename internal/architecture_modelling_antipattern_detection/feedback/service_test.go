@@ -0,0 +1,110 @@
+package feedback
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/suggestion"
+)
+
+type memStore struct{ list []Feedback }
+
+func (m *memStore) UpsertFeedback(f *Feedback) (*Feedback, error) {
+	for i := range m.list {
+		if m.list[i].DetectionKey == f.DetectionKey {
+			m.list[i] = *f
+			return f, nil
+		}
+	}
+	m.list = append(m.list, *f)
+	return f, nil
+}
+
+func (m *memStore) ListFeedback(userID, projectPublicID string) ([]Feedback, error) {
+	return m.list, nil
+}
+
+func (m *memStore) DeleteFeedback(userID, projectPublicID, detectionKey string) (bool, error) {
+	return false, nil
+}
+
+var (
+	godDet   = domain.Detection{Kind: domain.APGodService, Severity: domain.SeverityHigh, Nodes: []string{"SERVICE:b", "SERVICE:a"}}
+	dbDet    = domain.Detection{Kind: domain.APSharedDatabase, Severity: domain.SeverityMedium, Nodes: []string{"DATABASE:db"}}
+	cycleDet = domain.Detection{Kind: domain.APCycles, Severity: domain.SeverityHigh, Nodes: []string{"SERVICE:a", "SERVICE:b"}}
+)
+
+func TestService_RecordValidates(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := &Service{store: &memStore{}, now: func() time.Time { return now }}
+	past := now.Add(-time.Hour)
+
+	bad := []Feedback{
+		{Status: StatusFalsePositive},
+		{DetectionKey: "x|y", Status: "ignored"},
+		{DetectionKey: "x|y", Status: StatusAcceptedRisk},
+		{DetectionKey: "x|y", Status: StatusAcceptedRisk, Reason: "legacy", ExpiresAt: &past},
+		{DetectionKey: "wrong", Detection: &godDet, Status: StatusWillFix},
+	}
+	for i, f := range bad {
+		if _, err := s.Record(f); !errors.Is(err, ErrInvalid) {
+			t.Errorf("case %d: expected ErrInvalid, got %v", i, err)
+		}
+	}
+
+	saved, err := s.Record(Feedback{Detection: &godDet, Status: StatusWillFix})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.DetectionKey != suggestion.DetectionKey(godDet) || saved.Kind != domain.APGodService {
+		t.Fatalf("key/kind not derived from detection: %+v", saved)
+	}
+}
+
+func TestApply_AnnotatesAndHides(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	expired := now.Add(-time.Hour)
+	fbs := []Feedback{
+		{DetectionKey: suggestion.DetectionKey(godDet), Status: StatusFalsePositive},
+		{DetectionKey: suggestion.DetectionKey(dbDet), Status: StatusAcceptedRisk, Reason: "read replica", ExpiresAt: &expired},
+		{DetectionKey: suggestion.DetectionKey(cycleDet), Status: StatusWillFix},
+	}
+	dets := []domain.Detection{godDet, dbDet, cycleDet}
+
+	all, hidden := Apply(dets, fbs, now, false)
+	if hidden != 0 || len(all) != 3 {
+		t.Fatalf("annotate-only should keep all detections, got %d hidden", hidden)
+	}
+	note, _ := all[0].Evidence[EvidenceKey].(domain.Attrs)
+	if note["status"] != StatusFalsePositive {
+		t.Fatalf("expected false_positive annotation, got %#v", all[0].Evidence)
+	}
+	if godDet.Evidence != nil {
+		t.Fatalf("Apply must not modify the input detections")
+	}
+
+	visible, hidden := Apply(dets, fbs, now, true)
+	if hidden != 1 || len(visible) != 2 {
+		t.Fatalf("only the false positive should be hidden (accepted risk expired), got %d hidden", hidden)
+	}
+	if note, _ := visible[0].Evidence[EvidenceKey].(domain.Attrs); note["expired"] != true {
+		t.Fatalf("expected expired accepted risk annotation, got %#v", visible[0].Evidence)
+	}
+}
+
+func TestService_TrainingDataLabels(t *testing.T) {
+	store := &memStore{list: []Feedback{
+		{DetectionKey: "a", Status: StatusFalsePositive},
+		{DetectionKey: "b", Status: StatusAcceptedRisk, Reason: "ok"},
+		{DetectionKey: "c", Status: StatusWillFix},
+	}}
+	ex, err := NewService(store).TrainingData("u", "p")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ex) != 3 || ex[0].Label != 0 || ex[1].Label != 1 || ex[2].Label != 1 {
+		t.Fatalf("unexpected labels: %+v", ex)
+	}
+}
@@ -0,0 +1,78 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+type cyElement struct {
+	Data    map[string]any `json:"data"`
+	Classes string         `json:"classes,omitempty"`
+}
+
+type cyDocument struct {
+	Data     map[string]any `json:"data"`
+	Elements struct {
+		Nodes []cyElement `json:"nodes"`
+		Edges []cyElement `json:"edges"`
+	} `json:"elements"`
+}
+
+// ToCytoscape renders g as Cytoscape.js JSON (cy.json() shape). Every element has a
+// kind-<kind> class; elements with findings also get "finding" and their severity
+// (high/medium/low) as classes, plus severity and antipatterns in data.
+func ToCytoscape(g *domain.Graph, title string, dets []domain.Detection) (string, error) {
+	nodeFindings, edgeFindings := findingsOf(dets)
+	var doc cyDocument
+	doc.Data = map[string]any{"title": title}
+	doc.Elements.Nodes = []cyElement{}
+	doc.Elements.Edges = []cyElement{}
+
+	for _, n := range sortedNodes(g) {
+		el := cyElement{
+			Data:    map[string]any{"id": n.ID, "label": n.Name, "kind": string(n.Kind)},
+			Classes: "kind-" + strings.ToLower(string(n.Kind)),
+		}
+		if f := nodeFindings[n.ID]; f != nil {
+			el.Data["severity"] = string(f.sev)
+			el.Data["antipatterns"] = f.tooltip()
+			el.Classes += " finding " + f.severityClass()
+		}
+		doc.Elements.Nodes = append(doc.Elements.Nodes, el)
+	}
+	for i, e := range g.Edges {
+		if e == nil || g.Nodes[e.From] == nil || g.Nodes[e.To] == nil {
+			continue
+		}
+		el := cyElement{
+			Data: map[string]any{
+				"id":     fmt.Sprintf("e%d", i),
+				"source": e.From,
+				"target": e.To,
+				"kind":   string(e.Kind),
+				"label":  edgeLabel(e),
+			},
+			Classes: "kind-" + strings.ToLower(string(e.Kind)),
+		}
+		if s, ok := e.Attrs["sync"].(bool); ok {
+			el.Data["sync"] = s
+			if !s {
+				el.Classes += " async"
+			}
+		}
+		if f := edgeFindings[i]; f != nil {
+			el.Data["severity"] = string(f.sev)
+			el.Data["antipatterns"] = f.tooltip()
+			el.Classes += " finding " + f.severityClass()
+		}
+		doc.Elements.Edges = append(doc.Elements.Edges, el)
+	}
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,53 @@
+package export
+
+import (
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+// DiffStatus marks a node or edge of an annotated diff graph.
+type DiffStatus string
+
+const (
+	DiffAdded   DiffStatus = "added"
+	DiffRemoved DiffStatus = "removed"
+	DiffChanged DiffStatus = "changed"
+)
+
+// diffColors are the (border, fill) colours of each diff status.
+var diffColors = map[DiffStatus][2]string{
+	DiffAdded:   {"#1e8449", "#d5f5e3"},
+	DiffRemoved: {"#c0392b", "#fadbd8"},
+	DiffChanged: {"#2471a3", "#d6eaf8"},
+}
+
+// DiffMarks are the statuses of the nodes (by ID) and edges (by index) of a diff graph;
+// unmarked elements are unchanged.
+type DiffMarks struct {
+	Nodes map[string]DiffStatus
+	Edges map[int]DiffStatus
+}
+
+func (m DiffMarks) findings() (map[string]*finding, map[int]*finding) {
+	nodeFindings := map[string]*finding{}
+	edgeFindings := map[int]*finding{}
+	for id, st := range m.Nodes {
+		nodeFindings[id] = &finding{status: st}
+	}
+	for i, st := range m.Edges {
+		edgeFindings[i] = &finding{status: st}
+	}
+	return nodeFindings, edgeFindings
+}
+
+// ToDiffDOT renders a diff graph (both versions merged) as DOT: additions green, removals red
+// and dashed, changes blue.
+func ToDiffDOT(g *domain.Graph, title string, marks DiffMarks) string {
+	nodeFindings, edgeFindings := marks.findings()
+	return renderDOT(g, title, nodeFindings, edgeFindings)
+}
+
+// ToDiffSVG is ToDiffDOT rendered with the built-in SVG layout.
+func ToDiffSVG(g *domain.Graph, title string, marks DiffMarks) string {
+	nodeFindings, edgeFindings := marks.findings()
+	return renderSVG(g, title, nodeFindings, edgeFindings)
+}
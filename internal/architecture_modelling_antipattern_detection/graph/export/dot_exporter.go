@@ -2,12 +2,132 @@ package export
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
 )
 
-func ToDOT(g *domain.Graph, title string) string {
+// severityColors are the (border, fill) colours used to highlight findings.
+var severityColors = map[domain.Severity][2]string{
+	domain.SeverityHigh:   {"#c0392b", "#f8d7da"},
+	domain.SeverityMedium: {"#e67e22", "#ffe5b4"},
+	domain.SeverityLow:    {"#b7950b", "#fff9c4"},
+}
+
+func severityRank(s domain.Severity) int {
+	switch s {
+	case domain.SeverityHigh:
+		return 3
+	case domain.SeverityMedium:
+		return 2
+	case domain.SeverityLow:
+		return 1
+	}
+	return 0
+}
+
+// finding is what the detections say about one node or edge: the worst severity and the anti-patterns involved.
+// In an annotated diff it carries the change status instead (see ToDiffDOT).
+type finding struct {
+	sev    domain.Severity
+	kinds  []string
+	status DiffStatus
+}
+
+func (f *finding) add(d domain.Detection) {
+	if severityRank(d.Severity) > severityRank(f.sev) {
+		f.sev = d.Severity
+	}
+	k := fmt.Sprintf("%s (%s)", d.Kind, d.Severity)
+	for _, x := range f.kinds {
+		if x == k {
+			return
+		}
+	}
+	f.kinds = append(f.kinds, k)
+}
+
+func (f *finding) colors() (border, fill string) {
+	if c, ok := diffColors[f.status]; ok {
+		return c[0], c[1]
+	}
+	c, ok := severityColors[f.sev]
+	if !ok {
+		c = severityColors[domain.SeverityMedium]
+	}
+	return c[0], c[1]
+}
+
+func (f *finding) tooltip() string {
+	if f.status != "" {
+		return string(f.status)
+	}
+	sort.Strings(f.kinds)
+	return strings.Join(f.kinds, "; ")
+}
+
+// findingsOf indexes dets by node ID and by edge index.
+func findingsOf(dets []domain.Detection) (map[string]*finding, map[int]*finding) {
+	nodeFindings := map[string]*finding{}
+	edgeFindings := map[int]*finding{}
+	for _, d := range dets {
+		for _, id := range d.Nodes {
+			if nodeFindings[id] == nil {
+				nodeFindings[id] = &finding{}
+			}
+			nodeFindings[id].add(d)
+		}
+		for _, i := range d.Edges {
+			if edgeFindings[i] == nil {
+				edgeFindings[i] = &finding{}
+			}
+			edgeFindings[i].add(d)
+		}
+	}
+	return nodeFindings, edgeFindings
+}
+
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ").Replace(s)
+}
+
+// edgeLabel describes an edge; CALLS edges include endpoint count, rate, dependency kind and sync mode.
+func edgeLabel(e *domain.Edge) string {
+	lbl := string(e.Kind)
+	if e.Kind != domain.EdgeCalls {
+		return lbl
+	}
+	if c, ok := e.Attrs["count"].(int); ok && c > 0 {
+		lbl = fmt.Sprintf("calls (%d ep)", c)
+	}
+	if rpm, ok := e.Attrs["rate_per_min"].(int); ok && rpm > 0 {
+		lbl = fmt.Sprintf("%s, %drpm", lbl, rpm)
+	}
+	if pi, ok := e.Attrs["per_item"].(bool); ok && pi {
+		lbl = fmt.Sprintf("%s, per item", lbl)
+	}
+	if k, ok := e.Attrs["dep_kind"].(string); ok && k != "" {
+		lbl = fmt.Sprintf("%s [%s]", lbl, k)
+	}
+	if s, ok := e.Attrs["sync"].(bool); ok {
+		if s {
+			lbl = fmt.Sprintf("%s (sync)", lbl)
+		} else {
+			lbl = fmt.Sprintf("%s (async)", lbl)
+		}
+	}
+	return lbl
+}
+
+// ToDOT renders g as Graphviz DOT. Nodes and edges involved in dets are coloured by the worst
+// severity among their detections, with a tooltip naming the anti-patterns; dets may be nil.
+func ToDOT(g *domain.Graph, title string, dets []domain.Detection) string {
+	nodeFindings, edgeFindings := findingsOf(dets)
+	return renderDOT(g, title, nodeFindings, edgeFindings)
+}
+
+func renderDOT(g *domain.Graph, title string, nodeFindings map[string]*finding, edgeFindings map[int]*finding) string {
 	var b strings.Builder
 	b.WriteString("digraph G {\n  rankdir=LR;\n  node [shape=box, style=rounded];\n")
 	if title != "" {
@@ -23,6 +143,18 @@ func ToDOT(g *domain.Graph, title string) string {
 		if n.Kind == domain.NodeDB {
 			style = `shape=cylinder,style="filled",fillcolor="#fff3cd"`
 		}
+		if f := nodeFindings[n.ID]; f != nil {
+			border, fill := f.colors()
+			shape := `shape=box,style="rounded,filled`
+			if n.Kind == domain.NodeDB {
+				shape = `shape=cylinder,style="filled`
+			}
+			if f.status == DiffRemoved {
+				shape += ",dashed"
+			}
+			shape += `"`
+			style = fmt.Sprintf(`%s,fillcolor="%s",color="%s",penwidth=2,tooltip="%s"`, shape, fill, border, dotEscape(f.tooltip()))
+		}
 		b.WriteString(fmt.Sprintf(`  "%s" [label="%s", %s];`+"\n", n.ID, n.Name, style))
 	}
 
@@ -30,32 +162,17 @@ func ToDOT(g *domain.Graph, title string) string {
 		if e == nil {
 			continue
 		}
-
-		lbl := string(e.Kind)
-
-	
-		if e.Kind == domain.EdgeCalls {
-			
-			if c, ok := e.Attrs["count"].(int); ok && c > 0 {
-				lbl = fmt.Sprintf("calls (%d ep)", c)
-			}
-			if rpm, ok := e.Attrs["rate_per_min"].(int); ok && rpm > 0 {
-				lbl = fmt.Sprintf("%s, %drpm", lbl, rpm)
-			}
-
-			
-			if k, ok := e.Attrs["dep_kind"].(string); ok && k != "" {
-				lbl = fmt.Sprintf("%s [%s]", lbl, k)
-			}
-			if s, ok := e.Attrs["sync"].(bool); ok {
-				if s {
-					lbl = fmt.Sprintf("%s (sync)", lbl)
-				} else {
-					lbl = fmt.Sprintf("%s (async)", lbl)
-				}
+		lbl := edgeLabel(e)
+		if f := edgeFindings[i]; f != nil {
+			border, _ := f.colors()
+			dashed := ""
+			if f.status == DiffRemoved {
+				dashed = ", style=dashed"
 			}
+			b.WriteString(fmt.Sprintf(`  "%s" -> "%s" [label="%s", tooltip="edge#%d: %s", color="%s", fontcolor="%s", penwidth=2.5%s];`+"\n",
+				e.From, e.To, lbl, i, dotEscape(f.tooltip()), border, border, dashed))
+			continue
 		}
-
 		b.WriteString(fmt.Sprintf(`  "%s" -> "%s" [label="%s", tooltip="edge#%d"];`+"\n",
 			e.From, e.To, lbl, i))
 	}
@@ -0,0 +1,46 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+func TestToDOT_HighlightsDetections(t *testing.T) {
+	g := domain.NewGraph()
+	g.AddNode(&domain.Node{ID: "SERVICE:a", Name: "a", Kind: domain.NodeService})
+	g.AddNode(&domain.Node{ID: "SERVICE:b", Name: "b", Kind: domain.NodeService})
+	g.AddNode(&domain.Node{ID: "SERVICE:c", Name: "c", Kind: domain.NodeService})
+	g.AddEdge(&domain.Edge{From: "SERVICE:a", To: "SERVICE:b", Kind: domain.EdgeCalls})
+	g.AddEdge(&domain.Edge{From: "SERVICE:b", To: "SERVICE:c", Kind: domain.EdgeCalls})
+
+	dets := []domain.Detection{
+		{Kind: domain.APGodService, Severity: domain.SeverityMedium, Nodes: []string{"SERVICE:b"}},
+		{Kind: domain.APCycles, Severity: domain.SeverityHigh, Nodes: []string{"SERVICE:a", "SERVICE:b"}, Edges: []int{0}},
+	}
+	dot := ToDOT(g, "t", dets)
+
+	line := func(prefix string) string {
+		for _, l := range strings.Split(dot, "\n") {
+			if strings.HasPrefix(strings.TrimSpace(l), prefix) {
+				return l
+			}
+		}
+		t.Fatalf("no line starting with %s in\n%s", prefix, dot)
+		return ""
+	}
+	b := line(`"SERVICE:b" [`)
+	if !strings.Contains(b, severityColors[domain.SeverityHigh][0]) || !strings.Contains(b, "cycles (HIGH); god_service (MEDIUM)") {
+		t.Fatalf("b should be HIGH with both kinds in tooltip: %s", b)
+	}
+	if e := line(`"SERVICE:a" -> "SERVICE:b"`); !strings.Contains(e, "edge#0: cycles (HIGH)") || !strings.Contains(e, "penwidth") {
+		t.Fatalf("edge 0 should be highlighted: %s", e)
+	}
+	if e := line(`"SERVICE:b" -> "SERVICE:c"`); strings.Contains(e, "penwidth") {
+		t.Fatalf("edge 1 should be plain: %s", e)
+	}
+	if c := line(`"SERVICE:c" [`); strings.Contains(c, "tooltip") {
+		t.Fatalf("c should be plain: %s", c)
+	}
+}
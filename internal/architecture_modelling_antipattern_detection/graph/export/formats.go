@@ -0,0 +1,121 @@
+package export
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+// Format describes one diagram format Render can produce.
+type Format struct {
+	Name        string `json:"name"`
+	Ext         string `json:"ext"`
+	ContentType string `json:"content_type"`
+}
+
+const (
+	FormatDOT       = "dot"
+	FormatSVG       = "svg"
+	FormatMermaid   = "mermaid"
+	FormatPlantUML  = "plantuml"
+	FormatGraphML   = "graphml"
+	FormatCytoscape = "cytoscape"
+)
+
+var formats = map[string]Format{
+	FormatDOT:       {FormatDOT, "dot", "text/vnd.graphviz; charset=utf-8"},
+	FormatSVG:       {FormatSVG, "svg", "image/svg+xml"},
+	FormatMermaid:   {FormatMermaid, "mmd", "text/plain; charset=utf-8"},
+	FormatPlantUML:  {FormatPlantUML, "puml", "text/plain; charset=utf-8"},
+	FormatGraphML:   {FormatGraphML, "graphml", "application/graphml+xml"},
+	FormatCytoscape: {FormatCytoscape, "cyjs.json", "application/json"},
+}
+
+// FormatNames lists the formats Render accepts, sorted.
+func FormatNames() []string {
+	out := make([]string, 0, len(formats))
+	for name := range formats {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// LookupFormat resolves a format name (case-insensitive; "puml", "mmd" and "cyjs" are accepted aliases).
+func LookupFormat(name string) (Format, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	switch name {
+	case "puml":
+		name = FormatPlantUML
+	case "mmd":
+		name = FormatMermaid
+	case "cyjs", "cytoscape.js":
+		name = FormatCytoscape
+	}
+	f, ok := formats[name]
+	if !ok {
+		return Format{}, fmt.Errorf("unsupported format %q (want one of %s)", name, strings.Join(FormatNames(), ", "))
+	}
+	return f, nil
+}
+
+// Render draws g in the named format with dets highlighted. SVG uses the built-in renderer.
+func Render(format string, g *domain.Graph, title string, dets []domain.Detection) (string, Format, error) {
+	f, err := LookupFormat(format)
+	if err != nil {
+		return "", Format{}, err
+	}
+	switch f.Name {
+	case FormatDOT:
+		return ToDOT(g, title, dets), f, nil
+	case FormatSVG:
+		return ToSVG(g, title, dets), f, nil
+	case FormatMermaid:
+		return ToMermaid(g, title, dets), f, nil
+	case FormatPlantUML:
+		return ToPlantUML(g, title, dets), f, nil
+	case FormatGraphML:
+		return ToGraphML(g, title, dets), f, nil
+	default:
+		s, err := ToCytoscape(g, title, dets)
+		return s, f, err
+	}
+}
+
+// sortedNodes returns g's nodes ordered by ID so text exports are stable.
+func sortedNodes(g *domain.Graph) []*domain.Node {
+	out := make([]*domain.Node, 0, len(g.Nodes))
+	for _, n := range g.Nodes {
+		if n != nil {
+			out = append(out, n)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// aliases maps node IDs to short identifiers (n0, n1, ...) for formats that do not accept
+// "KIND:name" as an identifier.
+func aliases(nodes []*domain.Node) map[string]string {
+	out := make(map[string]string, len(nodes))
+	for i, n := range nodes {
+		out[n.ID] = fmt.Sprintf("n%d", i)
+	}
+	return out
+}
+
+// isAsync reports whether e is explicitly marked sync: false.
+func isAsync(e *domain.Edge) bool {
+	s, ok := e.Attrs["sync"].(bool)
+	return ok && !s
+}
+
+// severityClass is the lower-case severity used as a class / stereotype name.
+func (f *finding) severityClass() string {
+	if _, ok := severityColors[f.sev]; !ok {
+		return strings.ToLower(string(domain.SeverityMedium))
+	}
+	return strings.ToLower(string(f.sev))
+}
@@ -0,0 +1,129 @@
+package export
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+func formatsGraph() (*domain.Graph, []domain.Detection) {
+	g := domain.NewGraph()
+	g.AddNode(&domain.Node{ID: "API_GATEWAY:edge", Name: "edge", Kind: domain.NodeAPIGateway})
+	g.AddNode(&domain.Node{ID: "SERVICE:orders", Name: "orders", Kind: domain.NodeService})
+	g.AddNode(&domain.Node{ID: "DATABASE:db", Name: "db", Kind: domain.NodeDB})
+	g.AddNode(&domain.Node{ID: "EVENT_TOPIC:created", Name: "created", Kind: domain.NodeEventTopic})
+	g.AddEdge(&domain.Edge{From: "API_GATEWAY:edge", To: "SERVICE:orders", Kind: domain.EdgeCalls, Attrs: domain.Attrs{"sync": true}})
+	g.AddEdge(&domain.Edge{From: "SERVICE:orders", To: "DATABASE:db", Kind: domain.EdgeWrites})
+	g.AddEdge(&domain.Edge{From: "SERVICE:orders", To: "EVENT_TOPIC:created", Kind: domain.EdgeCalls, Attrs: domain.Attrs{"sync": false}})
+	dets := []domain.Detection{{Kind: domain.APSharedDatabase, Severity: domain.SeverityHigh, Nodes: []string{"DATABASE:db"}, Edges: []int{1}}}
+	return g, dets
+}
+
+func mustContain(t *testing.T, format, out string, wants ...string) {
+	t.Helper()
+	for _, w := range wants {
+		if !strings.Contains(out, w) {
+			t.Fatalf("%s output missing %q:\n%s", format, w, out)
+		}
+	}
+}
+
+func TestToMermaid(t *testing.T) {
+	g, dets := formatsGraph()
+	out := ToMermaid(g, `Shop "v2"`, dets)
+	// Nodes are aliased in ID order: API_GATEWAY:edge=n0, DATABASE:db=n1, EVENT_TOPIC:created=n2, SERVICE:orders=n3.
+	mustContain(t, "mermaid", out,
+		"title: \"Shop #quot;v2#quot;\"",
+		"flowchart LR",
+		`n0{{"edge"}}`, `n1[("db")]`, `n2[/"created"/]`, `n3("orders")`,
+		`n3 -.->|"CALLS (async)"| n2`,
+		"linkStyle 1 stroke:#c0392b",
+		"class n1 high",
+	)
+}
+
+func TestToPlantUML(t *testing.T) {
+	g, dets := formatsGraph()
+	out := ToPlantUML(g, "Shop", dets)
+	mustContain(t, "plantuml", out,
+		"@startuml", "@enduml", "title Shop",
+		`boundary "edge" as n0`,
+		`database "db" as n1 <<HIGH>> #f8d7da;line:c0392b;line.bold`,
+		`queue "created" as n2`,
+		"n3 -[#c0392b,bold]-> n1 : WRITES",
+		"n3 -[dashed]-> n2",
+		"note bottom of n1 : shared_database (HIGH)",
+	)
+}
+
+func TestToGraphML(t *testing.T) {
+	g, dets := formatsGraph()
+	out := ToGraphML(g, "Shop & co", dets)
+	var doc struct {
+		Graph struct {
+			Nodes []struct {
+				ID   string `xml:"id,attr"`
+				Data []struct {
+					Key string `xml:"key,attr"`
+					Val string `xml:",chardata"`
+				} `xml:"data"`
+			} `xml:"node"`
+			Edges []struct {
+				ID string `xml:"id,attr"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("invalid graphml: %v\n%s", err, out)
+	}
+	if len(doc.Graph.Nodes) != 4 || len(doc.Graph.Edges) != 3 {
+		t.Fatalf("got %d nodes, %d edges", len(doc.Graph.Nodes), len(doc.Graph.Edges))
+	}
+	mustContain(t, "graphml", out, `<data key="n_severity">HIGH</data>`, `<edge id="e1" source="SERVICE:orders" target="DATABASE:db">`, "Shop &amp; co")
+}
+
+func TestToCytoscape(t *testing.T) {
+	g, dets := formatsGraph()
+	out, err := ToCytoscape(g, "Shop", dets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Elements struct {
+			Nodes []struct {
+				Data    map[string]any `json:"data"`
+				Classes string         `json:"classes"`
+			} `json:"nodes"`
+			Edges []struct {
+				Data    map[string]any `json:"data"`
+				Classes string         `json:"classes"`
+			} `json:"edges"`
+		} `json:"elements"`
+	}
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatal(err)
+	}
+	db := doc.Elements.Nodes[1]
+	if db.Data["id"] != "DATABASE:db" || db.Classes != "kind-database finding high" {
+		t.Fatalf("db node: %+v", db)
+	}
+	if e := doc.Elements.Edges[2]; e.Data["source"] != "SERVICE:orders" || !strings.Contains(e.Classes, "async") {
+		t.Fatalf("async edge: %+v", e)
+	}
+}
+
+func TestRender_LookupAndAliases(t *testing.T) {
+	g, dets := formatsGraph()
+	for _, name := range append(FormatNames(), "PUML", "mmd", "cyjs") {
+		out, f, err := Render(name, g, "t", dets)
+		if err != nil || out == "" || f.ContentType == "" {
+			t.Fatalf("Render(%q): %v", name, err)
+		}
+	}
+	if _, _, err := Render("png", g, "t", nil); err == nil {
+		t.Fatal("expected an error for an unsupported format")
+	}
+}
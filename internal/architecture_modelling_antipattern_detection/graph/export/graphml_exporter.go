@@ -0,0 +1,70 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+// graphmlKeys are the attribute declarations; severity/antipatterns are only written for
+// elements with findings.
+var graphmlKeys = []struct{ id, target, name, typ string }{
+	{"n_name", "node", "name", "string"},
+	{"n_kind", "node", "kind", "string"},
+	{"n_severity", "node", "severity", "string"},
+	{"n_antipatterns", "node", "antipatterns", "string"},
+	{"e_kind", "edge", "kind", "string"},
+	{"e_label", "edge", "label", "string"},
+	{"e_sync", "edge", "sync", "boolean"},
+	{"e_severity", "edge", "severity", "string"},
+	{"e_antipatterns", "edge", "antipatterns", "string"},
+	{"g_title", "graph", "title", "string"},
+}
+
+// ToGraphML renders g as GraphML. Node and edge ids are the graph's node IDs and "e<index>".
+func ToGraphML(g *domain.Graph, title string, dets []domain.Detection) string {
+	nodeFindings, edgeFindings := findingsOf(dets)
+	data := func(b *strings.Builder, key, val string) {
+		fmt.Fprintf(b, `      <data key="%s">%s</data>`+"\n", key, xmlEscape(val))
+	}
+
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	b.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://graphml.graphdrawing.org/xmlns http://graphml.graphdrawing.org/xmlns/1.0/graphml.xsd">` + "\n")
+	for _, k := range graphmlKeys {
+		fmt.Fprintf(&b, `  <key id="%s" for="%s" attr.name="%s" attr.type="%s"/>`+"\n", k.id, k.target, k.name, k.typ)
+	}
+	b.WriteString(`  <graph id="G" edgedefault="directed">` + "\n")
+	if title != "" {
+		fmt.Fprintf(&b, `    <data key="g_title">%s</data>`+"\n", xmlEscape(title))
+	}
+	for _, n := range sortedNodes(g) {
+		fmt.Fprintf(&b, `    <node id="%s">`+"\n", xmlEscape(n.ID))
+		data(&b, "n_name", n.Name)
+		data(&b, "n_kind", string(n.Kind))
+		if f := nodeFindings[n.ID]; f != nil {
+			data(&b, "n_severity", string(f.sev))
+			data(&b, "n_antipatterns", f.tooltip())
+		}
+		b.WriteString("    </node>\n")
+	}
+	for i, e := range g.Edges {
+		if e == nil || g.Nodes[e.From] == nil || g.Nodes[e.To] == nil {
+			continue
+		}
+		fmt.Fprintf(&b, `    <edge id="e%d" source="%s" target="%s">`+"\n", i, xmlEscape(e.From), xmlEscape(e.To))
+		data(&b, "e_kind", string(e.Kind))
+		data(&b, "e_label", edgeLabel(e))
+		if s, ok := e.Attrs["sync"].(bool); ok {
+			data(&b, "e_sync", fmt.Sprint(s))
+		}
+		if f := edgeFindings[i]; f != nil {
+			data(&b, "e_severity", string(f.sev))
+			data(&b, "e_antipatterns", f.tooltip())
+		}
+		b.WriteString("    </edge>\n")
+	}
+	b.WriteString("  </graph>\n</graphml>\n")
+	return b.String()
+}
@@ -0,0 +1,77 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+func mermaidText(s string) string {
+	return `"` + strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(s) + `"`
+}
+
+// mermaidShape wraps a label in the flowchart shape for node kind k.
+func mermaidShape(k domain.NodeKind, label string) string {
+	switch k {
+	case domain.NodeDB:
+		return "[(" + label + ")]"
+	case domain.NodeAPIGateway:
+		return "{{" + label + "}}"
+	case domain.NodeEventTopic:
+		return "[/" + label + "/]"
+	case domain.NodeClient, domain.NodeUserActor:
+		return "([" + label + "])"
+	case domain.NodeExternalSystem:
+		return "[[" + label + "]]"
+	}
+	return "(" + label + ")"
+}
+
+// ToMermaid renders g as a Mermaid flowchart for Markdown wikis. Nodes with findings get the
+// high/medium/low class and highlighted edges a linkStyle; async edges are dotted.
+func ToMermaid(g *domain.Graph, title string, dets []domain.Detection) string {
+	nodeFindings, edgeFindings := findingsOf(dets)
+	nodes := sortedNodes(g)
+	alias := aliases(nodes)
+
+	var b strings.Builder
+	if title != "" {
+		fmt.Fprintf(&b, "---\ntitle: %s\n---\n", mermaidText(title))
+	}
+	b.WriteString("flowchart LR\n")
+	for _, sev := range []domain.Severity{domain.SeverityHigh, domain.SeverityMedium, domain.SeverityLow} {
+		c := severityColors[sev]
+		fmt.Fprintf(&b, "  classDef %s fill:%s,stroke:%s,stroke-width:2px\n", strings.ToLower(string(sev)), c[1], c[0])
+	}
+	for _, n := range nodes {
+		fmt.Fprintf(&b, "  %s%s\n", alias[n.ID], mermaidShape(n.Kind, mermaidText(n.Name)))
+	}
+
+	var linkStyles []string
+	link := 0
+	for i, e := range g.Edges {
+		if e == nil || alias[e.From] == "" || alias[e.To] == "" {
+			continue
+		}
+		arrow := "-->"
+		if isAsync(e) {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&b, "  %s %s|%s| %s\n", alias[e.From], arrow, mermaidText(edgeLabel(e)), alias[e.To])
+		if f := edgeFindings[i]; f != nil {
+			border, _ := f.colors()
+			linkStyles = append(linkStyles, fmt.Sprintf("  linkStyle %d stroke:%s,stroke-width:3px", link, border))
+		}
+		link++
+	}
+	for _, s := range linkStyles {
+		b.WriteString(s + "\n")
+	}
+	for _, n := range nodes {
+		if f := nodeFindings[n.ID]; f != nil {
+			fmt.Fprintf(&b, "  class %s %s\n", alias[n.ID], f.severityClass())
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,79 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+// plantumlElement is the component-diagram element used for node kind k.
+func plantumlElement(k domain.NodeKind) string {
+	switch k {
+	case domain.NodeDB:
+		return "database"
+	case domain.NodeAPIGateway:
+		return "boundary"
+	case domain.NodeEventTopic:
+		return "queue"
+	case domain.NodeClient:
+		return "node"
+	case domain.NodeUserActor:
+		return "actor"
+	case domain.NodeExternalSystem:
+		return "cloud"
+	}
+	return "component"
+}
+
+func plantumlText(s string) string {
+	return strings.NewReplacer(`"`, `'`, "\n", " ").Replace(s)
+}
+
+// ToPlantUML renders g as a PlantUML component diagram. Nodes with findings carry a
+// <<HIGH>>/<<MEDIUM>>/<<LOW>> stereotype and severity colours; highlighted edges are bold.
+func ToPlantUML(g *domain.Graph, title string, dets []domain.Detection) string {
+	nodeFindings, edgeFindings := findingsOf(dets)
+	nodes := sortedNodes(g)
+	alias := aliases(nodes)
+
+	var b strings.Builder
+	b.WriteString("@startuml\n")
+	if title != "" {
+		fmt.Fprintf(&b, "title %s\n", plantumlText(title))
+	}
+	b.WriteString("left to right direction\n")
+	for _, n := range nodes {
+		fmt.Fprintf(&b, `%s "%s" as %s`, plantumlElement(n.Kind), plantumlText(n.Name), alias[n.ID])
+		if f := nodeFindings[n.ID]; f != nil {
+			border, fill := f.colors()
+			fmt.Fprintf(&b, " <<%s>> %s;line:%s;line.bold", strings.ToUpper(f.severityClass()), fill, strings.TrimPrefix(border, "#"))
+		}
+		b.WriteString("\n")
+	}
+	for i, e := range g.Edges {
+		if e == nil || alias[e.From] == "" || alias[e.To] == "" {
+			continue
+		}
+		var style []string
+		if f := edgeFindings[i]; f != nil {
+			border, _ := f.colors()
+			style = append(style, border, "bold")
+		}
+		if isAsync(e) {
+			style = append(style, "dashed")
+		}
+		arrow := "-->"
+		if len(style) > 0 {
+			arrow = "-[" + strings.Join(style, ",") + "]->"
+		}
+		fmt.Fprintf(&b, "%s %s %s : %s\n", alias[e.From], arrow, alias[e.To], plantumlText(edgeLabel(e)))
+	}
+	for _, n := range nodes {
+		if f := nodeFindings[n.ID]; f != nil {
+			fmt.Fprintf(&b, "note bottom of %s : %s\n", alias[n.ID], plantumlText(f.tooltip()))
+		}
+	}
+	b.WriteString("@enduml\n")
+	return b.String()
+}
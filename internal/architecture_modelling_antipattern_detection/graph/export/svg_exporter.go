@@ -0,0 +1,223 @@
+package export
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/graph/layout"
+)
+
+const (
+	svgFont       = "Helvetica, Arial, sans-serif"
+	svgTitleH     = 32.0
+	svgEdgeColor  = "#555555"
+	svgNodeBorder = "#34495e"
+)
+
+// svgNodeFill is the default fill per node kind, matching the DOT palette where one exists.
+var svgNodeFill = map[domain.NodeKind]string{
+	domain.NodeService:        "#eef6ff",
+	domain.NodeDB:             "#fff3cd",
+	domain.NodeAPIGateway:     "#e8f5e9",
+	domain.NodeEventTopic:     "#f3e5f5",
+	domain.NodeClient:         "#f5f5f5",
+	domain.NodeUserActor:      "#f5f5f5",
+	domain.NodeExternalSystem: "#fafafa",
+}
+
+func xmlEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "'", "&#39;").Replace(s)
+}
+
+func svgNodeSize(n *domain.Node) (float64, float64) {
+	w, h := layout.DefaultSize(n)
+	switch n.Kind {
+	case domain.NodeAPIGateway, domain.NodeEventTopic:
+		w += 24
+	case domain.NodeClient, domain.NodeUserActor:
+		w += 16
+	}
+	return w, h
+}
+
+// ToSVG renders g as SVG with the built-in layered layout, so no Graphviz binary is needed.
+// Shapes follow node kinds (cylinder for databases, hexagon for gateways, parallelogram for
+// topics, ellipse for clients and users, dashed box for external systems); async edges are dashed.
+// Findings in dets are highlighted like ToDOT; dets may be nil.
+func ToSVG(g *domain.Graph, title string, dets []domain.Detection) string {
+	nodeFindings, edgeFindings := findingsOf(dets)
+	return renderSVG(g, title, nodeFindings, edgeFindings)
+}
+
+func renderSVG(g *domain.Graph, title string, nodeFindings map[string]*finding, edgeFindings map[int]*finding) string {
+	lay := layout.Compute(g, layout.Options{Direction: layout.LeftToRight, Size: svgNodeSize})
+
+	top := 0.0
+	if title != "" {
+		top = svgTitleH
+	}
+	width := math.Max(lay.Width, 7.5*float64(len([]rune(title)))+40)
+	height := lay.Height + top
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="%s" font-size="12">`+"\n",
+		width, height, width, height, svgFont)
+	b.WriteString("<defs>\n")
+	markers := []struct{ id, color string }{{"arrow", svgEdgeColor}}
+	for _, sev := range []domain.Severity{domain.SeverityHigh, domain.SeverityMedium, domain.SeverityLow} {
+		markers = append(markers, struct{ id, color string }{"arrow-" + strings.ToLower(string(sev)), severityColors[sev][0]})
+	}
+	for _, st := range []DiffStatus{DiffAdded, DiffRemoved, DiffChanged} {
+		markers = append(markers, struct{ id, color string }{"arrow-" + string(st), diffColors[st][0]})
+	}
+	for _, m := range markers {
+		fmt.Fprintf(&b, `<marker id="%s" viewBox="0 0 10 10" refX="9" refY="5" markerWidth="8" markerHeight="8" orient="auto-start-reverse"><path d="M0,0 L10,5 L0,10 z" fill="%s"/></marker>`+"\n", m.id, m.color)
+	}
+	b.WriteString("</defs>\n")
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="#ffffff"/>`+"\n")
+	if title != "" {
+		fmt.Fprintf(&b, `<text x="%.1f" y="22" text-anchor="middle" font-size="15" font-weight="bold">%s</text>`+"\n", width/2, xmlEscape(title))
+	}
+	fmt.Fprintf(&b, `<g transform="translate(%.1f,%.1f)">`+"\n", (width-lay.Width)/2, top)
+
+	// Edges first so nodes are drawn over their ends.
+	b.WriteString(`<g class="edges">` + "\n")
+	for _, r := range lay.Edges {
+		e := g.Edges[r.Index]
+		color, marker, stroke := svgEdgeColor, "arrow", 1.2
+		tip := fmt.Sprintf("edge#%d: %s -> %s", r.Index, e.From, e.To)
+		if f := edgeFindings[r.Index]; f != nil {
+			color, _ = f.colors()
+			marker = "arrow-" + strings.ToLower(string(f.sev))
+			if _, ok := severityColors[f.sev]; !ok {
+				marker = "arrow-medium"
+			}
+			if f.status != "" {
+				marker = "arrow-" + string(f.status)
+			}
+			stroke = 2.5
+			tip = fmt.Sprintf("%s; %s", tip, f.tooltip())
+		}
+		dash := ""
+		if s, ok := e.Attrs["sync"].(bool); ok && !s {
+			dash = ` stroke-dasharray="6,4"`
+		}
+		if f := edgeFindings[r.Index]; f != nil && f.status == DiffRemoved {
+			dash = ` stroke-dasharray="2,3"`
+		}
+		fmt.Fprintf(&b, `<g class="edge" data-index="%d"><title>%s</title>`, r.Index, xmlEscape(tip))
+		fmt.Fprintf(&b, `<path d="%s" fill="none" stroke="%s" stroke-width="%.1f"%s marker-end="url(#%s)"/>`,
+			svgPath(r.Points), color, stroke, dash, marker)
+		if lbl := edgeLabel(e); lbl != "" {
+			p := labelPoint(r.Points)
+			fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle" font-size="10" fill="%s" stroke="#ffffff" stroke-width="3" paint-order="stroke">%s</text>`,
+				p.X, p.Y-4, color, xmlEscape(lbl))
+		}
+		b.WriteString("</g>\n")
+	}
+	b.WriteString("</g>\n")
+
+	ids := make([]string, 0, len(lay.Nodes))
+	for id := range lay.Nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	b.WriteString(`<g class="nodes">` + "\n")
+	for _, id := range ids {
+		n, box := g.Nodes[id], lay.Nodes[id]
+		fill, ok := svgNodeFill[n.Kind]
+		if !ok {
+			fill = svgNodeFill[domain.NodeService]
+		}
+		border, stroke := svgNodeBorder, 1.2
+		tip := fmt.Sprintf("%s (%s)", n.Name, n.Kind)
+		if f := nodeFindings[id]; f != nil {
+			border, fill = f.colors()
+			stroke = 2.5
+			tip = fmt.Sprintf("%s: %s", tip, f.tooltip())
+		}
+		fmt.Fprintf(&b, `<g class="node" id="%s"><title>%s</title>`, xmlEscape(id), xmlEscape(tip))
+		shape := svgShape(n.Kind, box, fill, border, stroke)
+		if f := nodeFindings[id]; f != nil && f.status == DiffRemoved {
+			shape = strings.Replace(shape, "/>", ` stroke-dasharray="4,3"/>`, 1)
+		}
+		b.WriteString(shape)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle" dominant-baseline="central">%s</text>`,
+			box.X, labelY(n.Kind, box), xmlEscape(n.Name))
+		b.WriteString("</g>\n")
+	}
+	b.WriteString("</g>\n</g>\n</svg>\n")
+	return b.String()
+}
+
+// svgShape draws the outline of a node of kind k centred in box.
+func svgShape(k domain.NodeKind, box layout.Box, fill, border string, stroke float64) string {
+	x0, y0 := box.X-box.W/2, box.Y-box.H/2
+	x1, y1 := box.X+box.W/2, box.Y+box.H/2
+	attrs := fmt.Sprintf(`fill="%s" stroke="%s" stroke-width="%.1f"`, fill, border, stroke)
+	switch k {
+	case domain.NodeDB:
+		ry := box.H / 8
+		return fmt.Sprintf(`<path d="M%.1f,%.1f A%.1f,%.1f 0 0 1 %.1f,%.1f L%.1f,%.1f A%.1f,%.1f 0 0 1 %.1f,%.1f Z" %s/>`+
+			`<path d="M%.1f,%.1f A%.1f,%.1f 0 0 0 %.1f,%.1f" fill="none" stroke="%s" stroke-width="%.1f"/>`,
+			x0, y0+ry, box.W/2, ry, x1, y0+ry, x1, y1-ry, box.W/2, ry, x0, y1-ry, attrs,
+			x0, y0+ry, box.W/2, ry, x1, y0+ry, border, stroke)
+	case domain.NodeAPIGateway:
+		d := box.H / 2
+		return fmt.Sprintf(`<polygon points="%.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f" %s/>`,
+			x0, box.Y, x0+d, y0, x1-d, y0, x1, box.Y, x1-d, y1, x0+d, y1, attrs)
+	case domain.NodeEventTopic:
+		d := box.H / 2
+		return fmt.Sprintf(`<polygon points="%.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f" %s/>`,
+			x0+d, y0, x1, y0, x1-d, y1, x0, y1, attrs)
+	case domain.NodeClient, domain.NodeUserActor:
+		return fmt.Sprintf(`<ellipse cx="%.1f" cy="%.1f" rx="%.1f" ry="%.1f" %s/>`, box.X, box.Y, box.W/2, box.H/2, attrs)
+	case domain.NodeExternalSystem:
+		return fmt.Sprintf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" %s stroke-dasharray="5,3"/>`, x0, y0, box.W, box.H, attrs)
+	}
+	return fmt.Sprintf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" rx="8" ry="8" %s/>`, x0, y0, box.W, box.H, attrs)
+}
+
+// labelY keeps database labels below the cylinder's top ellipse.
+func labelY(k domain.NodeKind, box layout.Box) float64 {
+	if k == domain.NodeDB {
+		return box.Y + box.H/16
+	}
+	return box.Y
+}
+
+func svgPath(pts []layout.Point) string {
+	var b strings.Builder
+	for i, p := range pts {
+		if i == 0 {
+			fmt.Fprintf(&b, "M%.1f,%.1f", p.X, p.Y)
+			continue
+		}
+		fmt.Fprintf(&b, " L%.1f,%.1f", p.X, p.Y)
+	}
+	return b.String()
+}
+
+// labelPoint is the midpoint of the polyline measured along its length.
+func labelPoint(pts []layout.Point) layout.Point {
+	if len(pts) == 0 {
+		return layout.Point{}
+	}
+	total := 0.0
+	for i := 1; i < len(pts); i++ {
+		total += math.Hypot(pts[i].X-pts[i-1].X, pts[i].Y-pts[i-1].Y)
+	}
+	half := total / 2
+	for i := 1; i < len(pts); i++ {
+		seg := math.Hypot(pts[i].X-pts[i-1].X, pts[i].Y-pts[i-1].Y)
+		if seg >= half && seg > 0 {
+			t := half / seg
+			return layout.Point{X: pts[i-1].X + t*(pts[i].X-pts[i-1].X), Y: pts[i-1].Y + t*(pts[i].Y-pts[i-1].Y)}
+		}
+		half -= seg
+	}
+	return pts[len(pts)-1]
+}
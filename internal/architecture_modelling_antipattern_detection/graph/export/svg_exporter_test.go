@@ -0,0 +1,64 @@
+package export
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+func TestToSVG_ShapesLabelsAndHighlights(t *testing.T) {
+	g := domain.NewGraph()
+	g.AddNode(&domain.Node{ID: "API_GATEWAY:edge", Name: "edge", Kind: domain.NodeAPIGateway})
+	g.AddNode(&domain.Node{ID: "SERVICE:orders", Name: "orders <v2>", Kind: domain.NodeService})
+	g.AddNode(&domain.Node{ID: "DATABASE:orders-db", Name: "orders-db", Kind: domain.NodeDB})
+	g.AddNode(&domain.Node{ID: "EVENT_TOPIC:created", Name: "created", Kind: domain.NodeEventTopic})
+	g.AddEdge(&domain.Edge{From: "API_GATEWAY:edge", To: "SERVICE:orders", Kind: domain.EdgeCalls, Attrs: domain.Attrs{"sync": true}})
+	g.AddEdge(&domain.Edge{From: "SERVICE:orders", To: "DATABASE:orders-db", Kind: domain.EdgeWrites})
+	g.AddEdge(&domain.Edge{From: "SERVICE:orders", To: "EVENT_TOPIC:created", Kind: domain.EdgeCalls, Attrs: domain.Attrs{"sync": false}})
+
+	dets := []domain.Detection{{Kind: domain.APSharedDatabase, Severity: domain.SeverityHigh, Nodes: []string{"DATABASE:orders-db"}, Edges: []int{1}}}
+	svg := ToSVG(g, "Shop & co", dets)
+
+	if err := xml.Unmarshal([]byte(svg), new(struct{})); err != nil {
+		t.Fatalf("not well-formed XML: %v\n%s", err, svg)
+	}
+	for _, want := range []string{
+		"Shop &amp; co",
+		"orders &lt;v2&gt;",
+		"<polygon",               // gateway hexagon / topic parallelogram
+		" A",                     // cylinder arcs
+		"CALLS (sync)",           // edge label
+		`stroke-dasharray="6,4"`, // async edge
+		"url(#arrow-high)",
+		severityColors[domain.SeverityHigh][1],
+		"shared_database (HIGH)",
+	} {
+		if !strings.Contains(svg, want) {
+			t.Fatalf("svg missing %q:\n%s", want, svg)
+		}
+	}
+}
+
+func TestToDiffSVG_MarksChanges(t *testing.T) {
+	g := domain.NewGraph()
+	g.AddNode(&domain.Node{ID: "SERVICE:a", Name: "a", Kind: domain.NodeService})
+	g.AddNode(&domain.Node{ID: "SERVICE:b", Name: "b", Kind: domain.NodeService})
+	g.AddNode(&domain.Node{ID: "SERVICE:old", Name: "old", Kind: domain.NodeService})
+	g.AddEdge(&domain.Edge{From: "SERVICE:a", To: "SERVICE:b", Kind: domain.EdgeCalls})
+	g.AddEdge(&domain.Edge{From: "SERVICE:a", To: "SERVICE:old", Kind: domain.EdgeCalls})
+	marks := DiffMarks{
+		Nodes: map[string]DiffStatus{"SERVICE:b": DiffAdded, "SERVICE:old": DiffRemoved},
+		Edges: map[int]DiffStatus{0: DiffAdded, 1: DiffRemoved},
+	}
+	svg := ToDiffSVG(g, "", marks)
+	if err := xml.Unmarshal([]byte(svg), new(struct{})); err != nil {
+		t.Fatalf("not well-formed XML: %v\n%s", err, svg)
+	}
+	for _, want := range []string{"url(#arrow-added)", "url(#arrow-removed)", diffColors[DiffAdded][1], `stroke-dasharray="4,3"`, "<title>old (SERVICE): removed</title>"} {
+		if !strings.Contains(svg, want) {
+			t.Fatalf("svg missing %q:\n%s", want, svg)
+		}
+	}
+}
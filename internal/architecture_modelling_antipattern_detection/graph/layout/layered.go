@@ -0,0 +1,532 @@
+// Package layout computes a layered (Sugiyama-style) drawing of a domain.Graph so it can be
+// rendered without Graphviz. The phases are the classic ones: break cycles by reversing back
+// edges, assign layers by longest path, insert dummy vertices on long edges, reduce crossings
+// with barycenter sweeps, then assign coordinates and route edges through their dummies.
+package layout
+
+import (
+	"math"
+	"sort"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Box is a placed node; X/Y is its centre.
+type Box struct {
+	ID string  `json:"id"`
+	X  float64 `json:"x"`
+	Y  float64 `json:"y"`
+	W  float64 `json:"w"`
+	H  float64 `json:"h"`
+}
+
+// Route is the polyline of g.Edges[Index], from the source boundary to the target boundary.
+type Route struct {
+	Index  int     `json:"index"`
+	Points []Point `json:"points"`
+}
+
+type Layout struct {
+	Width  float64        `json:"width"`
+	Height float64        `json:"height"`
+	Nodes  map[string]Box `json:"nodes"`
+	Edges  []Route        `json:"edges"`
+}
+
+const (
+	LeftToRight = "LR"
+	TopToBottom = "TB"
+)
+
+type Options struct {
+	// Direction is LeftToRight (default, like rankdir=LR) or TopToBottom.
+	Direction string
+	// NodeSep is the gap between nodes in a layer; RankSep the gap between layers.
+	NodeSep float64
+	RankSep float64
+	Margin  float64
+	// Size returns a node's width and height; nil uses DefaultSize.
+	Size func(n *domain.Node) (w, h float64)
+	// Sweeps is the number of barycenter sweeps for crossing reduction.
+	Sweeps int
+}
+
+// DefaultSize sizes a node from its label length.
+func DefaultSize(n *domain.Node) (float64, float64) {
+	w := math.Max(90, 7.2*float64(len([]rune(n.Name)))+32)
+	h := 40.0
+	if n.Kind == domain.NodeDB {
+		h = 54
+	}
+	return w, h
+}
+
+func (o Options) withDefaults() Options {
+	if o.Direction != TopToBottom {
+		o.Direction = LeftToRight
+	}
+	if o.NodeSep <= 0 {
+		o.NodeSep = 28
+	}
+	if o.RankSep <= 0 {
+		o.RankSep = 90
+	}
+	if o.Margin <= 0 {
+		o.Margin = 20
+	}
+	if o.Size == nil {
+		o.Size = DefaultSize
+	}
+	if o.Sweeps <= 0 {
+		o.Sweeps = 12
+	}
+	return o
+}
+
+// vertex is a real node or a dummy on a long edge. breadth is its extent along the layer,
+// depth its extent across layers.
+type vertex struct {
+	id      string
+	dummy   bool
+	layer   int
+	breadth float64
+	depth   float64
+	pos     float64 // coordinate along the layer (centre)
+	order   int
+	preds   []int
+	succs   []int
+}
+
+type layered struct {
+	vs     []*vertex
+	layers [][]int
+}
+
+// Compute lays out g. The result is deterministic for a given graph.
+func Compute(g *domain.Graph, opt Options) *Layout {
+	opt = opt.withDefaults()
+	out := &Layout{Nodes: map[string]Box{}, Edges: []Route{}}
+	if g == nil || len(g.Nodes) == 0 {
+		out.Width, out.Height = 2*opt.Margin, 2*opt.Margin
+		return out
+	}
+
+	ids := make([]string, 0, len(g.Nodes))
+	for id, n := range g.Nodes {
+		if n != nil {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	L := &layered{}
+	index := make(map[string]int, len(ids))
+	for _, id := range ids {
+		w, h := opt.Size(g.Nodes[id])
+		v := &vertex{id: id, breadth: h, depth: w}
+		if opt.Direction == TopToBottom {
+			v.breadth, v.depth = w, h
+		}
+		index[id] = len(L.vs)
+		L.vs = append(L.vs, v)
+	}
+
+	// Phase 1: cycle breaking. Unique (u, v) pairs drive the layering; reversed marks back edges.
+	type pair struct{ u, v int }
+	adj := make([][]int, len(ids))
+	seenPair := map[pair]bool{}
+	for _, e := range g.Edges {
+		if e == nil {
+			continue
+		}
+		u, okU := index[e.From]
+		v, okV := index[e.To]
+		if !okU || !okV || u == v || seenPair[pair{u, v}] {
+			continue
+		}
+		seenPair[pair{u, v}] = true
+		adj[u] = append(adj[u], v)
+	}
+	for _, a := range adj {
+		sort.Ints(a)
+	}
+	reversed := map[pair]bool{}
+	state := make([]int, len(ids)) // 0 new, 1 on stack, 2 done
+	var dfs func(u int)
+	dfs = func(u int) {
+		state[u] = 1
+		for _, v := range adj[u] {
+			switch state[v] {
+			case 0:
+				dfs(v)
+			case 1:
+				reversed[pair{u, v}] = true
+			}
+		}
+		state[u] = 2
+	}
+	for u := range ids {
+		if state[u] == 0 {
+			dfs(u)
+		}
+	}
+	dagSucc := make([][]int, len(ids))
+	added := map[pair]bool{}
+	for u, a := range adj {
+		for _, v := range a {
+			from, to := u, v
+			if reversed[pair{u, v}] {
+				from, to = v, u
+			}
+			if added[pair{from, to}] {
+				continue
+			}
+			added[pair{from, to}] = true
+			dagSucc[from] = append(dagSucc[from], to)
+		}
+	}
+
+	// Phase 2: longest-path layering in topological order.
+	indeg := make([]int, len(ids))
+	for _, s := range dagSucc {
+		for _, v := range s {
+			indeg[v]++
+		}
+	}
+	queue := []int{}
+	for u := range ids {
+		if indeg[u] == 0 {
+			queue = append(queue, u)
+		}
+	}
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		for _, v := range dagSucc[u] {
+			if L.vs[u].layer+1 > L.vs[v].layer {
+				L.vs[v].layer = L.vs[u].layer + 1
+			}
+			indeg[v]--
+			if indeg[v] == 0 {
+				queue = append(queue, v)
+			}
+		}
+	}
+
+	// Phase 3: dummy vertices; chains[u,v] is the vertex path of the layered edge.
+	chains := map[pair][]int{}
+	for u := range ids {
+		for _, v := range dagSucc[u] {
+			chain := []int{u}
+			prev := u
+			for l := L.vs[u].layer + 1; l < L.vs[v].layer; l++ {
+				d := &vertex{dummy: true, layer: l, breadth: 2, depth: 0}
+				L.vs = append(L.vs, d)
+				di := len(L.vs) - 1
+				L.vs[prev].succs = append(L.vs[prev].succs, di)
+				d.preds = append(d.preds, prev)
+				chain = append(chain, di)
+				prev = di
+			}
+			L.vs[prev].succs = append(L.vs[prev].succs, v)
+			L.vs[v].preds = append(L.vs[v].preds, prev)
+			chains[pair{u, v}] = append(chain, v)
+		}
+	}
+	maxLayer := 0
+	for _, v := range L.vs {
+		if v.layer > maxLayer {
+			maxLayer = v.layer
+		}
+	}
+	L.layers = make([][]int, maxLayer+1)
+	for i, v := range L.vs {
+		L.layers[v.layer] = append(L.layers[v.layer], i)
+	}
+
+	// Phase 4: crossing reduction.
+	L.reorder(opt.Sweeps)
+
+	// Phase 5: coordinates.
+	L.assignPositions(opt.NodeSep, opt.Sweeps)
+	rankPos := make([]float64, len(L.layers))
+	cursor := opt.Margin
+	for l, layer := range L.layers {
+		depth := 0.0
+		for _, vi := range layer {
+			depth = math.Max(depth, L.vs[vi].depth)
+		}
+		rankPos[l] = cursor + depth/2
+		cursor += depth + opt.RankSep
+	}
+	minPos := math.Inf(1)
+	maxPos := math.Inf(-1)
+	for _, v := range L.vs {
+		minPos = math.Min(minPos, v.pos-v.breadth/2)
+		maxPos = math.Max(maxPos, v.pos+v.breadth/2)
+	}
+	shift := opt.Margin - minPos
+	point := func(vi int) Point {
+		v := L.vs[vi]
+		if opt.Direction == TopToBottom {
+			return Point{X: v.pos + shift, Y: rankPos[v.layer]}
+		}
+		return Point{X: rankPos[v.layer], Y: v.pos + shift}
+	}
+	breadthTotal := maxPos - minPos + 2*opt.Margin
+	depthTotal := cursor - opt.RankSep + opt.Margin
+	if opt.Direction == TopToBottom {
+		out.Width, out.Height = breadthTotal, depthTotal
+	} else {
+		out.Width, out.Height = depthTotal, breadthTotal
+	}
+	for _, id := range ids {
+		w, h := opt.Size(g.Nodes[id])
+		p := point(index[id])
+		out.Nodes[id] = Box{ID: id, X: p.X, Y: p.Y, W: w, H: h}
+	}
+
+	// Phase 6: edge routes (original direction, clipped to the node boxes).
+	for i, e := range g.Edges {
+		if e == nil {
+			continue
+		}
+		u, okU := index[e.From]
+		v, okV := index[e.To]
+		if !okU || !okV {
+			continue
+		}
+		src, dst := out.Nodes[e.From], out.Nodes[e.To]
+		if u == v {
+			out.Edges = append(out.Edges, Route{Index: i, Points: selfLoop(src, opt.Direction)})
+			continue
+		}
+		var pts []Point
+		if chain, ok := chains[pair{u, v}]; ok && !reversed[pair{u, v}] {
+			for _, vi := range chain {
+				pts = append(pts, point(vi))
+			}
+		} else if chain, ok := chains[pair{v, u}]; ok {
+			for k := len(chain) - 1; k >= 0; k-- {
+				pts = append(pts, point(chain[k]))
+			}
+		} else {
+			pts = []Point{point(u), point(v)}
+		}
+		pts[0] = clip(src, pts[1])
+		pts[len(pts)-1] = clip(dst, pts[len(pts)-2])
+		out.Edges = append(out.Edges, Route{Index: i, Points: pts})
+	}
+	return out
+}
+
+// reorder runs alternating down/up barycenter sweeps and keeps the ordering with the fewest crossings.
+func (L *layered) reorder(sweeps int) {
+	best := L.snapshot()
+	bestCross := L.crossings()
+	for s := 0; s < sweeps && bestCross > 0; s++ {
+		if s%2 == 0 {
+			for l := 1; l < len(L.layers); l++ {
+				L.sortLayer(l, true)
+			}
+		} else {
+			for l := len(L.layers) - 2; l >= 0; l-- {
+				L.sortLayer(l, false)
+			}
+		}
+		if c := L.crossings(); c < bestCross {
+			bestCross = c
+			best = L.snapshot()
+		}
+	}
+	L.layers = best
+	for _, layer := range L.layers {
+		for i, vi := range layer {
+			L.vs[vi].order = i
+		}
+	}
+}
+
+func (L *layered) snapshot() [][]int {
+	out := make([][]int, len(L.layers))
+	for l, layer := range L.layers {
+		out[l] = append([]int(nil), layer...)
+		for i, vi := range layer {
+			L.vs[vi].order = i
+		}
+	}
+	return out
+}
+
+// sortLayer orders layer l by the mean order of its neighbours in the previous (down) or next layer.
+func (L *layered) sortLayer(l int, down bool) {
+	layer := L.layers[l]
+	bary := make(map[int]float64, len(layer))
+	for _, vi := range layer {
+		nbrs := L.vs[vi].succs
+		if down {
+			nbrs = L.vs[vi].preds
+		}
+		if len(nbrs) == 0 {
+			bary[vi] = float64(L.vs[vi].order)
+			continue
+		}
+		sum := 0.0
+		for _, n := range nbrs {
+			sum += float64(L.vs[n].order)
+		}
+		bary[vi] = sum / float64(len(nbrs))
+	}
+	sort.SliceStable(layer, func(i, j int) bool { return bary[layer[i]] < bary[layer[j]] })
+	for i, vi := range layer {
+		L.vs[vi].order = i
+	}
+}
+
+// crossings counts edge crossings between adjacent layers. Per layer pair it sorts the edges by
+// their upper end and counts the inversions among their lower ends with a Fenwick tree, so a
+// sweep costs O(E log V) rather than comparing every pair of edges.
+func (L *layered) crossings() int {
+	total := 0
+	var segs [][2]int
+	var tree []int
+	for l := 0; l+1 < len(L.layers); l++ {
+		segs = segs[:0]
+		maxB := 0
+		for _, vi := range L.layers[l] {
+			a := L.vs[vi].order
+			for _, s := range L.vs[vi].succs {
+				b := L.vs[s].order
+				segs = append(segs, [2]int{a, b})
+				if b > maxB {
+					maxB = b
+				}
+			}
+		}
+		sort.Slice(segs, func(i, j int) bool {
+			if segs[i][0] != segs[j][0] {
+				return segs[i][0] < segs[j][0]
+			}
+			return segs[i][1] < segs[j][1]
+		})
+		tree = append(tree[:0], make([]int, maxB+2)...)
+		for k, sg := range segs {
+			// Edges seen so far start further left; those ending further right cross sg.
+			notAfter := 0
+			for x := sg[1] + 1; x > 0; x -= x & -x {
+				notAfter += tree[x]
+			}
+			total += k - notAfter
+			for x := sg[1] + 1; x < len(tree); x += x & -x {
+				tree[x]++
+			}
+		}
+	}
+	return total
+}
+
+// assignPositions packs each layer, then pulls vertices toward their neighbours' mean while
+// keeping the order and the minimum gap.
+func (L *layered) assignPositions(gap float64, sweeps int) {
+	for _, layer := range L.layers {
+		cursor := 0.0
+		for _, vi := range layer {
+			v := L.vs[vi]
+			v.pos = cursor + v.breadth/2
+			cursor += v.breadth + gap
+		}
+	}
+	for s := 0; s < sweeps; s++ {
+		down := s%2 == 0
+		for k := range L.layers {
+			l := k
+			if !down {
+				l = len(L.layers) - 1 - k
+			}
+			layer := L.layers[l]
+			want := make([]float64, len(layer))
+			for i, vi := range layer {
+				v := L.vs[vi]
+				nbrs := append(append([]int(nil), v.preds...), v.succs...)
+				if down && len(v.preds) > 0 {
+					nbrs = v.preds
+				} else if !down && len(v.succs) > 0 {
+					nbrs = v.succs
+				}
+				want[i] = v.pos
+				if len(nbrs) > 0 {
+					sum := 0.0
+					for _, n := range nbrs {
+						sum += L.vs[n].pos
+					}
+					want[i] = sum / float64(len(nbrs))
+				}
+			}
+			L.place(layer, want, gap)
+		}
+	}
+}
+
+// place sets positions as close to want as the order and gap allow (mean of a left-packed and a
+// right-packed solution, then one packing pass to restore the gap).
+func (L *layered) place(layer []int, want []float64, gap float64) {
+	n := len(layer)
+	if n == 0 {
+		return
+	}
+	minDist := func(i int) float64 { // between centres of layer[i-1] and layer[i]
+		return L.vs[layer[i-1]].breadth/2 + gap + L.vs[layer[i]].breadth/2
+	}
+	fwd := make([]float64, n)
+	bwd := make([]float64, n)
+	for i := 0; i < n; i++ {
+		fwd[i] = want[i]
+		if i > 0 && fwd[i] < fwd[i-1]+minDist(i) {
+			fwd[i] = fwd[i-1] + minDist(i)
+		}
+	}
+	for i := n - 1; i >= 0; i-- {
+		bwd[i] = want[i]
+		if i < n-1 && bwd[i] > bwd[i+1]-minDist(i+1) {
+			bwd[i] = bwd[i+1] - minDist(i+1)
+		}
+	}
+	for i := 0; i < n; i++ {
+		p := (fwd[i] + bwd[i]) / 2
+		if i > 0 && p < L.vs[layer[i-1]].pos+minDist(i) {
+			p = L.vs[layer[i-1]].pos + minDist(i)
+		}
+		L.vs[layer[i]].pos = p
+	}
+}
+
+// clip moves from b's centre toward p and returns where that ray leaves the box.
+func clip(b Box, p Point) Point {
+	dx, dy := p.X-b.X, p.Y-b.Y
+	if dx == 0 && dy == 0 {
+		return Point{X: b.X, Y: b.Y}
+	}
+	sx, sy := math.Inf(1), math.Inf(1)
+	if dx != 0 {
+		sx = (b.W / 2) / math.Abs(dx)
+	}
+	if dy != 0 {
+		sy = (b.H / 2) / math.Abs(dy)
+	}
+	s := math.Min(math.Min(sx, sy), 1)
+	return Point{X: b.X + dx*s, Y: b.Y + dy*s}
+}
+
+func selfLoop(b Box, dir string) []Point {
+	if dir == TopToBottom {
+		x := b.X + b.W/2
+		return []Point{{x, b.Y - b.H/4}, {x + 24, b.Y - b.H/4}, {x + 24, b.Y + b.H/4}, {x, b.Y + b.H/4}}
+	}
+	y := b.Y - b.H/2
+	return []Point{{b.X - b.W/4, y}, {b.X - b.W/4, y - 24}, {b.X + b.W/4, y - 24}, {b.X + b.W/4, y}}
+}
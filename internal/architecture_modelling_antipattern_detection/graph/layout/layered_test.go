@@ -0,0 +1,108 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+func testGraph(edges ...[2]string) *domain.Graph {
+	g := domain.NewGraph()
+	for _, e := range edges {
+		for _, id := range e {
+			if g.Nodes[id] == nil {
+				g.AddNode(&domain.Node{ID: id, Name: id, Kind: domain.NodeService})
+			}
+		}
+		g.AddEdge(&domain.Edge{From: e[0], To: e[1], Kind: domain.EdgeCalls})
+	}
+	return g
+}
+
+func overlaps(a, b Box) bool {
+	return a.X-a.W/2 < b.X+b.W/2 && b.X-b.W/2 < a.X+a.W/2 &&
+		a.Y-a.H/2 < b.Y+b.H/2 && b.Y-b.H/2 < a.Y+a.H/2
+}
+
+func TestCompute_LayersFollowEdgesAndNodesDoNotOverlap(t *testing.T) {
+	g := testGraph(
+		[2]string{"gw", "a"}, [2]string{"gw", "b"}, [2]string{"a", "c"},
+		[2]string{"b", "c"}, [2]string{"gw", "c"}, [2]string{"c", "a"},
+	)
+	l := Compute(g, Options{})
+	if len(l.Nodes) != 4 || len(l.Edges) != len(g.Edges) {
+		t.Fatalf("got %d nodes, %d routes", len(l.Nodes), len(l.Edges))
+	}
+	if !(l.Nodes["gw"].X < l.Nodes["a"].X && l.Nodes["gw"].X < l.Nodes["b"].X) {
+		t.Fatalf("gw should be left of its callees: %+v", l.Nodes)
+	}
+	ids := []string{"gw", "a", "b", "c"}
+	for i := range ids {
+		for j := i + 1; j < len(ids); j++ {
+			if overlaps(l.Nodes[ids[i]], l.Nodes[ids[j]]) {
+				t.Fatalf("%s and %s overlap: %+v", ids[i], ids[j], l.Nodes)
+			}
+		}
+	}
+	for _, b := range l.Nodes {
+		if b.X-b.W/2 < 0 || b.Y-b.H/2 < 0 || b.X+b.W/2 > l.Width || b.Y+b.H/2 > l.Height {
+			t.Fatalf("%s outside %vx%v canvas: %+v", b.ID, l.Width, l.Height, b)
+		}
+	}
+	// gw -> c spans two layers, so it is routed through a dummy bend.
+	for _, r := range l.Edges {
+		e := g.Edges[r.Index]
+		if e.From == "gw" && e.To == "c" && len(r.Points) < 3 {
+			t.Fatalf("long edge should have a bend: %+v", r.Points)
+		}
+		// Routes start at the source (even for the reversed back edge c -> a).
+		src := l.Nodes[e.From]
+		if d := r.Points[0].X - src.X; d > src.W/2+0.01 || d < -src.W/2-0.01 {
+			t.Fatalf("route %d does not start at %s: %+v", r.Index, e.From, r.Points)
+		}
+	}
+}
+
+func TestCompute_TopToBottomAndDeterministic(t *testing.T) {
+	g := testGraph([2]string{"a", "b"}, [2]string{"b", "c"}, [2]string{"a", "a"})
+	l1 := Compute(g, Options{Direction: TopToBottom})
+	l2 := Compute(g, Options{Direction: TopToBottom})
+	if !(l1.Nodes["a"].Y < l1.Nodes["b"].Y && l1.Nodes["b"].Y < l1.Nodes["c"].Y) {
+		t.Fatalf("TB layout should stack a, b, c: %+v", l1.Nodes)
+	}
+	for id, b := range l1.Nodes {
+		if l2.Nodes[id] != b {
+			t.Fatalf("layout not deterministic for %s: %+v vs %+v", id, b, l2.Nodes[id])
+		}
+	}
+	if got := Compute(domain.NewGraph(), Options{}); len(got.Nodes) != 0 || got.Width <= 0 {
+		t.Fatalf("empty graph: %+v", got)
+	}
+}
+
+func TestCrossings_MatchesPairwiseCount(t *testing.T) {
+	L := &layered{layers: [][]int{{0, 1, 2}, {3, 4, 5, 6}}}
+	succs := map[int][]int{0: {5, 6}, 1: {3, 6}, 2: {3, 4, 4}}
+	for vi := 0; vi < 7; vi++ {
+		L.vs = append(L.vs, &vertex{succs: succs[vi]})
+	}
+	L.snapshot()
+
+	want := 0
+	var segs [][2]int
+	for _, vi := range L.layers[0] {
+		for _, s := range L.vs[vi].succs {
+			segs = append(segs, [2]int{L.vs[vi].order, L.vs[s].order})
+		}
+	}
+	for i := range segs {
+		for j := i + 1; j < len(segs); j++ {
+			if (segs[i][0]-segs[j][0])*(segs[i][1]-segs[j][1]) < 0 {
+				want++
+			}
+		}
+	}
+	if got := L.crossings(); got != want || want == 0 {
+		t.Fatalf("crossings = %d, pairwise count = %d", got, want)
+	}
+}
@@ -26,6 +26,22 @@ func ensureNode(g *domain.Graph, kind domain.NodeKind, name string) string {
 	return id
 }
 
+// setLabels copies a service's labels onto its node (Attrs["labels"]) for custom rules.
+func setLabels(g *domain.Graph, id string, labels map[string]string) {
+	n := g.Nodes[id]
+	if n == nil || len(labels) == 0 {
+		return
+	}
+	if n.Attrs == nil {
+		n.Attrs = domain.Attrs{}
+	}
+	m := make(map[string]any, len(labels))
+	for k, v := range labels {
+		m[k] = v
+	}
+	n.Attrs["labels"] = m
+}
+
 func isNewStyle(s *parser.YSpec) bool {
 	return s != nil && len(s.Dependencies) > 0
 }
@@ -123,6 +139,21 @@ func buildDatabaseNameSet(s *parser.YSpec) map[string]bool {
 	return dbSet
 }
 
+// buildTopicNameSet collects names declared under top-level topics.
+func buildTopicNameSet(s *parser.YSpec) map[string]bool {
+	topicSet := map[string]bool{}
+	if s == nil {
+		return topicSet
+	}
+	for _, t := range s.Topics {
+		n := strings.ToLower(strings.TrimSpace(StripNodeNameRef(t.Name)))
+		if n != "" {
+			topicSet[n] = true
+		}
+	}
+	return topicSet
+}
+
 func findServiceByName(s *parser.YSpec, name string) *parser.YService {
 	if s == nil {
 		return nil
@@ -150,7 +181,8 @@ func looksLikeDB(name string) bool {
 }
 
 // kindForNode maps a declared service entry (name + yaml type) to a graph node kind.
-func kindForNode(name string, serviceType string, dbSet map[string]bool) domain.NodeKind {
+// Names listed under topics become EVENT_TOPIC unless services[] gives them a more specific type.
+func kindForNode(name string, serviceType string, dbSet, topicSet map[string]bool) domain.NodeKind {
 	clean := StripNodeNameRef(name)
 	key := strings.ToLower(strings.TrimSpace(clean))
 	if key == "" {
@@ -162,17 +194,21 @@ func kindForNode(name string, serviceType string, dbSet map[string]bool) domain.
 	if looksLikeDB(clean) {
 		return domain.NodeDB
 	}
-	return nodeKindFromNormalizedYAMLType(normalizeType(serviceType))
+	norm := normalizeType(serviceType)
+	if topicSet[key] && norm == "service" {
+		return domain.NodeEventTopic
+	}
+	return nodeKindFromNormalizedYAMLType(norm)
 }
 
 // kindForReference resolves kind for a dependency/call target that may or may not have a services[] entry.
-func kindForReference(s *parser.YSpec, refName string, dbSet map[string]bool) domain.NodeKind {
+func kindForReference(s *parser.YSpec, refName string, dbSet, topicSet map[string]bool) domain.NodeKind {
 	clean := StripNodeNameRef(refName)
 	if strings.TrimSpace(clean) == "" {
 		return domain.NodeService
 	}
 	if svc := findServiceByName(s, clean); svc != nil {
-		return kindForNode(svc.Name, svc.Type, dbSet)
+		return kindForNode(svc.Name, svc.Type, dbSet, topicSet)
 	}
 	key := strings.ToLower(strings.TrimSpace(clean))
 	if dbSet[key] {
@@ -181,6 +217,9 @@ func kindForReference(s *parser.YSpec, refName string, dbSet map[string]bool) do
 	if looksLikeDB(clean) {
 		return domain.NodeDB
 	}
+	if topicSet[key] {
+		return domain.NodeEventTopic
+	}
 	return domain.NodeService
 }
 
@@ -191,6 +230,7 @@ func ToGraph(s *parser.YSpec) *domain.Graph {
 	}
 
 	dbSet := buildDatabaseNameSet(s)
+	topicSet := buildTopicNameSet(s)
 
 	if isNewStyle(s) {
 		for _, svc := range s.Services {
@@ -198,8 +238,8 @@ func ToGraph(s *parser.YSpec) *domain.Graph {
 			if strings.TrimSpace(name) == "" {
 				continue
 			}
-			k := kindForNode(name, svc.Type, dbSet)
-			_ = ensureNode(g, k, name)
+			k := kindForNode(name, svc.Type, dbSet, topicSet)
+			setLabels(g, ensureNode(g, k, name), svc.Labels)
 		}
 		for _, ds := range s.Datastores {
 			name := StripNodeNameRef(ds.Name)
@@ -215,6 +255,13 @@ func ToGraph(s *parser.YSpec) *domain.Graph {
 			}
 			_ = ensureNode(g, domain.NodeDB, name)
 		}
+		for _, t := range s.Topics {
+			name := StripNodeNameRef(t.Name)
+			if strings.TrimSpace(name) == "" || findServiceByName(s, name) != nil {
+				continue
+			}
+			_ = ensureNode(g, domain.NodeEventTopic, name)
+		}
 
 		for _, dep := range s.Dependencies {
 			fromName := StripNodeNameRef(dep.From)
@@ -226,8 +273,8 @@ func ToGraph(s *parser.YSpec) *domain.Graph {
 
 			fromSvc := findServiceByName(s, fromName)
 			toSvc := findServiceByName(s, toName)
-			fromKind := kindForNode(fromName, getServiceType(s, fromSvc), dbSet)
-			toKind := kindForNode(toName, getServiceType(s, toSvc), dbSet)
+			fromKind := kindForNode(fromName, getServiceType(s, fromSvc), dbSet, topicSet)
+			toKind := kindForNode(toName, getServiceType(s, toSvc), dbSet, topicSet)
 			from := ensureNode(g, fromKind, fromName)
 			to := ensureNode(g, toKind, toName)
 
@@ -235,6 +282,16 @@ func ToGraph(s *parser.YSpec) *domain.Graph {
 				"sync":     dep.Sync,
 				"dep_kind": strings.ToLower(strings.TrimSpace(dep.Kind)),
 			}
+			if dep.RatePerMin > 0 {
+				attrs["rate_per_min"] = dep.RatePerMin
+			}
+			if dep.PerItem {
+				attrs["per_item"] = true
+			}
+			if len(dep.Endpoints) > 0 {
+				attrs["endpoints"] = dep.Endpoints
+				attrs["count"] = len(dep.Endpoints)
+			}
 
 			g.AddEdge(&domain.Edge{
 				From:  from,
@@ -261,13 +318,21 @@ func ToGraph(s *parser.YSpec) *domain.Graph {
 		_ = ensureNode(g, domain.NodeDB, StripNodeNameRef(ds.Name))
 	}
 
+	for _, t := range s.Topics {
+		name := StripNodeNameRef(t.Name)
+		if strings.TrimSpace(name) == "" || findServiceByName(s, name) != nil {
+			continue
+		}
+		_ = ensureNode(g, domain.NodeEventTopic, name)
+	}
+
 	for _, svc := range s.Services {
 		if strings.TrimSpace(svc.Name) == "" {
 			continue
 		}
 		name := StripNodeNameRef(svc.Name)
-		k := kindForNode(name, svc.Type, dbSet)
-		_ = ensureNode(g, k, name)
+		k := kindForNode(name, svc.Type, dbSet, topicSet)
+		setLabels(g, ensureNode(g, k, name), svc.Labels)
 	}
 
 	for _, svc := range s.Services {
@@ -275,7 +340,7 @@ func ToGraph(s *parser.YSpec) *domain.Graph {
 			continue
 		}
 		fromName := StripNodeNameRef(svc.Name)
-		fromKind := kindForNode(fromName, svc.Type, dbSet)
+		fromKind := kindForNode(fromName, svc.Type, dbSet, topicSet)
 		from := ensureNode(g, fromKind, fromName)
 
 		for _, c := range svc.Calls {
@@ -283,7 +348,7 @@ func ToGraph(s *parser.YSpec) *domain.Graph {
 				continue
 			}
 			toName := StripNodeNameRef(c.To)
-			toKind := kindForReference(s, toName, dbSet)
+			toKind := kindForReference(s, toName, dbSet, topicSet)
 			to := ensureNode(g, toKind, toName)
 
 			g.AddEdge(&domain.Edge{
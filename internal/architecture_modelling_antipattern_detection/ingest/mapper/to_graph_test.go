@@ -96,3 +96,90 @@ func TestStripNodeNameRef(t *testing.T) {
 		}
 	}
 }
+
+func TestToGraph_NewStyle_TopicsBecomeEventTopicNodes(t *testing.T) {
+	y := `
+services:
+  - name: orders
+topics:
+  - name: order-created
+dependencies:
+  - from: orders
+    to: order-created
+    kind: event
+`
+	spec, err := parser.ParseYAMLString(y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := ToGraph(spec)
+	n := g.Nodes[idify(domain.NodeEventTopic, "order-created")]
+	if n == nil {
+		t.Fatalf("expected EVENT_TOPIC node order-created, got %#v", g.Nodes)
+	}
+	if len(g.Out[idify(domain.NodeService, "orders")]) != 1 {
+		t.Fatalf("expected orders → order-created edge")
+	}
+}
+
+func TestToGraph_ServiceLabelsBecomeNodeAttrs(t *testing.T) {
+	y := `
+services:
+  - name: pay
+    labels:
+      kind: payment
+  - name: orders
+dependencies:
+  - from: orders
+    to: pay
+    sync: true
+`
+	spec, err := parser.ParseYAMLString(y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := ToGraph(spec)
+	n := g.Nodes[idify(domain.NodeService, "pay")]
+	labels, _ := n.Attrs["labels"].(map[string]any)
+	if labels["kind"] != "payment" {
+		t.Fatalf("expected labels on pay, got %#v", n.Attrs)
+	}
+	if o := g.Nodes[idify(domain.NodeService, "orders")]; o.Attrs["labels"] != nil {
+		t.Fatalf("orders has no labels, got %#v", o.Attrs)
+	}
+}
+
+// A spec with dependencies builds its edges from them alone; services[].calls of such a spec
+// are not added (saved versions must keep their edges and detections).
+func TestToGraph_NewStyle_IgnoresServiceCalls(t *testing.T) {
+	y := `
+services:
+  - name: orders
+    calls:
+      - to: billing
+        endpoints: ["POST /invoices"]
+      - to: stock
+  - name: billing
+  - name: stock
+dependencies:
+  - from: orders
+    to: billing
+    kind: rest
+    sync: true
+`
+	spec, err := parser.ParseYAMLString(y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := ToGraph(spec)
+	if len(g.Edges) != 1 {
+		t.Fatalf("expected only the dependency edge, got %d edges", len(g.Edges))
+	}
+	e := g.Edges[0]
+	if e.From != idify(domain.NodeService, "orders") || e.To != idify(domain.NodeService, "billing") {
+		t.Fatalf("edge = %s -> %s", e.From, e.To)
+	}
+	if _, ok := e.Attrs["endpoints"]; ok {
+		t.Fatalf("dependency edge must not take the call's endpoints, got %#v", e.Attrs)
+	}
+}
@@ -0,0 +1,482 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OpenAPI 3 / AsyncAPI 2-3 import: a stream of documents (YAML or JSON, separated by "---"),
+// one per service. OpenAPI paths become the service's endpoints and its servers name the
+// hosts it answers on. Operations or path items whose servers point at another service's
+// host become sync rest dependencies on that service, as do the entries of a root "x-calls"
+// list (service names or URLs, optionally with endpoints). AsyncAPI channels become topics
+// and their operations publish/subscribe dependencies of the document's service.
+
+var apiMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+type apiDoc struct {
+	OpenAPI  string `yaml:"openapi"`
+	AsyncAPI string `yaml:"asyncapi"`
+	Info     struct {
+		Title string `yaml:"title"`
+		// x-service names the service explicitly when the title or servers do not.
+		Service string `yaml:"x-service"`
+	} `yaml:"info"`
+	Service    string                          `yaml:"x-service"`
+	Servers    apiServers                      `yaml:"servers"`
+	Paths      map[string]map[string]yaml.Node `yaml:"paths"`
+	Channels   map[string]asyncChannel         `yaml:"channels"`
+	Operations map[string]asyncOperation       `yaml:"operations"`
+	Calls      []apiCallRef                    `yaml:"x-calls"`
+}
+
+type apiServer struct {
+	URL       string `yaml:"url"`
+	Host      string `yaml:"host"`     // AsyncAPI 3
+	Pathname  string `yaml:"pathname"` // AsyncAPI 3
+	Protocol  string `yaml:"protocol"`
+	Variables map[string]struct {
+		Default string `yaml:"default"`
+	} `yaml:"variables"`
+}
+
+var serverVar = regexp.MustCompile(`\{[^}]*\}`)
+
+// location resolves server variables to their defaults and returns host and base path.
+func (s apiServer) location() (string, string) {
+	raw := s.URL
+	if raw == "" && s.Host != "" {
+		raw = s.Host + s.Pathname
+	}
+	raw = serverVar.ReplaceAllStringFunc(raw, func(v string) string {
+		return s.Variables[strings.Trim(v, "{}")].Default
+	})
+	return urlHostPath(raw)
+}
+
+// urlHostPath accepts absolute URLs and scheme-less "host:port/path" references.
+func urlHostPath(raw string) (string, string) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || strings.HasPrefix(raw, "/") {
+		return "", raw
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "//" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", ""
+	}
+	return strings.ToLower(u.Hostname()), strings.TrimSuffix(u.Path, "/")
+}
+
+// apiServers accepts the OpenAPI list form and the AsyncAPI map (name -> server) form.
+type apiServers []apiServer
+
+func (a *apiServers) UnmarshalYAML(n *yaml.Node) error {
+	switch n.Kind {
+	case yaml.SequenceNode:
+		var s []apiServer
+		if err := n.Decode(&s); err != nil {
+			return err
+		}
+		*a = s
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			var s apiServer
+			if err := n.Content[i+1].Decode(&s); err != nil {
+				return err
+			}
+			*a = append(*a, s)
+		}
+	default:
+		return fmt.Errorf("line %d: servers must be a list or mapping", n.Line)
+	}
+	return nil
+}
+
+type asyncChannel struct {
+	Address   string    `yaml:"address"` // AsyncAPI 3; in 2.x the channel key is the address
+	Publish   *struct{} `yaml:"publish"`
+	Subscribe *struct{} `yaml:"subscribe"`
+}
+
+type asyncOperation struct {
+	Action  string `yaml:"action"`
+	Channel struct {
+		Ref string `yaml:"$ref"`
+	} `yaml:"channel"`
+}
+
+// apiCallRef is an x-calls entry: a service name or URL, or a mapping with endpoints.
+type apiCallRef struct {
+	Service   string   `yaml:"service"`
+	URL       string   `yaml:"url"`
+	Endpoints []string `yaml:"endpoints"`
+}
+
+func (c *apiCallRef) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		if strings.Contains(n.Value, "://") || strings.Contains(n.Value, "/") {
+			c.URL = n.Value
+		} else {
+			c.Service = n.Value
+		}
+		return nil
+	}
+	type plain apiCallRef
+	return n.Decode((*plain)(c))
+}
+
+type apiService struct {
+	name      string
+	basePaths []string
+	endpoints []apiEndpoint
+	calls     map[string]map[string]bool // target -> endpoints
+}
+
+type apiEndpoint struct {
+	method, path string
+}
+
+func (e apiEndpoint) String() string { return e.method + " " + e.path }
+
+var titleSuffix = regexp.MustCompile(`(?i)[\s_-]+(open|async)?(api|service|events?)$`)
+
+// serviceName prefers x-service, then the title without a trailing "API"/"Service"/"Events",
+// then (OpenAPI only, AsyncAPI servers are brokers) the first server host.
+func (d *apiDoc) serviceName() string {
+	for _, n := range []string{d.Info.Service, d.Service} {
+		if n = strings.TrimSpace(n); n != "" {
+			return n
+		}
+	}
+	t := strings.TrimSpace(d.Info.Title)
+	for titleSuffix.MatchString(t) {
+		t = titleSuffix.ReplaceAllString(t, "")
+	}
+	if t != "" {
+		return strings.Join(strings.Fields(strings.ToLower(t)), "-")
+	}
+	if d.OpenAPI != "" {
+		for _, s := range d.Servers {
+			if h, _ := s.location(); h != "" && !isLocalHost(h) {
+				return shortHost(h)
+			}
+		}
+	}
+	return ""
+}
+
+func isLocalHost(h string) bool {
+	return h == "localhost" || net.ParseIP(h) != nil
+}
+
+// shortHost is the first DNS label ("orders.prod.svc.cluster.local" -> "orders").
+func shortHost(h string) string {
+	first, _, _ := strings.Cut(h, ".")
+	return first
+}
+
+func ParseAPIDocs(path string) (*YSpec, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseAPIDocsBytes(b)
+}
+
+func decodeAPIDocs(b []byte) ([]*apiDoc, error) {
+	var docs []*apiDoc
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	for i := 1; ; i++ {
+		var d apiDoc
+		err := dec.Decode(&d)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("api documents: document %d: %w", i, err)
+		}
+		if d.OpenAPI == "" && d.AsyncAPI == "" {
+			if d.Info.Title == "" && len(d.Paths) == 0 && len(d.Channels) == 0 {
+				continue // empty document between separators
+			}
+			return nil, fmt.Errorf("api documents: document %d is neither OpenAPI (openapi: 3.x) nor AsyncAPI (asyncapi: 2.x/3.x)", i)
+		}
+		docs = append(docs, &d)
+	}
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("api documents: no OpenAPI or AsyncAPI documents found")
+	}
+	return docs, nil
+}
+
+// ParseAPIDocsBytes builds a YSpec from OpenAPI and AsyncAPI documents.
+func ParseAPIDocsBytes(b []byte) (*YSpec, error) {
+	docs, err := decodeAPIDocs(b)
+	if err != nil {
+		return nil, err
+	}
+
+	services := map[string]*apiService{}
+	var order []string
+	hosts := map[string]string{} // host or short host -> service
+	serviceOf := make([]*apiService, len(docs))
+	for i, d := range docs {
+		name := d.serviceName()
+		if name == "" {
+			return nil, fmt.Errorf("api documents: document %d has no title, servers or x-service to name its service", i+1)
+		}
+		svc := services[strings.ToLower(name)]
+		if svc == nil {
+			svc = &apiService{name: name, calls: map[string]map[string]bool{}}
+			services[strings.ToLower(name)] = svc
+			order = append(order, strings.ToLower(name))
+		}
+		serviceOf[i] = svc
+		hosts[strings.ToLower(name)] = svc.name
+		if d.OpenAPI == "" {
+			continue
+		}
+		for _, s := range d.Servers {
+			h, base := s.location()
+			if h != "" && !isLocalHost(h) {
+				hosts[h] = svc.name
+				hosts[shortHost(h)] = svc.name
+			}
+			if base != "" {
+				svc.basePaths = append(svc.basePaths, base)
+			}
+		}
+	}
+	resolve := func(host string) string {
+		if n, ok := hosts[host]; ok {
+			return n
+		}
+		return hosts[shortHost(host)]
+	}
+
+	external := map[string]bool{}
+	topics := map[string]bool{}
+	spec := &YSpec{}
+	deps := map[[3]string]bool{}
+	addDep := func(from, topic, kind string) {
+		topics[topic] = true
+		if k := [3]string{from, topic, kind}; !deps[k] {
+			deps[k] = true
+			spec.Dependencies = append(spec.Dependencies, YDependency{From: from, To: topic, Kind: kind})
+		}
+	}
+	addCall := func(from *apiService, to string, endpoints ...string) {
+		if strings.EqualFold(from.name, to) {
+			return
+		}
+		m := from.calls[to]
+		if m == nil {
+			m = map[string]bool{}
+			from.calls[to] = m
+		}
+		for _, e := range endpoints {
+			m[e] = true
+		}
+	}
+
+	for i, d := range docs {
+		svc := serviceOf[i]
+		protocol := "rest"
+		if d.AsyncAPI != "" {
+			protocol = "async"
+			for _, s := range d.Servers {
+				if s.Protocol != "" {
+					protocol = strings.ToLower(s.Protocol)
+					break
+				}
+			}
+		}
+		title := d.Info.Title
+		if title == "" {
+			title = svc.name
+		}
+		spec.APIs = append(spec.APIs, YAPI{Name: title, Protocol: protocol})
+
+		paths := make([]string, 0, len(d.Paths))
+		for p := range d.Paths {
+			paths = append(paths, p)
+		}
+		sort.Strings(paths)
+		for _, p := range paths {
+			item := d.Paths[p]
+			var itemServers apiServers
+			if n, ok := item["servers"]; ok {
+				if err := n.Decode(&itemServers); err != nil {
+					return nil, fmt.Errorf("api documents: %s %s: %w", svc.name, p, err)
+				}
+			}
+			for _, m := range apiMethods {
+				n, ok := item[m]
+				if !ok {
+					continue
+				}
+				var op struct {
+					Servers apiServers `yaml:"servers"`
+				}
+				if err := n.Decode(&op); err != nil {
+					return nil, fmt.Errorf("api documents: %s %s %s: %w", svc.name, strings.ToUpper(m), p, err)
+				}
+				ep := apiEndpoint{method: strings.ToUpper(m), path: p}
+				svc.endpoints = append(svc.endpoints, ep)
+				// Servers overridden per path/operation: the operation is served (proxied) elsewhere.
+				servers := op.Servers
+				if len(servers) == 0 {
+					servers = itemServers
+				}
+				for _, s := range servers {
+					if h, _ := s.location(); h != "" {
+						if to := resolve(h); to != "" {
+							addCall(svc, to, ep.String())
+						}
+					}
+				}
+			}
+		}
+
+		if d.AsyncAPI != "" {
+			addresses := map[string]string{}
+			keys := make([]string, 0, len(d.Channels))
+			for k, ch := range d.Channels {
+				keys = append(keys, k)
+				addresses[k] = k
+				if ch.Address != "" {
+					addresses[k] = ch.Address
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				topics[addresses[k]] = true
+			}
+			if strings.HasPrefix(d.AsyncAPI, "2") {
+				// AsyncAPI 2 describes operations from the client's side: "subscribe" means the
+				// application sends messages a client can subscribe to, "publish" that it receives them.
+				for _, k := range keys {
+					ch := d.Channels[k]
+					if ch.Subscribe != nil {
+						addDep(svc.name, addresses[k], "publish")
+					}
+					if ch.Publish != nil {
+						addDep(svc.name, addresses[k], "subscribe")
+					}
+				}
+			} else {
+				ops := make([]string, 0, len(d.Operations))
+				for k := range d.Operations {
+					ops = append(ops, k)
+				}
+				sort.Strings(ops)
+				for _, k := range ops {
+					op := d.Operations[k]
+					key := strings.TrimPrefix(op.Channel.Ref, "#/channels/")
+					addr, ok := addresses[key]
+					if !ok {
+						return nil, fmt.Errorf("api documents: %s operation %s: unknown channel %q", svc.name, k, op.Channel.Ref)
+					}
+					switch strings.ToLower(op.Action) {
+					case "send":
+						addDep(svc.name, addr, "publish")
+					case "receive":
+						addDep(svc.name, addr, "subscribe")
+					default:
+						return nil, fmt.Errorf("api documents: %s operation %s: action must be send or receive", svc.name, k)
+					}
+				}
+			}
+		}
+	}
+
+	// x-calls after every document is read, so targets may be declared later in the stream.
+	for i, d := range docs {
+		svc := serviceOf[i]
+		for _, c := range d.Calls {
+			to, path := c.Service, ""
+			if c.URL != "" {
+				var host string
+				host, path = urlHostPath(c.URL)
+				if to == "" {
+					to = resolve(host)
+				}
+				if to == "" && host != "" {
+					// Not described by any document: an external system.
+					to = host
+					external[host] = true
+				}
+			} else if n := resolve(strings.ToLower(to)); n != "" {
+				to = n
+			}
+			if to == "" {
+				return nil, fmt.Errorf("api documents: %s x-calls entry needs a service or url", svc.name)
+			}
+			endpoints := c.Endpoints
+			if len(endpoints) == 0 && path != "" {
+				if target := services[strings.ToLower(to)]; target != nil {
+					endpoints = target.endpointsUnder(path)
+				}
+			}
+			addCall(svc, to, endpoints...)
+		}
+	}
+
+	// Calls are emitted as sync rest dependencies (not services[].calls) so the spec stays in
+	// the dependencies style the event operations already use.
+	for _, k := range order {
+		svc := services[k]
+		spec.Services = append(spec.Services, YService{Name: svc.name, Type: "service"})
+		targets := make([]string, 0, len(svc.calls))
+		for to := range svc.calls {
+			targets = append(targets, to)
+		}
+		sort.Strings(targets)
+		for _, to := range targets {
+			dep := YDependency{From: svc.name, To: to, Kind: "rest", Sync: true}
+			if len(svc.calls[to]) > 0 {
+				dep.Endpoints = SortedSet(svc.calls[to])
+			}
+			spec.Dependencies = append(spec.Dependencies, dep)
+		}
+	}
+	for _, h := range SortedSet(external) {
+		if services[h] == nil {
+			spec.Services = append(spec.Services, YService{Name: h, Type: "external_system"})
+		}
+	}
+	for _, t := range SortedSet(topics) {
+		spec.Topics = append(spec.Topics, YTopic{Name: t})
+	}
+	spec.Metadata = map[string]any{"source": "api-docs", "documents": len(docs)}
+	return spec, nil
+}
+
+// endpointsUnder lists the endpoints whose path, below one of the service's base paths,
+// starts with the called URL path.
+func (s *apiService) endpointsUnder(path string) []string {
+	var out []string
+	for _, e := range s.endpoints {
+		for _, base := range append([]string{""}, s.basePaths...) {
+			full := base + e.path
+			if full == path || strings.HasPrefix(full, path+"/") {
+				out = append(out, e.String())
+				break
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,109 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+const apiDocsSample = `
+openapi: 3.0.3
+info:
+  title: Orders API
+servers:
+  - url: https://{env}.orders.internal/api
+    variables:
+      env:
+        default: prod
+paths:
+  /orders:
+    get: {}
+    post: {}
+  /orders/{id}:
+    get: {}
+x-calls:
+  - url: http://billing:8080/invoices
+  - https://api.stripe.com/v1/charges
+---
+openapi: 3.1.0
+info:
+  title: Billing Service
+servers:
+  - url: http://billing:8080
+paths:
+  /invoices:
+    post: {}
+  /invoices/{id}:
+    get: {}
+  /health:
+    get: {}
+---
+openapi: 3.0.0
+info:
+  title: Edge
+  x-service: edge
+paths:
+  /orders:
+    get:
+      servers:
+        - url: https://prod.orders.internal/api
+---
+asyncapi: 2.6.0
+info:
+  title: Orders Events
+servers:
+  kafka:
+    url: kafka:9092
+    protocol: kafka
+channels:
+  order.created:
+    subscribe: {}
+  payment.settled:
+    publish: {}
+---
+{"asyncapi": "3.0.0", "info": {"title": "Billing Events"},
+ "channels": {"settled": {"address": "payment.settled"}},
+ "operations": {"emitSettled": {"action": "send", "channel": {"$ref": "#/channels/settled"}}}}
+`
+
+func TestParseAPIDocsBytes(t *testing.T) {
+	s, err := ParseAPIDocsBytes([]byte(apiDocsSample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	svcs := map[string]YService{}
+	for _, svc := range s.Services {
+		svcs[svc.Name] = svc
+	}
+	if len(svcs) != 4 || svcs["api.stripe.com"].Type != "external_system" {
+		t.Fatalf("expected orders, billing, edge and external stripe, got %+v", s.Services)
+	}
+
+	if !reflect.DeepEqual(s.Topics, []YTopic{{Name: "order.created"}, {Name: "payment.settled"}}) {
+		t.Fatalf("topics = %+v", s.Topics)
+	}
+	want := []YDependency{
+		{From: "orders", To: "order.created", Kind: "publish"},
+		{From: "orders", To: "payment.settled", Kind: "subscribe"},
+		{From: "billing", To: "payment.settled", Kind: "publish"},
+		{From: "orders", To: "api.stripe.com", Kind: "rest", Sync: true},
+		{From: "orders", To: "billing", Kind: "rest", Sync: true, Endpoints: []string{"GET /invoices/{id}", "POST /invoices"}},
+		// edge proxies to orders via the operation's server.
+		{From: "edge", To: "orders", Kind: "rest", Sync: true, Endpoints: []string{"GET /orders"}},
+	}
+	if !reflect.DeepEqual(s.Dependencies, want) {
+		t.Fatalf("dependencies = %+v", s.Dependencies)
+	}
+	if len(s.APIs) != 5 || s.APIs[3].Protocol != "kafka" || s.APIs[0].Protocol != "rest" {
+		t.Fatalf("apis = %+v", s.APIs)
+	}
+}
+
+func TestParseAPIDocsBytes_RejectsUnknownDocuments(t *testing.T) {
+	if _, err := ParseAPIDocsBytes([]byte("swagger: \"2.0\"\ninfo: {title: Old}\n")); err == nil {
+		t.Fatal("expected an error for a Swagger 2 document")
+	}
+	bad := "asyncapi: 3.0.0\ninfo: {title: X}\noperations:\n  op: {action: send, channel: {$ref: '#/channels/missing'}}\n"
+	if _, err := ParseAPIDocsBytes([]byte(bad)); err == nil {
+		t.Fatal("expected an error for an operation on an unknown channel")
+	}
+}
@@ -0,0 +1,196 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// docker-compose import: compose services become YSpec services, well-known database images
+// become datastores and message brokers become topics. Dependencies come from depends_on, links
+// and environment values that mention another service's hostname.
+
+type composeFile struct {
+	Services map[string]composeService `yaml:"services"`
+}
+
+type composeService struct {
+	Image         string      `yaml:"image"`
+	Hostname      string      `yaml:"hostname"`
+	ContainerName string      `yaml:"container_name"`
+	DependsOn     composeList `yaml:"depends_on"`
+	Links         []string    `yaml:"links"`
+	Environment   composeMap  `yaml:"environment"`
+	Labels        composeMap  `yaml:"labels"`
+	Networks      composeNets `yaml:"networks"`
+}
+
+// composeList accepts a list of names or a mapping keyed by name (depends_on long syntax).
+type composeList []string
+
+func (l *composeList) UnmarshalYAML(n *yaml.Node) error {
+	switch n.Kind {
+	case yaml.SequenceNode:
+		var s []string
+		if err := n.Decode(&s); err != nil {
+			return err
+		}
+		*l = s
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			*l = append(*l, n.Content[i].Value)
+		}
+	default:
+		return fmt.Errorf("line %d: expected a list or mapping", n.Line)
+	}
+	return nil
+}
+
+// composeMap accepts a KEY: value mapping or a list of KEY=value strings.
+type composeMap map[string]string
+
+func (m *composeMap) UnmarshalYAML(n *yaml.Node) error {
+	out := composeMap{}
+	switch n.Kind {
+	case yaml.SequenceNode:
+		for _, c := range n.Content {
+			k, v, _ := strings.Cut(c.Value, "=")
+			out[k] = v
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			out[n.Content[i].Value] = n.Content[i+1].Value
+		}
+	default:
+		return fmt.Errorf("line %d: expected a list or mapping", n.Line)
+	}
+	*m = out
+	return nil
+}
+
+// composeNets collects network aliases from the list or mapping form of networks.
+type composeNets []string
+
+func (a *composeNets) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		var net struct {
+			Aliases []string `yaml:"aliases"`
+		}
+		if n.Content[i+1].Kind == yaml.MappingNode {
+			if err := n.Content[i+1].Decode(&net); err != nil {
+				return err
+			}
+		}
+		*a = append(*a, net.Aliases...)
+	}
+	return nil
+}
+
+var hostToken = regexp.MustCompile(`[A-Za-z0-9][A-Za-z0-9._-]*`)
+
+func ParseCompose(path string) (*YSpec, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseComposeBytes(b)
+}
+
+// ParseComposeBytes converts a docker-compose file into a YSpec for mapper.ToGraph.
+func ParseComposeBytes(b []byte) (*YSpec, error) {
+	var cf composeFile
+	if err := yaml.Unmarshal(b, &cf); err != nil {
+		return nil, fmt.Errorf("docker-compose: %w", err)
+	}
+	if len(cf.Services) == 0 {
+		return nil, fmt.Errorf("docker-compose: no services defined")
+	}
+
+	names := make([]string, 0, len(cf.Services))
+	for name := range cf.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	roles := map[string]workloadRole{}
+	hosts := map[string]string{} // hostname/alias (lower) -> compose service name
+	s := &YSpec{Metadata: map[string]any{"source": "docker-compose"}}
+	for _, name := range names {
+		svc := cf.Services[name]
+		role, typ := classifyImage(svc.Image)
+		roles[name] = role
+		if role == roleInfra {
+			continue
+		}
+		for _, h := range append([]string{name, svc.Hostname, svc.ContainerName}, svc.Networks...) {
+			if h = strings.ToLower(strings.TrimSpace(h)); h != "" {
+				hosts[h] = name
+			}
+		}
+		switch role {
+		case roleDatastore:
+			s.Datastores = append(s.Datastores, YDatastore{Name: name, Type: typ})
+		case roleBroker:
+			s.Topics = append(s.Topics, YTopic{Name: name})
+		default:
+			ys := YService{Name: name, Type: "service"}
+			if role == roleGateway {
+				ys.Type = "api_gateway"
+			}
+			if len(svc.Labels) > 0 {
+				ys.Labels = map[string]string(svc.Labels)
+			}
+			s.Services = append(s.Services, ys)
+		}
+	}
+
+	seen := map[[2]string]bool{}
+	addDep := func(from, to, hint string) {
+		if from == to || roles[to] == roleInfra || seen[[2]string{from, to}] {
+			return
+		}
+		seen[[2]string{from, to}] = true
+		s.Dependencies = append(s.Dependencies, newDependency(from, to, roles[to], hint))
+	}
+
+	for _, from := range names {
+		// Datastores and brokers only point at infrastructure (replicas, zookeeper); skip them.
+		if r := roles[from]; r == roleDatastore || r == roleBroker || r == roleInfra {
+			continue
+		}
+		svc := cf.Services[from]
+		// Environment first: it tells publish from subscribe and REST from gRPC.
+		keys := make([]string, 0, len(svc.Environment))
+		for k := range svc.Environment {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			v := svc.Environment[k]
+			for _, tok := range hostToken.FindAllString(v, -1) {
+				if to, ok := hosts[strings.ToLower(tok)]; ok {
+					addDep(from, to, k+" "+v)
+				}
+			}
+		}
+		for _, to := range svc.DependsOn {
+			if _, ok := cf.Services[to]; ok {
+				addDep(from, to, "")
+			}
+		}
+		for _, l := range svc.Links {
+			to, _, _ := strings.Cut(l, ":")
+			if _, ok := cf.Services[to]; ok {
+				addDep(from, to, "")
+			}
+		}
+	}
+	return s, nil
+}
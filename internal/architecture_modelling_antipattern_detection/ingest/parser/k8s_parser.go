@@ -0,0 +1,388 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kubernetes import: Deployments, StatefulSets and DaemonSets become services (or datastores and
+// topics for well-known images), Ingresses become gateways, and environment values (direct or
+// from ConfigMaps) that name a Service's *.svc.cluster.local host become dependencies.
+
+type k8sObject struct {
+	Kind     string `yaml:"kind"`
+	Metadata struct {
+		Name      string            `yaml:"name"`
+		Namespace string            `yaml:"namespace"`
+		Labels    map[string]string `yaml:"labels"`
+	} `yaml:"metadata"`
+	Spec  yaml.Node         `yaml:"spec"`
+	Data  map[string]string `yaml:"data"`
+	Items []yaml.Node       `yaml:"items"`
+}
+
+func (o *k8sObject) namespace() string {
+	if o.Metadata.Namespace == "" {
+		return "default"
+	}
+	return o.Metadata.Namespace
+}
+
+type k8sWorkloadSpec struct {
+	Replicas *int `yaml:"replicas"`
+	Template struct {
+		Metadata struct {
+			Labels map[string]string `yaml:"labels"`
+		} `yaml:"metadata"`
+		Spec struct {
+			Containers []k8sContainer `yaml:"containers"`
+		} `yaml:"spec"`
+	} `yaml:"template"`
+}
+
+type k8sContainer struct {
+	Image string `yaml:"image"`
+	Env   []struct {
+		Name      string `yaml:"name"`
+		Value     string `yaml:"value"`
+		ValueFrom struct {
+			ConfigMapKeyRef struct {
+				Name string `yaml:"name"`
+				Key  string `yaml:"key"`
+			} `yaml:"configMapKeyRef"`
+		} `yaml:"valueFrom"`
+	} `yaml:"env"`
+	EnvFrom []struct {
+		ConfigMapRef struct {
+			Name string `yaml:"name"`
+		} `yaml:"configMapRef"`
+	} `yaml:"envFrom"`
+	Resources struct {
+		Requests map[string]string `yaml:"requests"`
+	} `yaml:"resources"`
+}
+
+type k8sServiceSpec struct {
+	Selector map[string]string `yaml:"selector"`
+}
+
+// k8sBackend covers networking.k8s.io/v1 (service.name) and the older serviceName field.
+type k8sBackend struct {
+	Service struct {
+		Name string `yaml:"name"`
+	} `yaml:"service"`
+	ServiceName string `yaml:"serviceName"`
+}
+
+func (b k8sBackend) name() string {
+	if b.Service.Name != "" {
+		return b.Service.Name
+	}
+	return b.ServiceName
+}
+
+type k8sIngressSpec struct {
+	DefaultBackend *k8sBackend `yaml:"defaultBackend"`
+	Backend        *k8sBackend `yaml:"backend"`
+	Rules          []struct {
+		HTTP struct {
+			Paths []struct {
+				Backend k8sBackend `yaml:"backend"`
+			} `yaml:"paths"`
+		} `yaml:"http"`
+	} `yaml:"rules"`
+}
+
+type k8sWorkload struct {
+	obj      *k8sObject
+	spec     k8sWorkloadSpec
+	name     string
+	role     workloadRole
+	typ      string
+	replicas int
+	res      *YResources
+}
+
+// k8sHostRef matches in-cluster DNS names: svc.ns.svc.cluster.local, svc.ns.svc and svc.ns.svc.<domain>.
+var k8sHostRef = regexp.MustCompile(`(?i)\b([a-z0-9]([-a-z0-9]*[a-z0-9])?)\.([a-z0-9]([-a-z0-9]*[a-z0-9])?)\.svc\b`)
+
+// k8sURLHost matches a short Service name used as a URL host or host:port ("http://orders", "orders:8080").
+var k8sURLHost = regexp.MustCompile(`(?i)(?:^|[/@,\s])([a-z0-9]([-a-z0-9]*[a-z0-9])?)(?::\d+|/|$)`)
+
+func ParseK8s(path string) (*YSpec, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseK8sBytes(b)
+}
+
+// decodeK8sObjects reads every document of a manifest bundle, flattening kind: List.
+func decodeK8sObjects(b []byte) ([]*k8sObject, error) {
+	var out []*k8sObject
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	for {
+		var o k8sObject
+		err := dec.Decode(&o)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("kubernetes: %w", err)
+		}
+		if o.Kind == "List" {
+			for i := range o.Items {
+				var item k8sObject
+				if err := o.Items[i].Decode(&item); err != nil {
+					return nil, fmt.Errorf("kubernetes: %w", err)
+				}
+				out = append(out, &item)
+			}
+			continue
+		}
+		if o.Kind != "" {
+			out = append(out, &o)
+		}
+	}
+	return out, nil
+}
+
+// ParseK8sBytes converts a multi-document Kubernetes manifest bundle into a YSpec for mapper.ToGraph.
+func ParseK8sBytes(b []byte) (*YSpec, error) {
+	objs, err := decodeK8sObjects(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var workloads []*k8sWorkload
+	var services, ingresses []*k8sObject
+	configMaps := map[string]map[string]string{} // ns/name -> data
+	nameCount := map[string]int{}
+	for _, o := range objs {
+		switch o.Kind {
+		case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet":
+			w := &k8sWorkload{obj: o}
+			if err := o.Spec.Decode(&w.spec); err != nil {
+				return nil, fmt.Errorf("kubernetes: %s %s: %w", o.Kind, o.Metadata.Name, err)
+			}
+			workloads = append(workloads, w)
+			nameCount[o.Metadata.Name]++
+		case "Service":
+			services = append(services, o)
+		case "Ingress":
+			ingresses = append(ingresses, o)
+			nameCount[o.Metadata.Name]++
+		case "ConfigMap":
+			configMaps[o.namespace()+"/"+o.Metadata.Name] = o.Data
+		}
+	}
+	if len(workloads) == 0 {
+		return nil, fmt.Errorf("kubernetes: no Deployments, StatefulSets or DaemonSets found")
+	}
+	// Names are the object names, qualified with the namespace only when they clash.
+	nodeName := func(o *k8sObject) string {
+		if nameCount[o.Metadata.Name] > 1 {
+			return o.Metadata.Name + "." + o.namespace()
+		}
+		return o.Metadata.Name
+	}
+
+	s := &YSpec{Metadata: map[string]any{"source": "kubernetes"}}
+	roles := map[string]workloadRole{}
+	for _, w := range workloads {
+		w.name = nodeName(w.obj)
+		w.replicas = 1
+		if w.spec.Replicas != nil {
+			w.replicas = *w.spec.Replicas
+		}
+		w.res = &YResources{}
+		// The first container is the workload; later ones are usually sidecars (proxies, agents).
+		if cs := w.spec.Template.Spec.Containers; len(cs) > 0 {
+			w.role, w.typ = classifyImage(cs[0].Image)
+		}
+		for _, c := range w.spec.Template.Spec.Containers {
+			w.res.CPUCores += parseCPU(c.Resources.Requests["cpu"])
+			w.res.MemoryMB += parseMemoryMB(c.Resources.Requests["memory"])
+		}
+		if w.res.CPUCores == 0 && w.res.MemoryMB == 0 {
+			w.res = nil
+		}
+		// Only StatefulSets are treated as datastores; a Deployment running e.g. redis is a cache
+		// the team owns like any other service.
+		if w.role == roleDatastore && w.obj.Kind != "StatefulSet" {
+			w.role, w.typ = roleService, ""
+		}
+		roles[w.name] = w.role
+		switch w.role {
+		case roleInfra:
+		case roleDatastore:
+			s.Datastores = append(s.Datastores, YDatastore{Name: w.name, Type: w.typ, Replicas: w.replicas, Resources: w.res})
+		case roleBroker:
+			s.Topics = append(s.Topics, YTopic{Name: w.name})
+		default:
+			ys := YService{Name: w.name, Type: "service", Replicas: w.replicas, Resources: w.res}
+			if w.role == roleGateway {
+				ys.Type = "api_gateway"
+			}
+			if len(w.obj.Metadata.Labels) > 0 {
+				ys.Labels = w.obj.Metadata.Labels
+			}
+			s.Services = append(s.Services, ys)
+		}
+	}
+
+	// A Service routes to the workloads in its namespace whose pod labels match its selector.
+	backends := map[string][]string{} // "name.ns" -> workload node names
+	for _, svc := range services {
+		var spec k8sServiceSpec
+		if err := svc.Spec.Decode(&spec); err != nil {
+			return nil, fmt.Errorf("kubernetes: Service %s: %w", svc.Metadata.Name, err)
+		}
+		key := strings.ToLower(svc.Metadata.Name + "." + svc.namespace())
+		for _, w := range workloads {
+			if w.obj.namespace() == svc.namespace() && selects(spec.Selector, w.spec.Template.Metadata.Labels) {
+				backends[key] = append(backends[key], w.name)
+			}
+		}
+	}
+
+	seen := map[[2]string]bool{}
+	addDep := func(from, to, hint string) {
+		if from == to || roles[to] == roleInfra || seen[[2]string{from, to}] {
+			return
+		}
+		seen[[2]string{from, to}] = true
+		s.Dependencies = append(s.Dependencies, newDependency(from, to, roles[to], hint))
+	}
+
+	for _, ing := range ingresses {
+		var spec k8sIngressSpec
+		if err := ing.Spec.Decode(&spec); err != nil {
+			return nil, fmt.Errorf("kubernetes: Ingress %s: %w", ing.Metadata.Name, err)
+		}
+		name := nodeName(ing)
+		s.Services = append(s.Services, YService{Name: name, Type: "api_gateway", Labels: ing.Metadata.Labels})
+		var targets []string
+		for _, b := range []*k8sBackend{spec.DefaultBackend, spec.Backend} {
+			if b != nil && b.name() != "" {
+				targets = append(targets, b.name())
+			}
+		}
+		for _, r := range spec.Rules {
+			for _, p := range r.HTTP.Paths {
+				if p.Backend.name() != "" {
+					targets = append(targets, p.Backend.name())
+				}
+			}
+		}
+		for _, t := range targets {
+			for _, to := range backends[strings.ToLower(t+"."+ing.namespace())] {
+				addDep(name, to, "")
+			}
+		}
+	}
+
+	for _, w := range workloads {
+		if w.role != roleService && w.role != roleGateway {
+			continue
+		}
+		ns := w.obj.namespace()
+		for _, e := range workloadEnv(w, configMaps) {
+			for _, key := range k8sReferences(e[1], ns) {
+				for _, to := range backends[key] {
+					addDep(w.name, to, e[0]+" "+e[1])
+				}
+			}
+		}
+	}
+	return s, nil
+}
+
+// selects reports whether every selector label is present on the pod.
+func selects(selector, labels map[string]string) bool {
+	if len(selector) == 0 {
+		return false
+	}
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// workloadEnv returns the containers' environment as sorted (name, value) pairs, resolving
+// configMapKeyRef values and envFrom ConfigMaps in the workload's namespace.
+func workloadEnv(w *k8sWorkload, configMaps map[string]map[string]string) [][2]string {
+	ns := w.obj.namespace()
+	var out [][2]string
+	for _, c := range w.spec.Template.Spec.Containers {
+		for _, ref := range c.EnvFrom {
+			for k, v := range configMaps[ns+"/"+ref.ConfigMapRef.Name] {
+				out = append(out, [2]string{k, v})
+			}
+		}
+		for _, e := range c.Env {
+			v := e.Value
+			if ref := e.ValueFrom.ConfigMapKeyRef; ref.Name != "" {
+				v = configMaps[ns+"/"+ref.Name][ref.Key]
+			}
+			out = append(out, [2]string{e.Name, v})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i][0] < out[j][0] })
+	return out
+}
+
+// k8sReferences returns the "service.namespace" keys a value refers to: fully-qualified
+// *.svc names anywhere, and short names (same namespace) used as a URL host or host:port.
+func k8sReferences(v, ns string) []string {
+	var out []string
+	for _, m := range k8sHostRef.FindAllStringSubmatch(v, -1) {
+		out = append(out, strings.ToLower(m[1]+"."+m[3]))
+	}
+	for _, m := range k8sURLHost.FindAllStringSubmatch(v, -1) {
+		out = append(out, strings.ToLower(m[1]+"."+ns))
+	}
+	return out
+}
+
+// parseCPU converts a Kubernetes CPU quantity ("500m", "2") to cores.
+func parseCPU(q string) float64 {
+	q = strings.TrimSpace(q)
+	if strings.HasSuffix(q, "m") {
+		v, _ := strconv.ParseFloat(strings.TrimSuffix(q, "m"), 64)
+		return v / 1000
+	}
+	v, _ := strconv.ParseFloat(q, 64)
+	return v
+}
+
+// parseMemoryMB converts a Kubernetes memory quantity ("512Mi", "1Gi", "500M", bytes) to MiB.
+func parseMemoryMB(q string) float64 {
+	q = strings.TrimSpace(q)
+	units := []struct {
+		suffix string
+		bytes  float64
+	}{
+		{"Ki", 1 << 10}, {"Mi", 1 << 20}, {"Gi", 1 << 30}, {"Ti", 1 << 40},
+		{"k", 1e3}, {"M", 1e6}, {"G", 1e9}, {"T", 1e12},
+	}
+	for _, u := range units {
+		if strings.HasSuffix(q, u.suffix) {
+			v, _ := strconv.ParseFloat(strings.TrimSuffix(q, u.suffix), 64)
+			return v * u.bytes / (1 << 20)
+		}
+	}
+	v, _ := strconv.ParseFloat(q, 64)
+	return v / (1 << 20)
+}
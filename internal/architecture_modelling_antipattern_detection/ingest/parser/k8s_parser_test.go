@@ -0,0 +1,177 @@
+package parser
+
+import (
+	"testing"
+)
+
+const k8sSample = `
+apiVersion: apps/v1
+kind: Deployment
+metadata: {name: orders, namespace: shop, labels: {team: checkout}}
+spec:
+  replicas: 3
+  selector: {matchLabels: {app: orders}}
+  template:
+    metadata: {labels: {app: orders}}
+    spec:
+      containers:
+        - name: orders
+          image: registry.local/orders:1.4
+          envFrom:
+            - configMapRef: {name: orders-config}
+          env:
+            - name: BILLING_URL
+              value: http://billing:8080/api
+            - name: KAFKA_BOOTSTRAP
+              valueFrom:
+                configMapKeyRef: {name: shared, key: kafka}
+          resources:
+            requests: {cpu: 500m, memory: 256Mi}
+        - name: proxy
+          image: envoyproxy/envoy:v1.29
+          resources:
+            requests: {cpu: 100m, memory: 64Mi}
+---
+apiVersion: v1
+kind: ConfigMap
+metadata: {name: orders-config, namespace: shop}
+data:
+  DATABASE_URL: postgres://app@orders-db.shop.svc.cluster.local:5432/orders
+---
+apiVersion: v1
+kind: ConfigMap
+metadata: {name: shared, namespace: shop}
+data:
+  kafka: events.infra.svc.cluster.local:9092
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata: {name: billing, namespace: shop}
+spec:
+  template:
+    metadata: {labels: {app: billing}}
+    spec:
+      containers:
+        - image: registry.local/billing:2
+          env:
+            - name: KAFKA_CONSUMER_SERVERS
+              value: events.infra.svc:9092
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata: {name: orders-db, namespace: shop}
+spec:
+  replicas: 2
+  template:
+    metadata: {labels: {app: orders-db}}
+    spec:
+      containers:
+        - image: postgres:16
+          resources:
+            requests: {cpu: "1", memory: 2Gi}
+---
+apiVersion: v1
+kind: List
+items:
+  - apiVersion: apps/v1
+    kind: StatefulSet
+    metadata: {name: kafka, namespace: infra}
+    spec:
+      template:
+        metadata: {labels: {app: kafka}}
+        spec:
+          containers:
+            - image: bitnami/kafka:3.6
+  - apiVersion: v1
+    kind: Service
+    metadata: {name: events, namespace: infra}
+    spec:
+      selector: {app: kafka}
+---
+apiVersion: v1
+kind: Service
+metadata: {name: orders, namespace: shop}
+spec: {selector: {app: orders}}
+---
+apiVersion: v1
+kind: Service
+metadata: {name: billing, namespace: shop}
+spec: {selector: {app: billing}}
+---
+apiVersion: v1
+kind: Service
+metadata: {name: orders-db, namespace: shop}
+spec: {selector: {app: orders-db}}
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata: {name: shop-ingress, namespace: shop}
+spec:
+  rules:
+    - http:
+        paths:
+          - path: /orders
+            backend:
+              service: {name: orders, port: {number: 80}}
+`
+
+func TestParseK8sBytes(t *testing.T) {
+	s, err := ParseK8sBytes([]byte(k8sSample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	svcs := map[string]YService{}
+	for _, svc := range s.Services {
+		svcs[svc.Name] = svc
+	}
+	if len(svcs) != 3 || svcs["shop-ingress"].Type != "api_gateway" || svcs["orders"].Type != "service" {
+		t.Fatalf("services: %+v", s.Services)
+	}
+	o := svcs["orders"]
+	if o.Replicas != 3 || o.Resources == nil || o.Resources.CPUCores != 0.6 || o.Resources.MemoryMB != 320 || o.Labels["team"] != "checkout" {
+		t.Fatalf("orders deployment info: %+v %+v", o, o.Resources)
+	}
+	if svcs["billing"].Replicas != 1 || svcs["billing"].Resources != nil {
+		t.Fatalf("billing defaults: %+v", svcs["billing"])
+	}
+	if len(s.Datastores) != 1 || s.Datastores[0].Name != "orders-db" || s.Datastores[0].Type != "postgres" ||
+		s.Datastores[0].Replicas != 2 || s.Datastores[0].Resources.MemoryMB != 2048 {
+		t.Fatalf("datastores: %+v", s.Datastores)
+	}
+	if len(s.Topics) != 1 || s.Topics[0].Name != "kafka" {
+		t.Fatalf("topics: %+v", s.Topics)
+	}
+
+	deps := map[[2]string]string{}
+	for _, d := range s.Dependencies {
+		deps[[2]string{d.From, d.To}] = d.Kind
+	}
+	want := map[[2]string]string{
+		{"shop-ingress", "orders"}: "rest",
+		{"orders", "billing"}:      "rest",
+		{"orders", "orders-db"}:    "db",
+		{"orders", "kafka"}:        "publish",
+		{"billing", "kafka"}:       "subscribe",
+	}
+	if len(deps) != len(want) {
+		t.Fatalf("dependencies: %+v", s.Dependencies)
+	}
+	for k, v := range want {
+		if deps[k] != v {
+			t.Fatalf("dependency %v = %q, want %q (all: %+v)", k, deps[k], v, s.Dependencies)
+		}
+	}
+}
+
+func TestParseK8sQuantities(t *testing.T) {
+	for q, want := range map[string]float64{"250m": 0.25, "2": 2, "1.5": 1.5, "": 0} {
+		if got := parseCPU(q); got != want {
+			t.Fatalf("parseCPU(%q) = %v, want %v", q, got, want)
+		}
+	}
+	for q, want := range map[string]float64{"512Mi": 512, "1Gi": 1024, "1048576": 1, "1024Ki": 1} {
+		if got := parseMemoryMB(q); got != want {
+			t.Fatalf("parseMemoryMB(%q) = %v, want %v", q, got, want)
+		}
+	}
+}
@@ -0,0 +1,367 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OTLP/JSON trace import: spans are grouped by resource service.name; a span whose parent runs
+// in another service is one call parent → child. Producer/consumer spans with a messaging
+// destination become topic publish/subscribe edges, client spans with db.system become
+// datastore edges and other client spans without a traced callee become calls to the peer.
+// Call counts over the trace window give rate_per_min; server span routes give endpoints.
+
+// OTLP span kinds (proto enum values).
+const (
+	otlpKindInternal = 1
+	otlpKindServer   = 2
+	otlpKindClient   = 3
+	otlpKindProducer = 4
+	otlpKindConsumer = 5
+)
+
+var otlpKindNames = map[string]int{
+	"SPAN_KIND_INTERNAL": otlpKindInternal,
+	"SPAN_KIND_SERVER":   otlpKindServer,
+	"SPAN_KIND_CLIENT":   otlpKindClient,
+	"SPAN_KIND_PRODUCER": otlpKindProducer,
+	"SPAN_KIND_CONSUMER": otlpKindConsumer,
+}
+
+type otlpExport struct {
+	ResourceSpans []struct {
+		Resource struct {
+			Attributes []otlpKeyValue `json:"attributes"`
+		} `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+		// instrumentationLibrarySpans is the pre-1.0 name of scopeSpans.
+		InstrumentationLibrarySpans []otlpScopeSpans `json:"instrumentationLibrarySpans"`
+	} `json:"resourceSpans"`
+}
+
+type otlpScopeSpans struct {
+	Spans []struct {
+		TraceID      string         `json:"traceId"`
+		SpanID       string         `json:"spanId"`
+		ParentSpanID string         `json:"parentSpanId"`
+		Name         string         `json:"name"`
+		Kind         otlpSpanKind   `json:"kind"`
+		Start        otlpUint64     `json:"startTimeUnixNano"`
+		End          otlpUint64     `json:"endTimeUnixNano"`
+		Attributes   []otlpKeyValue `json:"attributes"`
+	} `json:"spans"`
+}
+
+type otlpKeyValue struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue *string         `json:"stringValue"`
+		IntValue    json.RawMessage `json:"intValue"`
+		DoubleValue *float64        `json:"doubleValue"`
+		BoolValue   *bool           `json:"boolValue"`
+	} `json:"value"`
+}
+
+func (kv otlpKeyValue) str() string {
+	v := kv.Value
+	switch {
+	case v.StringValue != nil:
+		return *v.StringValue
+	case len(v.IntValue) > 0:
+		return strings.Trim(string(v.IntValue), `"`)
+	case v.DoubleValue != nil:
+		return strconv.FormatFloat(*v.DoubleValue, 'f', -1, 64)
+	case v.BoolValue != nil:
+		return strconv.FormatBool(*v.BoolValue)
+	}
+	return ""
+}
+
+// otlpSpanKind accepts the enum as a number or its name.
+type otlpSpanKind int
+
+func (k *otlpSpanKind) UnmarshalJSON(b []byte) error {
+	var name string
+	if json.Unmarshal(b, &name) == nil {
+		*k = otlpSpanKind(otlpKindNames[name])
+		return nil
+	}
+	var n int
+	if err := json.Unmarshal(b, &n); err != nil {
+		return err
+	}
+	*k = otlpSpanKind(n)
+	return nil
+}
+
+// otlpUint64 accepts a 64-bit integer encoded as a JSON string (OTLP/JSON) or number.
+type otlpUint64 uint64
+
+func (u *otlpUint64) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "" || s == "null" {
+		return nil
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	*u = otlpUint64(v)
+	return nil
+}
+
+type otlpSpan struct {
+	service string
+	kind    int
+	name    string
+	parent  string // trace/span key of the parent, or ""
+	attrs   map[string]string
+	start   uint64
+	end     uint64
+}
+
+func (s *otlpSpan) attr(keys ...string) string {
+	for _, k := range keys {
+		if v := strings.TrimSpace(s.attrs[k]); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// endpoint names the operation a server span handles: "GET /orders/{id}", an RPC method or the span name.
+func (s *otlpSpan) endpoint() string {
+	if route := s.attr("http.route", "url.path", "http.target"); route != "" {
+		if m := s.attr("http.request.method", "http.method"); m != "" {
+			return m + " " + route
+		}
+		return route
+	}
+	if m := s.attr("rpc.method"); m != "" {
+		if svc := s.attr("rpc.service"); svc != "" {
+			return svc + "/" + m
+		}
+		return m
+	}
+	return s.name
+}
+
+func (s *otlpSpan) destination() string {
+	return s.attr("messaging.destination.name", "messaging.destination")
+}
+
+func (s *otlpSpan) depKind() string {
+	if strings.EqualFold(s.attr("rpc.system"), "grpc") {
+		return "grpc"
+	}
+	return "rest"
+}
+
+// otlpEdge aggregates observed calls for one dependency.
+type otlpEdge struct {
+	dep       YDependency
+	count     int
+	endpoints map[string]bool
+}
+
+func ParseOTLP(path string) (*YSpec, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseOTLPBytes(b)
+}
+
+// decodeOTLPSpans reads one OTLP/JSON export or a stream of them (the collector file exporter
+// writes one per line).
+func decodeOTLPSpans(b []byte) (map[string]*otlpSpan, []string, error) {
+	spans := map[string]*otlpSpan{}
+	var order []string
+	dec := json.NewDecoder(bytes.NewReader(b))
+	for {
+		var exp otlpExport
+		err := dec.Decode(&exp)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("otlp: %w", err)
+		}
+		for _, rs := range exp.ResourceSpans {
+			service := "unknown_service"
+			for _, kv := range rs.Resource.Attributes {
+				if kv.Key == "service.name" && kv.str() != "" {
+					service = kv.str()
+				}
+			}
+			for _, ss := range append(rs.ScopeSpans, rs.InstrumentationLibrarySpans...) {
+				for _, sp := range ss.Spans {
+					s := &otlpSpan{
+						service: service,
+						kind:    int(sp.Kind),
+						name:    sp.Name,
+						attrs:   make(map[string]string, len(sp.Attributes)),
+						start:   uint64(sp.Start),
+						end:     uint64(sp.End),
+					}
+					if sp.ParentSpanID != "" {
+						s.parent = sp.TraceID + "/" + sp.ParentSpanID
+					}
+					for _, kv := range sp.Attributes {
+						s.attrs[kv.Key] = kv.str()
+					}
+					key := sp.TraceID + "/" + sp.SpanID
+					if _, dup := spans[key]; !dup {
+						order = append(order, key)
+					}
+					spans[key] = s
+				}
+			}
+		}
+	}
+	if len(spans) == 0 {
+		return nil, nil, fmt.Errorf("otlp: no spans found")
+	}
+	return spans, order, nil
+}
+
+// ParseOTLPBytes builds a YSpec from OTLP/JSON trace exports.
+func ParseOTLPBytes(b []byte) (*YSpec, error) {
+	spans, order, err := decodeOTLPSpans(b)
+	if err != nil {
+		return nil, err
+	}
+
+	services := map[string]bool{}
+	datastores := map[string]string{} // name -> db.system
+	topics := map[string]bool{}
+	peers := map[string]bool{}
+	edges := map[[2]string]*otlpEdge{}
+	var edgeOrder [][2]string
+	add := func(dep YDependency, endpoint string) {
+		k := [2]string{dep.From, dep.To}
+		e := edges[k]
+		if e == nil {
+			e = &otlpEdge{dep: dep, endpoints: map[string]bool{}}
+			edges[k] = e
+			edgeOrder = append(edgeOrder, k)
+		}
+		e.count++
+		if endpoint != "" {
+			e.endpoints[endpoint] = true
+		}
+	}
+
+	var minStart, maxEnd uint64 = math.MaxUint64, 0
+	hasRemoteChild := map[string]bool{}
+	traces := map[string]bool{}
+	for _, key := range order {
+		s := spans[key]
+		services[s.service] = true
+		traces[key[:strings.Index(key, "/")]] = true
+		if s.start > 0 && s.start < minStart {
+			minStart = s.start
+		}
+		if s.end > maxEnd {
+			maxEnd = s.end
+		}
+		if p := spans[s.parent]; p != nil && p.service != s.service {
+			hasRemoteChild[s.parent] = true
+		}
+	}
+
+	for _, key := range order {
+		s := spans[key]
+		p := spans[s.parent]
+		remoteParent := p != nil && p.service != s.service
+		messaging := s.attr("messaging.system") != ""
+		switch {
+		case (s.kind == otlpKindProducer || (messaging && s.kind == otlpKindClient)) && s.destination() != "":
+			topics[s.destination()] = true
+			add(YDependency{From: s.service, To: s.destination(), Kind: "publish"}, "")
+		case s.kind == otlpKindConsumer && s.destination() != "":
+			topics[s.destination()] = true
+			add(YDependency{From: s.service, To: s.destination(), Kind: "subscribe"}, "")
+		case remoteParent:
+			// Async when either side is a messaging span without a destination to model as a topic.
+			async := s.kind == otlpKindConsumer || p.kind == otlpKindProducer || messaging
+			kind := s.depKind()
+			if async {
+				kind = "event"
+			}
+			add(YDependency{From: p.service, To: s.service, Kind: kind, Sync: !async}, s.endpoint())
+		case s.kind == otlpKindClient && s.attr("db.system") != "":
+			name := s.attr("db.name", "db.namespace", "peer.service", "server.address", "net.peer.name", "db.system")
+			datastores[name] = s.attr("db.system")
+			add(YDependency{From: s.service, To: name, Kind: "db", Sync: true}, s.attr("db.operation.name", "db.operation"))
+		case s.kind == otlpKindClient && !hasRemoteChild[key]:
+			// An uninstrumented callee: name it by peer.service or the server address.
+			peer := s.attr("peer.service", "server.address", "net.peer.name", "http.host")
+			if peer == "" || peer == s.service {
+				continue
+			}
+			peers[peer] = true
+			add(YDependency{From: s.service, To: peer, Kind: s.depKind(), Sync: true}, s.endpoint())
+		}
+	}
+
+	minutes := 1.0
+	if maxEnd > minStart {
+		minutes = math.Max(float64(maxEnd-minStart)/float64(60e9), 1.0/60)
+	}
+
+	spec := &YSpec{Metadata: map[string]any{
+		"source": "otlp",
+		"observed": map[string]any{
+			"spans":          len(spans),
+			"traces":         len(traces),
+			"window_minutes": math.Round(minutes*100) / 100,
+		},
+	}}
+	for _, name := range SortedSet(services) {
+		spec.Services = append(spec.Services, YService{Name: name, Type: "service"})
+	}
+	for _, name := range SortedSet(peers) {
+		if services[name] || datastores[name] != "" || topics[name] {
+			continue
+		}
+		spec.Services = append(spec.Services, YService{Name: name, Type: "external_system"})
+	}
+	for _, name := range SortedSet(topics) {
+		spec.Topics = append(spec.Topics, YTopic{Name: name})
+	}
+	dbNames := make([]string, 0, len(datastores))
+	for name := range datastores {
+		dbNames = append(dbNames, name)
+	}
+	sort.Strings(dbNames)
+	for _, name := range dbNames {
+		spec.Datastores = append(spec.Datastores, YDatastore{Name: name, Type: datastores[name]})
+	}
+	for _, k := range edgeOrder {
+		e := edges[k]
+		dep := e.dep
+		dep.RatePerMin = int(math.Max(1, math.Round(float64(e.count)/minutes)))
+		dep.Endpoints = SortedSet(e.endpoints)
+		spec.Dependencies = append(spec.Dependencies, dep)
+	}
+	return spec, nil
+}
+
+// SortedSet returns the keys of m in sorted order.
+func SortedSet(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
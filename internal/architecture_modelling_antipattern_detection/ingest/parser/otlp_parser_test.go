@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"testing"
+)
+
+// Two exports on separate lines (collector file exporter format), one minute apart.
+const otlpSample = `{"resourceSpans":[
+ {"resource":{"attributes":[{"key":"service.name","value":{"stringValue":"gateway"}}]},
+  "scopeSpans":[{"spans":[
+   {"traceId":"t1","spanId":"a1","name":"GET /orders","kind":2,"startTimeUnixNano":"0","endTimeUnixNano":"1000"},
+   {"traceId":"t1","spanId":"a2","parentSpanId":"a1","name":"GET","kind":3,"startTimeUnixNano":"10","endTimeUnixNano":"900"}
+  ]}]},
+ {"resource":{"attributes":[{"key":"service.name","value":{"stringValue":"orders"}}]},
+  "scopeSpans":[{"spans":[
+   {"traceId":"t1","spanId":"b1","parentSpanId":"a2","name":"GET /orders/{id}","kind":"SPAN_KIND_SERVER",
+    "attributes":[{"key":"http.route","value":{"stringValue":"/orders/{id}"}},{"key":"http.request.method","value":{"stringValue":"GET"}}]},
+   {"traceId":"t1","spanId":"b2","parentSpanId":"b1","name":"SELECT","kind":3,
+    "attributes":[{"key":"db.system","value":{"stringValue":"postgresql"}},{"key":"db.name","value":{"stringValue":"orders_db"}}]},
+   {"traceId":"t1","spanId":"b3","parentSpanId":"b1","name":"order.created publish","kind":4,
+    "attributes":[{"key":"messaging.system","value":{"stringValue":"kafka"}},{"key":"messaging.destination.name","value":{"stringValue":"order.created"}}]},
+   {"traceId":"t1","spanId":"b4","parentSpanId":"b1","name":"POST","kind":3,
+    "attributes":[{"key":"peer.service","value":{"stringValue":"stripe"}},{"key":"url.path","value":{"stringValue":"/v1/charges"}}]}
+  ]}]}
+]}
+{"resourceSpans":[
+ {"resource":{"attributes":[{"key":"service.name","value":{"stringValue":"gateway"}}]},
+  "scopeSpans":[{"spans":[
+   {"traceId":"t2","spanId":"c1","name":"GET /orders","kind":2,"startTimeUnixNano":"60000000000","endTimeUnixNano":"120000000000"},
+   {"traceId":"t2","spanId":"c2","parentSpanId":"c1","name":"GET","kind":3}
+  ]}]},
+ {"resource":{"attributes":[{"key":"service.name","value":{"stringValue":"orders"}}]},
+  "scopeSpans":[{"spans":[
+   {"traceId":"t2","spanId":"d1","parentSpanId":"c2","name":"GET /orders","kind":2,
+    "attributes":[{"key":"http.route","value":{"stringValue":"/orders"}},{"key":"http.method","value":{"stringValue":"GET"}}]}
+  ]}]},
+ {"resource":{"attributes":[{"key":"service.name","value":{"stringValue":"billing"}}]},
+  "scopeSpans":[{"spans":[
+   {"traceId":"t1","spanId":"e1","parentSpanId":"b3","name":"order.created process","kind":5,
+    "attributes":[{"key":"messaging.system","value":{"stringValue":"kafka"}},{"key":"messaging.destination.name","value":{"stringValue":"order.created"}}]}
+  ]}]}
+]}
+`
+
+func TestParseOTLPBytes(t *testing.T) {
+	s, err := ParseOTLPBytes([]byte(otlpSample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := map[string]string{}
+	for _, svc := range s.Services {
+		types[svc.Name] = svc.Type
+	}
+	if types["gateway"] != "service" || types["orders"] != "service" || types["billing"] != "service" || types["stripe"] != "external_system" {
+		t.Fatalf("services: %+v", s.Services)
+	}
+	if len(s.Datastores) != 1 || s.Datastores[0].Name != "orders_db" || s.Datastores[0].Type != "postgresql" {
+		t.Fatalf("datastores: %+v", s.Datastores)
+	}
+	if len(s.Topics) != 1 || s.Topics[0].Name != "order.created" {
+		t.Fatalf("topics: %+v", s.Topics)
+	}
+
+	deps := map[[2]string]YDependency{}
+	for _, d := range s.Dependencies {
+		deps[[2]string{d.From, d.To}] = d
+	}
+	if len(deps) != 5 {
+		t.Fatalf("dependencies: %+v", s.Dependencies)
+	}
+	gw := deps[[2]string{"gateway", "orders"}]
+	// Two calls over a two-minute window.
+	if gw.Kind != "rest" || !gw.Sync || gw.RatePerMin != 1 || len(gw.Endpoints) != 2 || gw.Endpoints[0] != "GET /orders" {
+		t.Fatalf("gateway -> orders: %+v", gw)
+	}
+	if d := deps[[2]string{"orders", "orders_db"}]; d.Kind != "db" || !d.Sync {
+		t.Fatalf("orders -> db: %+v", d)
+	}
+	if d := deps[[2]string{"orders", "order.created"}]; d.Kind != "publish" || d.Sync {
+		t.Fatalf("orders -> topic: %+v", d)
+	}
+	if d := deps[[2]string{"billing", "order.created"}]; d.Kind != "subscribe" || d.Sync {
+		t.Fatalf("billing -> topic: %+v", d)
+	}
+	if d := deps[[2]string{"orders", "stripe"}]; d.Kind != "rest" || len(d.Endpoints) != 1 || d.Endpoints[0] != "/v1/charges" {
+		t.Fatalf("orders -> stripe: %+v", d)
+	}
+}
+
+func TestParseOTLPBytes_Empty(t *testing.T) {
+	if _, err := ParseOTLPBytes([]byte(`{"resourceSpans":[]}`)); err == nil {
+		t.Fatal("expected an error when there are no spans")
+	}
+}
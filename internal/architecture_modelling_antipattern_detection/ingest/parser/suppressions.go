@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YSuppression is one entry of metadata.suppressions: a known anti-pattern the team accepts.
+//
+//	metadata:
+//	  suppressions:
+//	    - kind: shared_database
+//	      nodes: [orders-db]
+//	      reason: "legacy schema, split planned for Q3"
+//	      expires: 2026-12-31
+type YSuppression struct {
+	Kind    string   `yaml:"kind"`
+	Nodes   []string `yaml:"nodes,omitempty"`
+	Reason  string   `yaml:"reason,omitempty"`
+	Expires string   `yaml:"expires,omitempty"`
+}
+
+// ExpiresAt parses Expires (YYYY-MM-DD, end of that day UTC, or RFC3339). Nil when unset.
+func (s YSuppression) ExpiresAt() (*time.Time, error) {
+	v := strings.TrimSpace(s.Expires)
+	if v == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse("2006-01-02", v); err == nil {
+		end := t.Add(24*time.Hour - time.Nanosecond)
+		return &end, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, fmt.Errorf("suppression %q: expires must be YYYY-MM-DD or RFC3339, got %q", s.Kind, v)
+	}
+	return &t, nil
+}
+
+// Suppressions decodes metadata.suppressions. A spec without them returns nil.
+func (s *YSpec) Suppressions() ([]YSuppression, error) {
+	if s == nil || s.Metadata == nil {
+		return nil, nil
+	}
+	raw, ok := s.Metadata["suppressions"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	b, err := yaml.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var out []YSuppression
+	if err := yaml.Unmarshal(b, &out); err != nil {
+		return nil, fmt.Errorf("metadata.suppressions: %w", err)
+	}
+	for i, sup := range out {
+		if strings.TrimSpace(sup.Kind) == "" {
+			return nil, fmt.Errorf("metadata.suppressions[%d]: kind is required", i)
+		}
+		if _, err := sup.ExpiresAt(); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,127 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Shared by the deployment importers (docker-compose, Kubernetes): image classification and
+// dependency kinds.
+
+// workloadRole is what a container image is used as in the architecture.
+type workloadRole int
+
+const (
+	roleService workloadRole = iota
+	roleGateway
+	roleDatastore
+	roleBroker
+	roleInfra // ignored (e.g. zookeeper, monitoring)
+)
+
+// knownImages maps image names to a role and (for datastores/brokers) a type. A name matches
+// whole "-", "_" or "." separated words of the image base (see imageMatches), and the first
+// match wins, so admin tools named after a datastore are listed before the datastore.
+var knownImages = []struct {
+	match string
+	role  workloadRole
+	typ   string
+}{
+	{"mongo-express", roleInfra, ""},
+	{"redis-commander", roleInfra, ""},
+	{"redisinsight", roleInfra, ""},
+	{"kafka-ui", roleInfra, ""},
+	{"pgadmin4", roleInfra, ""},
+	{"phpmyadmin", roleInfra, ""},
+	{"adminer", roleInfra, ""},
+	{"postgis", roleDatastore, "postgres"},
+	{"postgres", roleDatastore, "postgres"},
+	{"postgresql", roleDatastore, "postgres"},
+	{"mysql", roleDatastore, "mysql"},
+	{"mariadb", roleDatastore, "mysql"},
+	{"mongo", roleDatastore, "mongo"},
+	{"redis", roleDatastore, "redis"},
+	{"valkey", roleDatastore, "redis"},
+	{"memcached", roleDatastore, "memcached"},
+	{"cassandra", roleDatastore, "cassandra"},
+	{"elasticsearch", roleDatastore, "elasticsearch"},
+	{"opensearch", roleDatastore, "elasticsearch"},
+	{"mssql", roleDatastore, "mssql"},
+	{"redpanda", roleBroker, "kafka"},
+	{"kafka", roleBroker, "kafka"},
+	{"rabbitmq", roleBroker, "rabbitmq"},
+	{"nats", roleBroker, "nats"},
+	{"pulsar", roleBroker, "pulsar"},
+	{"zookeeper", roleInfra, ""},
+	{"prometheus", roleInfra, ""},
+	{"grafana", roleInfra, ""},
+	{"jaeger", roleInfra, ""},
+	{"otel", roleInfra, ""},
+	{"opentelemetry-collector", roleInfra, ""},
+	{"nginx", roleGateway, ""},
+	{"traefik", roleGateway, ""},
+	{"envoy", roleGateway, ""},
+	{"kong", roleGateway, ""},
+	{"haproxy", roleGateway, ""},
+	{"caddy", roleGateway, ""},
+}
+
+// imageBase strips registry, repository path, tag and digest: "docker.io/bitnami/kafka:3.6" -> "kafka".
+func imageBase(image string) string {
+	image = strings.ToLower(strings.TrimSpace(image))
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, "/"); i >= 0 {
+		image = image[i+1:]
+	}
+	if i := strings.Index(image, ":"); i >= 0 {
+		image = image[:i]
+	}
+	return image
+}
+
+func classifyImage(image string) (workloadRole, string) {
+	base := imageBase(image)
+	if base == "" {
+		return roleService, ""
+	}
+	for _, c := range knownImages {
+		if imageMatches(base, c.match) {
+			return c.role, c.typ
+		}
+	}
+	return roleService, ""
+}
+
+// imageMatches reports whether name occurs in base as whole words: "cp-kafka" and "kafka_2.13"
+// match "kafka", "hotel-service" does not match "otel".
+func imageMatches(base, name string) bool {
+	norm := func(s string) string {
+		return "-" + strings.NewReplacer("_", "-", ".", "-").Replace(s) + "-"
+	}
+	return strings.Contains(norm(base), norm(name))
+}
+
+// subscribeEnv marks broker references that consume rather than publish.
+var subscribeEnv = regexp.MustCompile(`(?i)consum|subscrib|listen|group_id|group\.id`)
+
+// newDependency builds from -> to for a target with role; hint is the environment entry
+// ("KEY value") the reference came from, or empty.
+func newDependency(from, to string, role workloadRole, hint string) YDependency {
+	dep := YDependency{From: from, To: to, Kind: "rest", Sync: true}
+	switch role {
+	case roleDatastore:
+		dep.Kind = "db"
+	case roleBroker:
+		dep.Kind, dep.Sync = "publish", false
+		if subscribeEnv.MatchString(hint) {
+			dep.Kind = "subscribe"
+		}
+	default:
+		if strings.Contains(strings.ToLower(hint), "grpc") {
+			dep.Kind = "grpc"
+		}
+	}
+	return dep
+}
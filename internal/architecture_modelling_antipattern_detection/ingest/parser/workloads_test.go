@@ -0,0 +1,27 @@
+package parser
+
+import "testing"
+
+func TestClassifyImage(t *testing.T) {
+	cases := []struct {
+		image string
+		role  workloadRole
+		typ   string
+	}{
+		{"acme/hotel-service:1.0", roleService, ""},
+		{"mongo-express:1.0", roleInfra, ""},
+		{"rediscommander/redis-commander:latest", roleInfra, ""},
+		{"mongo:7", roleDatastore, "mongo"},
+		{"bitnami/postgresql:16", roleDatastore, "postgres"},
+		{"confluentinc/cp-kafka:7.5.0", roleBroker, "kafka"},
+		{"otel/opentelemetry-collector-contrib:0.98.0", roleInfra, ""},
+		{"ghcr.io/acme/redistribution-api:2", roleService, ""},
+		{"nginx:1.25", roleGateway, ""},
+	}
+	for _, c := range cases {
+		role, typ := classifyImage(c.image)
+		if role != c.role || typ != c.typ {
+			t.Errorf("classifyImage(%q) = %v %q, want %v %q", c.image, role, typ, c.role, c.typ)
+		}
+	}
+}
@@ -36,6 +36,16 @@ type YAPI struct {
 type YDatastore struct {
 	Name string `yaml:"name"`
 	Type string `yaml:"type,omitempty"`
+	// Replicas and Resources describe the deployment (e.g. imported from Kubernetes); the
+	// simulation scenario generator uses them instead of its defaults.
+	Replicas  int         `yaml:"replicas,omitempty"`
+	Resources *YResources `yaml:"resources,omitempty"`
+}
+
+// YResources are per-replica resource requests.
+type YResources struct {
+	CPUCores float64 `yaml:"cpu_cores,omitempty"`
+	MemoryMB float64 `yaml:"memory_mb,omitempty"`
 }
 
 type YDependency struct {
@@ -43,6 +53,11 @@ type YDependency struct {
 	To   string `yaml:"to"`
 	Kind string `yaml:"kind,omitempty"`
 	Sync bool   `yaml:"sync,omitempty"`
+	// Optional call-rate hints (same meaning as YCall); used by the chatty_service detector.
+	RatePerMin int  `yaml:"rate_per_min,omitempty"`
+	PerItem    bool `yaml:"per_item,omitempty"`
+	// Endpoints called on the target (e.g. observed in traces); shown as the edge's endpoint count.
+	Endpoints []string `yaml:"endpoints,omitempty"`
 }
 
 type YTopic struct {
@@ -51,10 +66,15 @@ type YTopic struct {
 
 
 type YService struct {
-	Name      string     `yaml:"name"`
-	Type      string     `yaml:"type,omitempty"`
-	Calls     []YCall    `yaml:"calls,omitempty"`
-	Databases YDatabases `yaml:"databases,omitempty"`
+	Name string `yaml:"name"`
+	Type string `yaml:"type,omitempty"`
+	// Labels are free-form key/values (team, domain, tier...) exposed to custom detection rules.
+	Labels    map[string]string `yaml:"labels,omitempty"`
+	Calls     []YCall           `yaml:"calls,omitempty"`
+	Databases YDatabases        `yaml:"databases,omitempty"`
+	// Replicas and Resources describe the deployment, as on YDatastore.
+	Replicas  int         `yaml:"replicas,omitempty"`
+	Resources *YResources `yaml:"resources,omitempty"`
 }
 
 type YDatabase struct {
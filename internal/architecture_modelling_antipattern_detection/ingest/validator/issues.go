@@ -0,0 +1,91 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Severity string
+
+const (
+	// SeverityError issues make the spec unusable; analysis does not run.
+	SeverityError Severity = "error"
+	// SeverityWarning issues are probably mistakes but the spec can still be analyzed.
+	SeverityWarning Severity = "warning"
+)
+
+// Issue codes.
+const (
+	CodeYAMLSyntax          = "yaml_syntax"
+	CodeInvalidType         = "invalid_type"
+	CodeEmptyName           = "empty_name"
+	CodeDuplicateService    = "duplicate_service"
+	CodeDuplicateDatastore  = "duplicate_datastore"
+	CodeEmptyReference      = "empty_reference"
+	CodeUnknownService      = "unknown_service"
+	CodeUndeclaredDatastore = "undeclared_datastore"
+)
+
+// Issue is one validation problem. Path is a YAML path such as "dependencies[2].to"; Line and
+// Column are 1-based positions in the source (0 when unknown, e.g. for specs built in code).
+type Issue struct {
+	Path     string   `json:"path" yaml:"path"`
+	Line     int      `json:"line,omitempty" yaml:"line,omitempty"`
+	Column   int      `json:"column,omitempty" yaml:"column,omitempty"`
+	Severity Severity `json:"severity" yaml:"severity"`
+	Code     string   `json:"code" yaml:"code"`
+	Message  string   `json:"message" yaml:"message"`
+}
+
+func (i Issue) String() string {
+	var b strings.Builder
+	if i.Line > 0 {
+		fmt.Fprintf(&b, "line %d:%d: ", i.Line, i.Column)
+	}
+	if i.Path != "" {
+		b.WriteString(i.Path + ": ")
+	}
+	b.WriteString(i.Message)
+	return b.String()
+}
+
+// Issues is the result of validation. As an error it reports the error-severity issues.
+type Issues []Issue
+
+func (is Issues) Error() string {
+	errs := is.Errors()
+	if len(errs) == 0 {
+		errs = is
+	}
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.String()
+	}
+	return "invalid spec: " + strings.Join(msgs, "; ")
+}
+
+// Errors returns the issues with SeverityError.
+func (is Issues) Errors() Issues {
+	var out Issues
+	for _, i := range is {
+		if i.Severity == SeverityError {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// Warnings returns the issues with SeverityWarning.
+func (is Issues) Warnings() Issues {
+	var out Issues
+	for _, i := range is {
+		if i.Severity == SeverityWarning {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+func (is Issues) HasErrors() bool {
+	return len(is.Errors()) > 0
+}
@@ -1,35 +1,229 @@
 package validator
 
 import (
+	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 
+	"gopkg.in/yaml.v3"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/mapper"
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
 )
 
+// Validate checks a spec built in code (or already decoded). It returns Issues holding the
+// error-severity problems, or nil; use ValidateYAML for positions and warnings.
 func Validate(s *parser.YSpec) error {
 	if s == nil {
 		return fmt.Errorf("spec is nil")
 	}
+	var doc yaml.Node
+	if err := doc.Encode(s); err != nil {
+		return err
+	}
+	if errs := check(&doc).Errors(); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
 
-	seen := map[string]bool{}
-	for _, svc := range s.Services {
-		n := strings.TrimSpace(svc.Name)
-		if n == "" {
-			return fmt.Errorf("service name is empty")
-		}
-		key := strings.ToLower(n)
-		if seen[key] {
-			return fmt.Errorf("duplicate service: %q", n)
+// ValidateYAML parses b and reports every problem found, with source positions. The spec is
+// nil when b is not valid YAML; otherwise it is returned even when there are issues.
+func ValidateYAML(b []byte) (*parser.YSpec, Issues) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, Issues{yamlError(err, CodeYAMLSyntax)}
+	}
+	var s parser.YSpec
+	var issues Issues
+	if len(doc.Content) > 0 {
+		var te *yaml.TypeError
+		if err := doc.Decode(&s); errors.As(err, &te) {
+			for _, msg := range te.Errors {
+				issues = append(issues, yamlError(errors.New(msg), CodeInvalidType))
+			}
+		} else if err != nil {
+			issues = append(issues, yamlError(err, CodeInvalidType))
 		}
-		seen[key] = true
 	}
+	return &s, append(issues, check(&doc)...)
+}
+
+var yamlErrLine = regexp.MustCompile(`line (\d+)(?::(\d+))?: `)
 
-	for _, d := range s.Dependencies {
-		if strings.TrimSpace(d.From) == "" || strings.TrimSpace(d.To) == "" {
-			return fmt.Errorf("dependency has empty from/to")
+// yamlError turns a yaml.v3 error ("yaml: line 3: ...") into an issue at that line.
+func yamlError(err error, code string) Issue {
+	msg := strings.TrimPrefix(err.Error(), "yaml: ")
+	is := Issue{Severity: SeverityError, Code: code, Message: msg}
+	if m := yamlErrLine.FindStringSubmatchIndex(msg); m != nil {
+		is.Line, _ = strconv.Atoi(msg[m[2]:m[3]])
+		if m[4] >= 0 {
+			is.Column, _ = strconv.Atoi(msg[m[4]:m[5]])
 		}
+		is.Message = msg[:m[0]] + msg[m[1]:]
 	}
+	return is
+}
 
+// field returns the value of key in mapping n, or nil.
+func field(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
 	return nil
 }
+
+// items returns the elements of sequence n (none when n is missing or not a sequence).
+func items(n *yaml.Node) []*yaml.Node {
+	if n == nil || n.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return n.Content
+}
+
+type checker struct {
+	issues Issues
+}
+
+func (c *checker) add(n *yaml.Node, path string, sev Severity, code, format string, args ...any) {
+	is := Issue{Path: path, Severity: sev, Code: code, Message: fmt.Sprintf(format, args...)}
+	if n != nil {
+		is.Line, is.Column = n.Line, n.Column
+	}
+	c.issues = append(c.issues, is)
+}
+
+// name reads the scalar "name" of item and reports it with sev when empty.
+func (c *checker) name(item *yaml.Node, path string, sev Severity) string {
+	n := field(item, "name")
+	if n == nil || strings.TrimSpace(n.Value) == "" {
+		at := item
+		if n != nil {
+			at = n
+		}
+		c.add(at, path+".name", sev, CodeEmptyName, "name is empty")
+		return ""
+	}
+	return mapper.StripNodeNameRef(n.Value)
+}
+
+func refKey(s string) string {
+	return strings.ToLower(strings.TrimSpace(mapper.StripNodeNameRef(s)))
+}
+
+// check validates a document node, or the mapping node Validate encodes a spec into.
+func check(doc *yaml.Node) Issues {
+	c := &checker{}
+	root := doc
+	if doc.Kind == yaml.DocumentNode {
+		if len(doc.Content) == 0 {
+			return nil
+		}
+		root = doc.Content[0]
+	}
+	if root.Kind == 0 {
+		return nil
+	}
+	if root.Kind != yaml.MappingNode {
+		c.add(root, "", SeverityError, CodeInvalidType, "spec must be a mapping with services, dependencies, ...")
+		return c.issues
+	}
+
+	services := map[string]bool{}
+	datastores := map[string]bool{}
+	declared := map[string]bool{} // anything a dependency may point at
+	for i, item := range items(field(root, "services")) {
+		path := fmt.Sprintf("services[%d]", i)
+		name := c.name(item, path, SeverityError)
+		if name == "" {
+			continue
+		}
+		if services[refKey(name)] {
+			c.add(field(item, "name"), path+".name", SeverityError, CodeDuplicateService, "duplicate service %q", name)
+		}
+		services[refKey(name)] = true
+		declared[refKey(name)] = true
+		// A service typed as a database (legacy specs) is a datastore too.
+		if t := field(item, "type"); t != nil && mapper.CanonicalServiceTypeForYAML(t.Value) == "database" {
+			datastores[refKey(name)] = true
+		}
+	}
+	for _, section := range []string{"datastores", "databases"} {
+		for i, item := range items(field(root, section)) {
+			path := fmt.Sprintf("%s[%d]", section, i)
+			// Nameless datastores and topics are skipped by the mapper, so only warn.
+			name := c.name(item, path, SeverityWarning)
+			if name == "" {
+				continue
+			}
+			if datastores[refKey(name)] && !services[refKey(name)] {
+				c.add(field(item, "name"), path+".name", SeverityWarning, CodeDuplicateDatastore, "datastore %q is declared twice", name)
+			}
+			datastores[refKey(name)] = true
+			declared[refKey(name)] = true
+		}
+	}
+	for i, item := range items(field(root, "topics")) {
+		if name := c.name(item, fmt.Sprintf("topics[%d]", i), SeverityWarning); name != "" {
+			declared[refKey(name)] = true
+		}
+	}
+
+	for i, item := range items(field(root, "dependencies")) {
+		path := fmt.Sprintf("dependencies[%d]", i)
+		kind := ""
+		if k := field(item, "kind"); k != nil {
+			kind = strings.ToLower(strings.TrimSpace(k.Value))
+		}
+		for _, end := range []string{"from", "to"} {
+			n := field(item, end)
+			if n == nil || refKey(n.Value) == "" {
+				at := item
+				if n != nil {
+					at = n
+				}
+				c.add(at, path+"."+end, SeverityError, CodeEmptyReference, "dependency %s is empty", end)
+				continue
+			}
+			if declared[refKey(n.Value)] {
+				continue
+			}
+			if end == "to" && (kind == "db" || kind == "database") {
+				c.add(n, path+".to", SeverityWarning, CodeUndeclaredDatastore, "datastore %q is not declared under datastores", n.Value)
+				continue
+			}
+			c.add(n, path+"."+end, SeverityWarning, CodeUnknownService, "%q is not a declared service, datastore or topic", n.Value)
+		}
+	}
+
+	for i, item := range items(field(root, "services")) {
+		path := fmt.Sprintf("services[%d]", i)
+		for j, call := range items(field(item, "calls")) {
+			n := field(call, "to")
+			cp := fmt.Sprintf("%s.calls[%d].to", path, j)
+			switch {
+			case n == nil || refKey(n.Value) == "":
+				c.add(call, cp, SeverityWarning, CodeEmptyReference, "call target is empty")
+			case !declared[refKey(n.Value)]:
+				c.add(n, cp, SeverityWarning, CodeUnknownService, "%q is not a declared service, datastore or topic", n.Value)
+			}
+		}
+		dbs := field(item, "databases")
+		for _, mode := range []string{"reads", "writes"} {
+			for j, n := range items(field(dbs, mode)) {
+				if !datastores[refKey(n.Value)] {
+					c.add(n, fmt.Sprintf("%s.databases.%s[%d]", path, mode, j), SeverityWarning, CodeUndeclaredDatastore,
+						"datastore %q is not declared under datastores", n.Value)
+				}
+			}
+		}
+	}
+	return c.issues
+}
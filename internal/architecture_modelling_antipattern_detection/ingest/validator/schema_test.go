@@ -0,0 +1,86 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
+)
+
+func TestValidateYAML_ReportsAllIssuesWithPositions(t *testing.T) {
+	y := `services:
+  - name: orders
+    databases:
+      reads: [orders-db]
+  - name: Orders
+  - name: ""
+datastores:
+  - name: billing-db
+dependencies:
+  - from: orders
+    to: billing
+  - from: orders
+    to: ""
+  - from: orders
+    to: stock-db
+    kind: db
+`
+	spec, issues := ValidateYAML([]byte(y))
+	if spec == nil {
+		t.Fatal("spec should be decoded even with issues")
+	}
+	type want struct {
+		path string
+		line int
+		col  int
+		sev  Severity
+		code string
+	}
+	wants := []want{
+		{"services[1].name", 5, 11, SeverityError, CodeDuplicateService},
+		{"services[2].name", 6, 11, SeverityError, CodeEmptyName},
+		{"dependencies[0].to", 11, 9, SeverityWarning, CodeUnknownService},
+		{"dependencies[1].to", 13, 9, SeverityError, CodeEmptyReference},
+		{"dependencies[2].to", 15, 9, SeverityWarning, CodeUndeclaredDatastore},
+		{"services[0].databases.reads[0]", 4, 15, SeverityWarning, CodeUndeclaredDatastore},
+	}
+	if len(issues) != len(wants) {
+		t.Fatalf("got %d issues, want %d: %+v", len(issues), len(wants), issues)
+	}
+	for i, w := range wants {
+		got := issues[i]
+		if got.Path != w.path || got.Line != w.line || got.Column != w.col || got.Severity != w.sev || got.Code != w.code {
+			t.Errorf("issue %d = %+v, want %+v", i, got, w)
+		}
+	}
+	if len(issues.Errors()) != 3 || !issues.HasErrors() {
+		t.Fatalf("expected 3 errors, got %+v", issues.Errors())
+	}
+}
+
+func TestValidateYAML_SyntaxAndTypeErrors(t *testing.T) {
+	_, issues := ValidateYAML([]byte("services:\n  - name: a\n   bad: [\n"))
+	if len(issues) != 1 || issues[0].Code != CodeYAMLSyntax || issues[0].Line == 0 {
+		t.Fatalf("expected one positioned syntax issue, got %+v", issues)
+	}
+
+	_, issues = ValidateYAML([]byte("services:\n  - name: a\n    replicas: many\n"))
+	if len(issues) != 1 || issues[0].Code != CodeInvalidType || issues[0].Line != 3 {
+		t.Fatalf("expected a type issue on line 3, got %+v", issues)
+	}
+}
+
+func TestValidate_Struct(t *testing.T) {
+	ok := &parser.YSpec{
+		Services:     []parser.YService{{Name: "a"}, {Name: "b"}},
+		Dependencies: []parser.YDependency{{From: "a", To: "b"}, {From: "a", To: "external"}},
+	}
+	if err := Validate(ok); err != nil {
+		t.Fatalf("warnings must not fail Validate: %v", err)
+	}
+	bad := &parser.YSpec{Services: []parser.YService{{Name: "a"}, {Name: "A"}}}
+	err := Validate(bad)
+	issues, isIssues := err.(Issues)
+	if !isIssues || len(issues) != 1 || issues[0].Code != CodeDuplicateService {
+		t.Fatalf("expected duplicate_service issue, got %v", err)
+	}
+}
@@ -1 +1,248 @@
-package features
\ No newline at end of file
+package features
+
+import (
+	"sort"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+// NodeMetrics are structural metrics for one node. Fan-in/fan-out count distinct neighbours over
+// CALLS / READS / WRITES edges (parallel edges count once).
+type NodeMetrics struct {
+	ID   string          `json:"id" yaml:"id"`
+	Kind domain.NodeKind `json:"kind" yaml:"kind"`
+
+	FanIn  int `json:"fan_in" yaml:"fan_in"`
+	FanOut int `json:"fan_out" yaml:"fan_out"`
+	// Instability is Martin's I = Ce / (Ca + Ce): 0 = maximally stable, 1 = maximally unstable.
+	Instability float64 `json:"instability" yaml:"instability"`
+	// Betweenness is normalized directed betweenness centrality in [0, 1].
+	Betweenness float64 `json:"betweenness" yaml:"betweenness"`
+	// SyncDepth is the longest chain of synchronous calls starting at this node (see GraphMetrics.MaxSyncDepth).
+	SyncDepth int `json:"sync_depth" yaml:"sync_depth"`
+}
+
+// GraphMetrics are whole-graph metrics plus per-node metrics sorted by node id.
+type GraphMetrics struct {
+	NodeCount int `json:"node_count" yaml:"node_count"`
+	EdgeCount int `json:"edge_count" yaml:"edge_count"`
+	// Density is distinct directed node pairs with an edge / (n * (n - 1)).
+	Density float64 `json:"density" yaml:"density"`
+	// SCCCount counts strongly connected components; CyclicSCCCount only those with more than one node.
+	SCCCount       int `json:"scc_count" yaml:"scc_count"`
+	CyclicSCCCount int `json:"cyclic_scc_count" yaml:"cyclic_scc_count"`
+	// MaxSyncDepth is the longest sync CALLS chain in hops. Sync cycles are condensed first,
+	// so a cycle adds one hop instead of looping forever.
+	MaxSyncDepth   int     `json:"max_sync_depth" yaml:"max_sync_depth"`
+	AvgFanOut      float64 `json:"avg_fan_out" yaml:"avg_fan_out"`
+	MaxBetweenness float64 `json:"max_betweenness" yaml:"max_betweenness"`
+
+	Nodes []NodeMetrics `json:"nodes" yaml:"nodes"`
+}
+
+func isDependency(e *domain.Edge) bool {
+	if e == nil {
+		return false
+	}
+	switch e.Kind {
+	case domain.EdgeCalls, domain.EdgeReads, domain.EdgeWrites:
+		return true
+	}
+	return false
+}
+
+func isSyncCall(e *domain.Edge) bool {
+	if e == nil || e.Kind != domain.EdgeCalls {
+		return false
+	}
+	if e.Attrs != nil {
+		if b, ok := e.Attrs["sync"].(bool); ok {
+			return b
+		}
+	}
+	return true
+}
+
+// adjacency returns sorted, deduplicated successor lists (self-loops dropped) for edges accepted by keep.
+func adjacency(g *domain.Graph, ids []string, keep func(*domain.Edge) bool) map[string][]string {
+	adj := make(map[string][]string, len(ids))
+	for _, v := range ids {
+		seen := map[string]bool{}
+		for _, e := range g.Out[v] {
+			if !keep(e) || e.To == v || seen[e.To] {
+				continue
+			}
+			if _, ok := g.Nodes[e.To]; !ok {
+				continue
+			}
+			seen[e.To] = true
+			adj[v] = append(adj[v], e.To)
+		}
+		sort.Strings(adj[v])
+	}
+	return adj
+}
+
+// Compute returns structural metrics for g. A nil graph yields zero metrics.
+func Compute(g *domain.Graph) *GraphMetrics {
+	m := &GraphMetrics{Nodes: []NodeMetrics{}}
+	if g == nil {
+		return m
+	}
+
+	ids := make([]string, 0, len(g.Nodes))
+	for id := range g.Nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	n := len(ids)
+
+	adj := adjacency(g, ids, isDependency)
+	fanIn := map[string]int{}
+	pairs := 0
+	for _, v := range ids {
+		for _, w := range adj[v] {
+			fanIn[w]++
+			pairs++
+		}
+	}
+
+	bc := betweenness(ids, adj)
+	comps := domain.StronglyConnected(ids, adj)
+	depth := syncDepths(ids, adjacency(g, ids, isSyncCall))
+
+	m.NodeCount = n
+	m.EdgeCount = len(g.Edges)
+	if n > 1 {
+		m.Density = float64(pairs) / float64(n*(n-1))
+	}
+	m.SCCCount = len(comps)
+	for _, c := range comps {
+		if len(c) > 1 {
+			m.CyclicSCCCount++
+		}
+	}
+
+	totalOut := 0
+	for _, v := range ids {
+		out := len(adj[v])
+		in := fanIn[v]
+		totalOut += out
+		inst := 0.0
+		if in+out > 0 {
+			inst = float64(out) / float64(in+out)
+		}
+		nm := NodeMetrics{
+			ID:          v,
+			Kind:        g.Nodes[v].Kind,
+			FanIn:       in,
+			FanOut:      out,
+			Instability: inst,
+			Betweenness: bc[v],
+			SyncDepth:   depth[v],
+		}
+		if nm.SyncDepth > m.MaxSyncDepth {
+			m.MaxSyncDepth = nm.SyncDepth
+		}
+		if nm.Betweenness > m.MaxBetweenness {
+			m.MaxBetweenness = nm.Betweenness
+		}
+		m.Nodes = append(m.Nodes, nm)
+	}
+	if n > 0 {
+		m.AvgFanOut = float64(totalOut) / float64(n)
+	}
+	return m
+}
+
+// NodeByID returns the metrics for id, or nil.
+func (m *GraphMetrics) NodeByID(id string) *NodeMetrics {
+	if m == nil {
+		return nil
+	}
+	i := sort.Search(len(m.Nodes), func(i int) bool { return m.Nodes[i].ID >= id })
+	if i < len(m.Nodes) && m.Nodes[i].ID == id {
+		return &m.Nodes[i]
+	}
+	return nil
+}
+
+// betweenness is Brandes' algorithm for unweighted directed graphs, normalized by (n-1)(n-2).
+func betweenness(ids []string, adj map[string][]string) map[string]float64 {
+	cb := make(map[string]float64, len(ids))
+	for _, s := range ids {
+		var stack []string
+		pred := map[string][]string{}
+		sigma := map[string]float64{s: 1}
+		dist := map[string]int{s: 0}
+		queue := []string{s}
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			stack = append(stack, v)
+			for _, w := range adj[v] {
+				if _, seen := dist[w]; !seen {
+					dist[w] = dist[v] + 1
+					queue = append(queue, w)
+				}
+				if dist[w] == dist[v]+1 {
+					sigma[w] += sigma[v]
+					pred[w] = append(pred[w], v)
+				}
+			}
+		}
+		delta := map[string]float64{}
+		for i := len(stack) - 1; i >= 0; i-- {
+			w := stack[i]
+			for _, v := range pred[w] {
+				delta[v] += sigma[v] / sigma[w] * (1 + delta[w])
+			}
+			if w != s {
+				cb[w] += delta[w]
+			}
+		}
+	}
+	if n := len(ids); n > 2 {
+		norm := float64((n - 1) * (n - 2))
+		for k := range cb {
+			cb[k] /= norm
+		}
+	}
+	return cb
+}
+
+// syncDepths returns, per node, the longest hop count over the condensation of the sync call graph.
+// StronglyConnected emits components in reverse topological order, so successors are resolved first.
+func syncDepths(ids []string, adj map[string][]string) map[string]int {
+	comps := domain.StronglyConnected(ids, adj)
+	compOf := map[string]int{}
+	for i, c := range comps {
+		for _, v := range c {
+			compOf[v] = i
+		}
+	}
+	compDepth := make([]int, len(comps))
+	for i, c := range comps {
+		best := 0
+		if len(c) > 1 {
+			best = 1
+		}
+		for _, v := range c {
+			for _, w := range adj[v] {
+				j := compOf[w]
+				if j == i {
+					continue
+				}
+				if d := compDepth[j] + 1; d > best {
+					best = d
+				}
+			}
+		}
+		compDepth[i] = best
+	}
+	out := make(map[string]int, len(ids))
+	for _, v := range ids {
+		out[v] = compDepth[compOf[v]]
+	}
+	return out
+}
@@ -0,0 +1,60 @@
+package features
+
+import (
+	"math"
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+func TestCompute_ChainWithCycle(t *testing.T) {
+	g := domain.NewGraph()
+	for _, id := range []string{"a", "b", "c", "d"} {
+		g.AddNode(&domain.Node{ID: id, Name: id, Kind: domain.NodeService})
+	}
+	call := func(from, to string, sync bool) {
+		g.AddEdge(&domain.Edge{From: from, To: to, Kind: domain.EdgeCalls, Attrs: domain.Attrs{"sync": sync}})
+	}
+	call("a", "b", true)
+	call("a", "b", true) // parallel edge counts once
+	call("b", "c", true)
+	call("c", "b", true)
+	call("c", "d", false)
+
+	m := Compute(g)
+	if m.NodeCount != 4 || m.EdgeCount != 5 {
+		t.Fatalf("counts: %+v", m)
+	}
+	if m.SCCCount != 3 || m.CyclicSCCCount != 1 {
+		t.Fatalf("expected 3 SCCs (one cyclic), got %d / %d", m.SCCCount, m.CyclicSCCCount)
+	}
+	if want := 4.0 / 12.0; math.Abs(m.Density-want) > 1e-9 {
+		t.Fatalf("density: want %v, got %v", want, m.Density)
+	}
+	// a -> {b,c} is one condensed hop plus the cycle itself; c -> d is async.
+	if m.MaxSyncDepth != 2 || m.NodeByID("a").SyncDepth != 2 {
+		t.Fatalf("sync depth: %+v", m)
+	}
+
+	b := m.NodeByID("b")
+	if b.FanIn != 2 || b.FanOut != 1 {
+		t.Fatalf("b fan-in/out: %+v", b)
+	}
+	if math.Abs(b.Instability-1.0/3.0) > 1e-9 {
+		t.Fatalf("b instability: %v", b.Instability)
+	}
+	// b lies on a→c and a→d; c lies on a→d and b→d: both 2 / ((n-1)(n-2)) = 2/6.
+	if math.Abs(b.Betweenness-2.0/6.0) > 1e-9 || math.Abs(m.NodeByID("c").Betweenness-2.0/6.0) > 1e-9 {
+		t.Fatalf("betweenness b=%v c=%v", b.Betweenness, m.NodeByID("c").Betweenness)
+	}
+	if m.NodeByID("d").Instability != 0 || m.NodeByID("a").Instability != 1 {
+		t.Fatalf("instability extremes: a=%v d=%v", m.NodeByID("a").Instability, m.NodeByID("d").Instability)
+	}
+}
+
+func TestCompute_NilGraph(t *testing.T) {
+	m := Compute(nil)
+	if m == nil || m.NodeCount != 0 || m.Nodes == nil {
+		t.Fatalf("expected empty metrics, got %#v", m)
+	}
+}
@@ -1 +1,77 @@
-package model
\ No newline at end of file
+package model
+
+import (
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ml/features"
+)
+
+// Feature names produced by DetectionFeatures. Kind indicators are "kind:<anti-pattern kind>".
+const (
+	FeatSeverity        = "severity"
+	FeatNodes           = "nodes"
+	FeatMaxFanIn        = "max_fan_in"
+	FeatMaxFanOut       = "max_fan_out"
+	FeatMaxBetweenness  = "max_betweenness"
+	FeatMeanInstability = "mean_instability"
+	FeatMaxSyncDepth    = "max_sync_depth"
+	FeatGraphDensity    = "graph_density"
+	FeatGraphCyclicSCCs = "graph_cyclic_sccs"
+	FeatGraphNodes      = "graph_nodes"
+)
+
+// KindFeature is the one-hot feature name for an anti-pattern kind.
+func KindFeature(k domain.AntiPatternKind) string { return "kind:" + string(k) }
+
+func severityValue(s domain.Severity) float64 {
+	switch s {
+	case domain.SeverityHigh:
+		return 3
+	case domain.SeverityMedium:
+		return 2
+	case domain.SeverityLow:
+		return 1
+	}
+	return 0
+}
+
+// DetectionFeatures turns a detection plus graph metrics into a named feature vector.
+// Node-level features aggregate over the detection's nodes; m may be nil (graph features stay 0).
+func DetectionFeatures(m *features.GraphMetrics, d domain.Detection) map[string]float64 {
+	f := map[string]float64{
+		KindFeature(d.Kind): 1,
+		FeatSeverity:        severityValue(d.Severity),
+		FeatNodes:           float64(len(d.Nodes)),
+	}
+	if m == nil {
+		return f
+	}
+	f[FeatGraphDensity] = m.Density
+	f[FeatGraphCyclicSCCs] = float64(m.CyclicSCCCount)
+	f[FeatGraphNodes] = float64(m.NodeCount)
+
+	var instSum float64
+	seen := 0
+	for _, id := range d.Nodes {
+		nm := m.NodeByID(id)
+		if nm == nil {
+			continue
+		}
+		seen++
+		instSum += nm.Instability
+		f[FeatMaxFanIn] = maxf(f[FeatMaxFanIn], float64(nm.FanIn))
+		f[FeatMaxFanOut] = maxf(f[FeatMaxFanOut], float64(nm.FanOut))
+		f[FeatMaxBetweenness] = maxf(f[FeatMaxBetweenness], nm.Betweenness)
+		f[FeatMaxSyncDepth] = maxf(f[FeatMaxSyncDepth], float64(nm.SyncDepth))
+	}
+	if seen > 0 {
+		f[FeatMeanInstability] = instSum / float64(seen)
+	}
+	return f
+}
+
+func maxf(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
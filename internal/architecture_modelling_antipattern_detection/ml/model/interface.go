@@ -1 +1,19 @@
-package model
\ No newline at end of file
+package model
+
+import (
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ml/features"
+)
+
+// Prediction is a model's view of one detection.
+type Prediction struct {
+	// Confidence is a calibrated probability in [0, 1] that the detection is a real, worth-fixing problem.
+	Confidence float64         `json:"confidence"`
+	Severity   domain.Severity `json:"severity"`
+}
+
+// Model scores detections from graph metrics plus the detection itself.
+type Model interface {
+	Name() string
+	Predict(m *features.GraphMetrics, d domain.Detection) (Prediction, error)
+}
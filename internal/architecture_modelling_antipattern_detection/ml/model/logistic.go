@@ -0,0 +1,123 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ml/features"
+)
+
+// TypeLogistic is the "type" value of a logistic-regression weights file.
+const TypeLogistic = "logistic_regression"
+
+// Calibration is Platt scaling applied to the raw logit: p = sigmoid(A*z + B).
+type Calibration struct {
+	A float64 `json:"a"`
+	B float64 `json:"b"`
+}
+
+// SeverityThresholds map a calibrated confidence to a severity (>= High → HIGH, >= Medium → MEDIUM, else LOW).
+type SeverityThresholds struct {
+	High   float64 `json:"high"`
+	Medium float64 `json:"medium"`
+}
+
+// Logistic is a logistic-regression model over DetectionFeatures. Features without a weight are ignored.
+//
+// Weights file:
+//
+//	{"type": "logistic_regression", "name": "...", "bias": -1.5,
+//	 "weights": {"kind:cycles": 1.2, "max_betweenness": 3.0, ...},
+//	 "calibration": {"a": 1, "b": 0}, "severity_thresholds": {"high": 0.75, "medium": 0.45}}
+type Logistic struct {
+	Type        string             `json:"type"`
+	ModelName   string             `json:"name,omitempty"`
+	Bias        float64            `json:"bias"`
+	Weights     map[string]float64 `json:"weights"`
+	Calibration *Calibration       `json:"calibration,omitempty"`
+	Thresholds  SeverityThresholds `json:"severity_thresholds"`
+}
+
+func (l *Logistic) Name() string {
+	if l.ModelName != "" {
+		return l.ModelName
+	}
+	return TypeLogistic
+}
+
+// Predict returns the calibrated probability and the severity bucket it falls into.
+func (l *Logistic) Predict(m *features.GraphMetrics, d domain.Detection) (Prediction, error) {
+	z := l.Bias
+	for name, v := range DetectionFeatures(m, d) {
+		z += l.Weights[name] * v
+	}
+	if l.Calibration != nil {
+		z = l.Calibration.A*z + l.Calibration.B
+	}
+	p := 1 / (1 + math.Exp(-z))
+
+	sev := domain.SeverityLow
+	switch {
+	case p >= l.Thresholds.High:
+		sev = domain.SeverityHigh
+	case p >= l.Thresholds.Medium:
+		sev = domain.SeverityMedium
+	}
+	return Prediction{Confidence: p, Severity: sev}, nil
+}
+
+func (l *Logistic) validate() error {
+	if len(l.Weights) == 0 {
+		return fmt.Errorf("logistic model: weights are empty")
+	}
+	for name, w := range l.Weights {
+		if math.IsNaN(w) || math.IsInf(w, 0) {
+			return fmt.Errorf("logistic model: weight %q is not finite", name)
+		}
+	}
+	if l.Calibration != nil && l.Calibration.A == 0 {
+		return fmt.Errorf("logistic model: calibration.a must be non-zero")
+	}
+	if l.Thresholds.High == 0 && l.Thresholds.Medium == 0 {
+		l.Thresholds = SeverityThresholds{High: 0.75, Medium: 0.45}
+	}
+	if l.Thresholds.Medium < 0 || l.Thresholds.High > 1 || l.Thresholds.Medium > l.Thresholds.High {
+		return fmt.Errorf("logistic model: need 0 <= medium <= high <= 1 in severity_thresholds")
+	}
+	return nil
+}
+
+// LoadJSON parses a weights file. Only TypeLogistic is supported.
+func LoadJSON(b []byte) (Model, error) {
+	var head struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(b, &head); err != nil {
+		return nil, fmt.Errorf("model weights: %w", err)
+	}
+	switch head.Type {
+	case TypeLogistic, "":
+		var l Logistic
+		if err := json.Unmarshal(b, &l); err != nil {
+			return nil, fmt.Errorf("model weights: %w", err)
+		}
+		if err := l.validate(); err != nil {
+			return nil, err
+		}
+		return &l, nil
+	default:
+		return nil, fmt.Errorf("model weights: unsupported type %q", head.Type)
+	}
+}
+
+// LoadFile reads a weights file from disk.
+func LoadFile(path string) (Model, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return LoadJSON(b)
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"math"
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ml/features"
+)
+
+func TestLogistic_LoadAndPredict(t *testing.T) {
+	m, err := LoadFile("testdata/logistic.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Name() != "test-lr" {
+		t.Fatalf("name: %s", m.Name())
+	}
+
+	gm := &features.GraphMetrics{Nodes: []features.NodeMetrics{
+		{ID: "DATABASE:db", FanIn: 3},
+		{ID: "SERVICE:a", FanIn: 1},
+	}}
+	det := domain.Detection{Kind: domain.APSharedDatabase, Severity: domain.SeverityMedium, Nodes: []string{"DATABASE:db", "SERVICE:a"}}
+	p, err := m.Predict(gm, det)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// z = -2 + 2.5 + 0.4*2 + 0.3*3 = 2.2
+	if want := 1 / (1 + math.Exp(-2.2)); math.Abs(p.Confidence-want) > 1e-9 {
+		t.Fatalf("confidence: want %v, got %v", want, p.Confidence)
+	}
+	if p.Severity != domain.SeverityHigh {
+		t.Fatalf("severity: %s", p.Severity)
+	}
+}
+
+func TestLoadJSON_Rejects(t *testing.T) {
+	for _, b := range []string{
+		`{"type": "gbdt", "weights": {"severity": 1}}`,
+		`{"type": "logistic_regression", "weights": {}}`,
+		`{"type": "logistic_regression", "weights": {"severity": 1}, "severity_thresholds": {"high": 0.3, "medium": 0.6}}`,
+	} {
+		if _, err := LoadJSON([]byte(b)); err == nil {
+			t.Errorf("expected error for %s", b)
+		}
+	}
+}
@@ -4,16 +4,84 @@ import (
 	"sort"
 
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ml/features"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ml/model"
 )
 
-func PrioritizeDetections(dets []domain.Detection) []domain.Detection {
-	sort.SliceStable(dets, func(i, j int) bool {
-		si := ScoreDetection(dets[i])
-		sj := ScoreDetection(dets[j])
-		if si != sj {
-			return si > sj
+// ModelEvidenceKey is the Detection.Evidence key PrioritizeDetections writes a model's view under:
+// name, confidence, severity (the model's bucket; Detection.Severity is left as the rule set it)
+// and the blended score.
+const ModelEvidenceKey = "model"
+
+// Option tunes PrioritizeDetections.
+type Option func(*prioritizeOpts)
+
+type prioritizeOpts struct {
+	model   model.Model
+	metrics *features.GraphMetrics
+	blend   float64
+}
+
+// WithModel blends a model's confidence into the ranking: score = (1-blend)*ScoreDetection + blend*100*confidence.
+// blend is clamped to [0, 1]; detections the model fails on keep their fixed score.
+func WithModel(m model.Model, metrics *features.GraphMetrics, blend float64) Option {
+	return func(o *prioritizeOpts) {
+		if blend < 0 {
+			blend = 0
+		}
+		if blend > 1 {
+			blend = 1
+		}
+		o.model, o.metrics, o.blend = m, metrics, blend
+	}
+}
+
+// score returns d's ranking score and, when the model predicted one, its prediction.
+func (o *prioritizeOpts) score(d domain.Detection) (float64, *model.Prediction) {
+	fixed := float64(ScoreDetection(d))
+	if o.model == nil {
+		return fixed, nil
+	}
+	p, err := o.model.Predict(o.metrics, d)
+	if err != nil {
+		return fixed, nil
+	}
+	return (1-o.blend)*fixed + o.blend*100*p.Confidence, &p
+}
+
+// PrioritizeDetections sorts dets in place by descending score (ScoreDetection, optionally blended
+// with a model). With a model, each detection it predicts gets Evidence[ModelEvidenceKey].
+func PrioritizeDetections(dets []domain.Detection, opts ...Option) []domain.Detection {
+	var o prioritizeOpts
+	for _, opt := range opts {
+		opt(&o)
+	}
+	type scored struct {
+		d domain.Detection
+		s float64
+	}
+	tmp := make([]scored, len(dets))
+	for i := range dets {
+		s, p := o.score(dets[i])
+		tmp[i] = scored{dets[i], s}
+		if p == nil {
+			continue
+		}
+		ev := make(domain.Attrs, len(dets[i].Evidence)+1)
+		for k, v := range dets[i].Evidence {
+			ev[k] = v
+		}
+		ev[ModelEvidenceKey] = domain.Attrs{"name": o.model.Name(), "confidence": p.Confidence, "severity": p.Severity, "score": s}
+		tmp[i].d.Evidence = ev
+	}
+	sort.SliceStable(tmp, func(i, j int) bool {
+		if tmp[i].s != tmp[j].s {
+			return tmp[i].s > tmp[j].s
 		}
-		return dets[i].Severity > dets[j].Severity
+		return tmp[i].d.Severity > tmp[j].d.Severity
 	})
+	for i := range tmp {
+		dets[i] = tmp[i].d
+	}
 	return dets
 }
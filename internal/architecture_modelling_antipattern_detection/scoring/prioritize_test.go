@@ -0,0 +1,46 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ml/features"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ml/model"
+)
+
+func TestPrioritizeDetections_BlendsModel(t *testing.T) {
+	dets := func() []domain.Detection {
+		return []domain.Detection{
+			{Kind: domain.APCycles, Severity: domain.SeverityHigh, Nodes: []string{"a", "b"}},
+			{Kind: domain.APSharedDatabase, Severity: domain.SeverityMedium, Nodes: []string{"db", "a"}},
+		}
+	}
+
+	fixed := PrioritizeDetections(dets())
+	if fixed[0].Kind != domain.APCycles {
+		t.Fatalf("fixed weights should rank cycles first, got %s", fixed[0].Kind)
+	}
+
+	m, err := model.LoadJSON([]byte(`{"type":"logistic_regression","bias":-3,
+		"weights":{"kind:shared_database":6,"kind:cycles":-2}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	blended := PrioritizeDetections(dets(), WithModel(m, &features.GraphMetrics{}, 0.8))
+	if blended[0].Kind != domain.APSharedDatabase {
+		t.Fatalf("model should lift shared_database to the top, got %s", blended[0].Kind)
+	}
+
+	note, ok := blended[0].Evidence[ModelEvidenceKey].(domain.Attrs)
+	if !ok || note["severity"] != domain.SeverityHigh || note["confidence"].(float64) < 0.75 {
+		t.Fatalf("model evidence: %+v", blended[0].Evidence)
+	}
+
+	kept := PrioritizeDetections(dets(), WithModel(m, nil, 0))
+	if kept[0].Kind != domain.APCycles {
+		t.Fatalf("blend=0 must keep the fixed order, got %s", kept[0].Kind)
+	}
+	if s := kept[0].Evidence[ModelEvidenceKey].(domain.Attrs)["score"]; s != float64(ScoreDetection(kept[0])) {
+		t.Fatalf("blend=0 must keep the fixed score, got %v", s)
+	}
+}
@@ -13,6 +13,16 @@ func ScoreDetection(d domain.Detection) int {
 	return base + kind + size
 }
 
+// RiskScore is the total ScoreDetection of dets, the architecture-level risk compared
+// between versions.
+func RiskScore(dets []domain.Detection) int {
+	total := 0
+	for _, d := range dets {
+		total += ScoreDetection(d)
+	}
+	return total
+}
+
 func severityWeight(s domain.Severity) int {
 	switch s {
 	case domain.SeverityHigh:
@@ -44,6 +54,16 @@ func kindWeight(k domain.AntiPatternKind) int {
 		return 20
 	case domain.APUIOrchestrator:
 		return 17
+	case domain.APChattyService:
+		return 19
+	case domain.APSinglePointOfFailure:
+		return 21
+	case domain.APOrphanEventTopic:
+		return 12
+	case domain.APSelfConsumingService:
+		return 13
+	case domain.APEventLoop:
+		return 23
 	default:
 		return 10
 	}
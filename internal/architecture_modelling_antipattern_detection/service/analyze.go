@@ -1,36 +1,46 @@
 package service
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection"
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
+	specpkg "github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/spec"
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/suggestion"
 	_ "github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/suggestion/strategies"
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/versioning"
 )
 
-// cloneGraphDeep copies the graph so suggestion strategies can mutate without corrupting origAnalysis.Graph.
-func cloneGraphDeep(g *domain.Graph) *domain.Graph {
-	if g == nil {
-		return domain.NewGraph()
+// detectWith re-runs detection on a spec being fixed with the detector config the suggestions
+// were analyzed with (nil for built-in defaults).
+func detectWith(cfg *detection.DetectorConfig) suggestion.DetectFunc {
+	return func(ys *parser.YSpec) (*domain.Graph, []domain.Detection, error) {
+		c, err := specpkg.CloneSpec(ys)
+		if err != nil {
+			return nil, nil, err
+		}
+		g, sups, err := prepareSpec(c)
+		if err != nil {
+			return nil, nil, err
+		}
+		kept, _, _, err := runDetectors(g, nil, cfg, sups)
+		if err != nil {
+			return nil, nil, err
+		}
+		return g, kept, nil
 	}
-	b, err := json.Marshal(g)
-	if err != nil {
-		return domain.NewGraph()
-	}
-	var out domain.Graph
-	if err := json.Unmarshal(b, &out); err != nil {
-		return domain.NewGraph()
-	}
-	out.RebuildOutIn()
-	return &out
 }
 
 type SuggestPreviewResult struct {
 	Analysis     *Result                 `json:"analysis" yaml:"analysis"`
+	// Suggestions keep the severity order that "idx:N" selections refer to; each carries an
+	// Impact estimate whose Rank orders them by benefit.
 	Suggestions  []suggestion.Suggestion `json:"suggestions" yaml:"suggestions"`
+	// Plan is the order the selected suggestions (all when none are selected) would be applied
+	// in, with the fixes that touch the same services; nothing is applied yet.
+	Plan []suggestion.PlannedFix `json:"plan" yaml:"plan"`
 }
 
 type ApplySuggestionsResult struct {
@@ -38,41 +48,57 @@ type ApplySuggestionsResult struct {
 	OriginalSuggestions []suggestion.Suggestion  `json:"original_suggestions" yaml:"original_suggestions"`
 
 	FixedYAML           string                  `json:"fixed_yaml" yaml:"fixed_yaml"`
+	// FixedDiff is a unified diff from the submitted YAML to FixedYAML ("" when nothing was fixed).
+	FixedDiff           string                  `json:"fixed_diff" yaml:"fixed_diff"`
 	FixedVersion        *versioning.Version     `json:"fixed_version" yaml:"fixed_version"`
 	FixedAnalysis       *Result                 `json:"fixed_analysis" yaml:"fixed_analysis"`
 	AppliedFixes        []suggestion.Suggestion `json:"applied_fixes" yaml:"applied_fixes"`
+	// Plan is the order the selected fixes ran in, with the skipped ones and why.
+	Plan []suggestion.PlannedFix `json:"plan" yaml:"plan"`
 }
 
-func PreviewSuggestionsYAMLBytes(yamlBytes []byte, outBaseDir, title string) (*SuggestPreviewResult, error) {
+// PreviewSuggestionsYAMLBytes analyzes yamlBytes with cfg (nil for built-in defaults) and returns
+// the suggestions with their impact and the fix plan for the selected ones.
+func PreviewSuggestionsYAMLBytes(yamlBytes []byte, outBaseDir, title string, selectedSuggestionIDs []string, cfg *detection.DetectorConfig) (*SuggestPreviewResult, error) {
 	dotBin := os.Getenv("DOT_BIN")
-	analysis, err := AnalyzeYAMLBytes(yamlBytes, outBaseDir, title, dotBin)
+	analysis, err := AnalyzeYAMLBytes(yamlBytes, outBaseDir, title, dotBin, cfg)
 	if err != nil {
 		return nil, err
 	}
 
 	sugs := suggestion.BuildSuggestions(analysis.Graph, analysis.Detections)
+	if err := suggestion.EstimateImpacts(yamlBytes, analysis.Graph, analysis.Detections, sugs, detectWith(cfg)); err != nil {
+		return nil, err
+	}
+	var selectedMap map[string]bool
+	if len(selectedSuggestionIDs) > 0 {
+		selectedMap = suggestion.ResolveSelectedIDs(selectedSuggestionIDs, suggestion.OrderedDetectionKeys(analysis.Detections))
+	}
 	return &SuggestPreviewResult{
 		Analysis:    analysis,
 		Suggestions: sugs,
+		Plan:        suggestion.PlanFixes(analysis.Graph, analysis.Detections, selectedMap),
 	}, nil
 }
 
-func PreviewSuggestionsYAMLString(yamlText, outBaseDir, title string) (*SuggestPreviewResult, error) {
-	return PreviewSuggestionsYAMLBytes([]byte(yamlText), outBaseDir, title)
+func PreviewSuggestionsYAMLString(yamlText, outBaseDir, title string, selectedSuggestionIDs []string, cfg *detection.DetectorConfig) (*SuggestPreviewResult, error) {
+	return PreviewSuggestionsYAMLBytes([]byte(yamlText), outBaseDir, title, selectedSuggestionIDs, cfg)
 }
 
-func PreviewSuggestionsYAMLFile(path, outBaseDir, title string) (*SuggestPreviewResult, error) {
+func PreviewSuggestionsYAMLFile(path, outBaseDir, title string, selectedSuggestionIDs []string, cfg *detection.DetectorConfig) (*SuggestPreviewResult, error) {
 	b, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
-	return PreviewSuggestionsYAMLBytes(b, outBaseDir, title)
+	return PreviewSuggestionsYAMLBytes(b, outBaseDir, title, selectedSuggestionIDs, cfg)
 }
 
-func ApplySuggestionsYAMLBytes(jobID string, yamlBytes []byte, outBaseDir, title string, selectedSuggestionIDs []string) (*ApplySuggestionsResult, error) {
+// ApplySuggestionsYAMLBytes applies the selected fixes to yamlBytes and analyzes the original
+// and the fixed spec with cfg (nil for built-in defaults).
+func ApplySuggestionsYAMLBytes(jobID string, yamlBytes []byte, outBaseDir, title string, selectedSuggestionIDs []string, cfg *detection.DetectorConfig) (*ApplySuggestionsResult, error) {
 	dotBin := os.Getenv("DOT_BIN")
 
-	origAnalysis, err := AnalyzeYAMLBytes(yamlBytes, outBaseDir, title, dotBin)
+	origAnalysis, err := AnalyzeYAMLBytes(yamlBytes, outBaseDir, title, dotBin, cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -82,8 +108,8 @@ func ApplySuggestionsYAMLBytes(jobID string, yamlBytes []byte, outBaseDir, title
 	orderedKeys := suggestion.OrderedDetectionKeys(origAnalysis.Detections)
 	selectedMap := suggestion.ResolveSelectedIDs(selectedSuggestionIDs, orderedKeys)
 
-	graphForApply := cloneGraphDeep(origAnalysis.Graph)
-	fixed, applied, err := suggestion.ApplyFixesYAMLBytesFiltered(yamlBytes, graphForApply, origAnalysis.Detections, selectedMap)
+	graphForApply := origAnalysis.Graph.Clone()
+	fixed, applied, plan, err := suggestion.ApplyFixPlan(yamlBytes, graphForApply, origAnalysis.Detections, selectedMap, detectWith(cfg))
 	if err != nil {
 		return nil, err
 	}
@@ -96,15 +122,29 @@ func ApplySuggestionsYAMLBytes(jobID string, yamlBytes []byte, outBaseDir, title
 			FixedVersion:        nil,
 			FixedAnalysis:       origAnalysis,
 			AppliedFixes:        []suggestion.Suggestion{},
+			Plan:                plan,
 		}, nil
 	}
 
+	diff, err := specpkg.UnifiedDiff("original.yaml", "fixed.yaml", yamlBytes, fixed)
+	if err != nil {
+		return nil, err
+	}
+
 	ver, err := versioning.CreateVersion(jobID, outBaseDir, "auto_fix", fixed)
 	if err != nil {
 		return nil, fmt.Errorf("versioning: %w", err)
 	}
 
-	fixedAnalysis, err := AnalyzeYAMLBytesToDir(fixed, ver.Dir, title, dotBin)
+	patches := make([]versioning.FixPatch, 0, len(applied))
+	for _, a := range applied {
+		patches = append(patches, versioning.FixPatch{ID: a.ID, Kind: a.Kind, Title: a.Title, Ops: a.AutoFixPatch})
+	}
+	if err := versioning.WritePatches(ver, patches); err != nil {
+		return nil, fmt.Errorf("versioning: %w", err)
+	}
+
+	fixedAnalysis, err := AnalyzeYAMLBytesToDir(fixed, ver.Dir, title, dotBin, cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -113,20 +153,22 @@ func ApplySuggestionsYAMLBytes(jobID string, yamlBytes []byte, outBaseDir, title
 		OriginalAnalysis:    origAnalysis,
 		OriginalSuggestions: origSugs,
 		FixedYAML:           string(fixed),
+		FixedDiff:           diff,
 		FixedVersion:        ver,
 		FixedAnalysis:       fixedAnalysis,
 		AppliedFixes:        applied,
+		Plan:                plan,
 	}, nil
 }
 
-func ApplySuggestionsYAMLString(jobID string, yamlText, outBaseDir, title string, selectedSuggestionIDs []string) (*ApplySuggestionsResult, error) {
-	return ApplySuggestionsYAMLBytes(jobID, []byte(yamlText), outBaseDir, title, selectedSuggestionIDs)
+func ApplySuggestionsYAMLString(jobID string, yamlText, outBaseDir, title string, selectedSuggestionIDs []string, cfg *detection.DetectorConfig) (*ApplySuggestionsResult, error) {
+	return ApplySuggestionsYAMLBytes(jobID, []byte(yamlText), outBaseDir, title, selectedSuggestionIDs, cfg)
 }
 
-func ApplySuggestionsYAMLFile(jobID string, path, outBaseDir, title string, selectedSuggestionIDs []string) (*ApplySuggestionsResult, error) {
+func ApplySuggestionsYAMLFile(jobID string, path, outBaseDir, title string, selectedSuggestionIDs []string, cfg *detection.DetectorConfig) (*ApplySuggestionsResult, error) {
 	b, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
-	return ApplySuggestionsYAMLBytes(jobID, b, outBaseDir, title, selectedSuggestionIDs)
+	return ApplySuggestionsYAMLBytes(jobID, b, outBaseDir, title, selectedSuggestionIDs, cfg)
 }
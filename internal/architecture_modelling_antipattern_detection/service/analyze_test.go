@@ -0,0 +1,65 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+const pingPongYAML = `# cart and catalog call each other
+services:
+  - name: cart # owned by the web team
+    type: service
+  - name: catalog
+    type: service
+dependencies:
+  - from: cart
+    to: catalog
+    kind: rest
+    sync: true
+  - from: catalog
+    to: cart
+    kind: rest
+    sync: true
+`
+
+func TestApplySuggestions_MinimalYAMLEdit(t *testing.T) {
+	t.Setenv("AMG_APD_SVG_RENDERER", "")
+	t.Setenv("DOT_BIN", "/nonexistent/dot")
+	dir := t.TempDir()
+	prev, err := PreviewSuggestionsYAMLBytes([]byte(pingPongYAML), dir, "t", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, s := range prev.Suggestions {
+		if s.Kind == domain.APPingPongDependency {
+			ids = append(ids, s.ID)
+		}
+	}
+	if len(ids) == 0 {
+		t.Fatalf("expected a ping-pong suggestion, got %+v", prev.Suggestions)
+	}
+
+	res, err := ApplySuggestionsYAMLBytes("job", []byte(pingPongYAML), dir, "t", ids, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.AppliedFixes) == 0 {
+		t.Fatal("expected the ping-pong fix to apply")
+	}
+	for _, keep := range []string{"# cart and catalog call each other", "# owned by the web team"} {
+		if !strings.Contains(res.FixedYAML, keep) {
+			t.Errorf("fixed yaml lost %q:\n%s", keep, res.FixedYAML)
+		}
+	}
+	if !strings.HasPrefix(res.FixedDiff, "--- original.yaml\n+++ fixed.yaml\n") || !strings.Contains(res.FixedDiff, "\n-  - from: ") {
+		t.Errorf("diff:\n%s", res.FixedDiff)
+	}
+	for _, l := range strings.Split(res.FixedDiff, "\n") {
+		if strings.HasPrefix(l, "+") && !strings.HasPrefix(l, "+++") {
+			t.Errorf("removing a dependency should not add lines, got %q", l)
+		}
+	}
+}
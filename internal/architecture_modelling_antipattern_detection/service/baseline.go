@@ -0,0 +1,64 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/suggestion"
+)
+
+// BaselineReport compares an analysis against a baseline (a previously accepted version).
+// Only New detections count as failures; Existing ones were already in the baseline and
+// Resolved ones are baseline detections that no longer appear.
+type BaselineReport struct {
+	BaselineVersionID string             `json:"baseline_version_id,omitempty" yaml:"baseline_version_id,omitempty"`
+	New               []domain.Detection `json:"new" yaml:"new"`
+	Existing          []domain.Detection `json:"existing" yaml:"existing"`
+	Resolved          []domain.Detection `json:"resolved" yaml:"resolved"`
+	Failed            bool               `json:"failed" yaml:"failed"`
+}
+
+// CompareToBaseline matches detections by suggestion.DetectionKey (kind + involved nodes).
+func CompareToBaseline(current, baseline []domain.Detection) *BaselineReport {
+	seen := make(map[string]bool, len(baseline))
+	for _, d := range baseline {
+		seen[suggestion.DetectionKey(d)] = true
+	}
+	rep := &BaselineReport{
+		New:      []domain.Detection{},
+		Existing: []domain.Detection{},
+		Resolved: []domain.Detection{},
+	}
+	still := make(map[string]bool, len(current))
+	for _, d := range current {
+		key := suggestion.DetectionKey(d)
+		still[key] = true
+		if seen[key] {
+			rep.Existing = append(rep.Existing, d)
+		} else {
+			rep.New = append(rep.New, d)
+		}
+	}
+	for _, d := range baseline {
+		if !still[suggestion.DetectionKey(d)] {
+			rep.Resolved = append(rep.Resolved, d)
+		}
+	}
+	rep.Failed = len(rep.New) > 0
+	return rep
+}
+
+// LoadBaselineFile reads the detections of a previous analysis.json (a serialized Result).
+func LoadBaselineFile(path string) ([]domain.Detection, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var res Result
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, fmt.Errorf("baseline %s: %w", path, err)
+	}
+	return res.Detections, nil
+}
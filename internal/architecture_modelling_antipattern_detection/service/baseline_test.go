@@ -0,0 +1,108 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+const cycleYAML = `
+services:
+  - name: orders
+  - name: billing
+dependencies:
+  - from: orders
+    to: billing
+    kind: rest
+    sync: true
+  - from: billing
+    to: orders
+    kind: rest
+    sync: true
+`
+
+func kinds(dets []domain.Detection) map[domain.AntiPatternKind]int {
+	out := map[domain.AntiPatternKind]int{}
+	for _, d := range dets {
+		out[d.Kind]++
+	}
+	return out
+}
+
+func TestAnalyze_MetadataSuppressions(t *testing.T) {
+	plain, _, err := AnalyzeYAMLBytesInMemory([]byte(cycleYAML), "t", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kinds(plain.Detections)[domain.APCycles] == 0 {
+		t.Fatalf("expected a cycle detection, got %+v", plain.Detections)
+	}
+
+	y := cycleYAML + `
+metadata:
+  suppressions:
+    - kind: cycles
+      nodes: [orders, SERVICE:billing]
+      reason: known, tracked in backlog
+      expires: 2999-01-01
+    - kind: ping_pong_dependency
+      reason: expired
+      expires: 2000-01-01
+`
+	res, _, err := AnalyzeYAMLBytesInMemory([]byte(y), "t", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kinds(res.Detections)[domain.APCycles] != 0 {
+		t.Fatalf("cycle should be suppressed, got %+v", res.Detections)
+	}
+	if kinds(res.Suppressed)[domain.APCycles] == 0 {
+		t.Fatalf("cycle should be listed as suppressed, got %+v", res.Suppressed)
+	}
+	if kinds(res.Detections)[domain.APPingPongDependency] != kinds(plain.Detections)[domain.APPingPongDependency] {
+		t.Fatalf("expired suppression must not apply")
+	}
+	for _, d := range res.Suppressed {
+		note, _ := d.Evidence["suppressed"].(domain.Attrs)
+		if note["reason"] != "known, tracked in backlog" {
+			t.Fatalf("expected suppression reason in evidence, got %#v", d.Evidence)
+		}
+	}
+}
+
+func TestAnalyze_InvalidSuppressionExpiry(t *testing.T) {
+	y := cycleYAML + `
+metadata:
+  suppressions:
+    - kind: cycles
+      expires: next week
+`
+	_, _, err := AnalyzeYAMLBytesInMemory([]byte(y), "t", "", nil)
+	if err == nil || !strings.Contains(err.Error(), "expires") {
+		t.Fatalf("expected expires error, got %v", err)
+	}
+}
+
+func TestCompareToBaseline(t *testing.T) {
+	a := domain.Detection{Kind: domain.APCycles, Nodes: []string{"SERVICE:a", "SERVICE:b"}}
+	b := domain.Detection{Kind: domain.APGodService, Nodes: []string{"SERVICE:hub"}}
+	c := domain.Detection{Kind: domain.APSharedDatabase, Nodes: []string{"DATABASE:db"}}
+
+	rep := CompareToBaseline([]domain.Detection{a, b}, []domain.Detection{b, c})
+	if len(rep.New) != 1 || rep.New[0].Kind != domain.APCycles {
+		t.Fatalf("new: %+v", rep.New)
+	}
+	if len(rep.Existing) != 1 || rep.Existing[0].Kind != domain.APGodService {
+		t.Fatalf("existing: %+v", rep.Existing)
+	}
+	if len(rep.Resolved) != 1 || rep.Resolved[0].Kind != domain.APSharedDatabase {
+		t.Fatalf("resolved: %+v", rep.Resolved)
+	}
+	if !rep.Failed {
+		t.Fatal("a new detection must fail the baseline check")
+	}
+	if CompareToBaseline([]domain.Detection{b}, []domain.Detection{b, c}).Failed {
+		t.Fatal("no new detections must pass")
+	}
+}
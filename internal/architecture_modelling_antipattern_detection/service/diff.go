@@ -0,0 +1,250 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/graph/export"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/scoring"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/suggestion"
+)
+
+// AttrChange is one attribute whose value differs; Before or After is nil when the attribute
+// was added or removed.
+type AttrChange struct {
+	Attr   string `json:"attr" yaml:"attr"`
+	Before any    `json:"before" yaml:"before"`
+	After  any    `json:"after" yaml:"after"`
+}
+
+type NodeDiff struct {
+	ID      string          `json:"id" yaml:"id"`
+	Name    string          `json:"name" yaml:"name"`
+	Kind    domain.NodeKind `json:"kind" yaml:"kind"`
+	Changes []AttrChange    `json:"changes,omitempty" yaml:"changes,omitempty"`
+}
+
+// EdgeDiff identifies an edge by endpoints and kind; Index is its position in the graph it
+// belongs to (the new graph, or the old one for removed edges).
+type EdgeDiff struct {
+	From    string          `json:"from" yaml:"from"`
+	To      string          `json:"to" yaml:"to"`
+	Kind    domain.EdgeKind `json:"kind" yaml:"kind"`
+	Index   int             `json:"index" yaml:"index"`
+	Changes []AttrChange    `json:"changes,omitempty" yaml:"changes,omitempty"`
+}
+
+// ArchitectureDiff is what changed from one version (before) to another (after).
+type ArchitectureDiff struct {
+	AddedNodes   []NodeDiff `json:"added_nodes" yaml:"added_nodes"`
+	RemovedNodes []NodeDiff `json:"removed_nodes" yaml:"removed_nodes"`
+	ChangedNodes []NodeDiff `json:"changed_nodes" yaml:"changed_nodes"`
+	AddedEdges   []EdgeDiff `json:"added_edges" yaml:"added_edges"`
+	RemovedEdges []EdgeDiff `json:"removed_edges" yaml:"removed_edges"`
+	ChangedEdges []EdgeDiff `json:"changed_edges" yaml:"changed_edges"`
+	// Detections are matched by suggestion.DetectionKey, counting repeats.
+	IntroducedDetections []domain.Detection `json:"introduced_detections" yaml:"introduced_detections"`
+	ResolvedDetections   []domain.Detection `json:"resolved_detections" yaml:"resolved_detections"`
+	UnchangedDetections  []domain.Detection `json:"unchanged_detections" yaml:"unchanged_detections"`
+	// Risk is scoring.RiskScore of each side's detections; RiskDelta > 0 means riskier.
+	RiskBefore int `json:"risk_before" yaml:"risk_before"`
+	RiskAfter  int `json:"risk_after" yaml:"risk_after"`
+	RiskDelta  int `json:"risk_delta" yaml:"risk_delta"`
+}
+
+// attrChanges compares attribute maps by their JSON encoding, so a graph decoded from storage
+// (numbers as float64) compares equal to a freshly built one.
+func attrChanges(before, after domain.Attrs) []AttrChange {
+	keys := map[string]bool{}
+	for k := range before {
+		keys[k] = true
+	}
+	for k := range after {
+		keys[k] = true
+	}
+	var out []AttrChange
+	for _, k := range parser.SortedSet(keys) {
+		b, inB := before[k]
+		a, inA := after[k]
+		if inB && inA && sameJSON(b, a) {
+			continue
+		}
+		out = append(out, AttrChange{Attr: k, Before: b, After: a})
+	}
+	return out
+}
+
+func sameJSON(a, b any) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
+
+// edgeKeys keys each edge by from|to|kind plus its ordinal among parallel edges, so the n-th
+// parallel edge of one version matches the n-th of the other. Nil edges keep an empty key.
+func edgeKeys(g *domain.Graph) (map[string]int, []string) {
+	idx := map[string]int{}
+	keys := make([]string, len(g.Edges))
+	seen := map[string]int{}
+	for i, e := range g.Edges {
+		if e == nil {
+			continue
+		}
+		base := fmt.Sprintf("%s|%s|%s", e.From, e.To, e.Kind)
+		keys[i] = fmt.Sprintf("%s#%d", base, seen[base])
+		seen[base]++
+		idx[keys[i]] = i
+	}
+	return idx, keys
+}
+
+func nodeDiff(n *domain.Node) NodeDiff {
+	return NodeDiff{ID: n.ID, Name: n.Name, Kind: n.Kind}
+}
+
+func edgeDiff(e *domain.Edge, i int) EdgeDiff {
+	return EdgeDiff{From: e.From, To: e.To, Kind: e.Kind, Index: i}
+}
+
+func emptyGraph(g *domain.Graph) *domain.Graph {
+	if g == nil {
+		return domain.NewGraph()
+	}
+	return g
+}
+
+// DiffArchitectures compares two analyzed versions. Nodes match by ID (a kind change is a
+// removal plus an addition); layout positions are not compared.
+func DiffArchitectures(before *domain.Graph, beforeDets []domain.Detection, after *domain.Graph, afterDets []domain.Detection) *ArchitectureDiff {
+	before, after = emptyGraph(before), emptyGraph(after)
+	d := &ArchitectureDiff{
+		AddedNodes: []NodeDiff{}, RemovedNodes: []NodeDiff{}, ChangedNodes: []NodeDiff{},
+		AddedEdges: []EdgeDiff{}, RemovedEdges: []EdgeDiff{}, ChangedEdges: []EdgeDiff{},
+		IntroducedDetections: []domain.Detection{}, ResolvedDetections: []domain.Detection{}, UnchangedDetections: []domain.Detection{},
+	}
+
+	ids := map[string]bool{}
+	for id := range before.Nodes {
+		ids[id] = true
+	}
+	for id := range after.Nodes {
+		ids[id] = true
+	}
+	for _, id := range parser.SortedSet(ids) {
+		b, a := before.Nodes[id], after.Nodes[id]
+		switch {
+		case b == nil:
+			d.AddedNodes = append(d.AddedNodes, nodeDiff(a))
+		case a == nil:
+			d.RemovedNodes = append(d.RemovedNodes, nodeDiff(b))
+		default:
+			changes := attrChanges(b.Attrs, a.Attrs)
+			if b.Name != a.Name {
+				changes = append([]AttrChange{{Attr: "name", Before: b.Name, After: a.Name}}, changes...)
+			}
+			if len(changes) > 0 {
+				nd := nodeDiff(a)
+				nd.Changes = changes
+				d.ChangedNodes = append(d.ChangedNodes, nd)
+			}
+		}
+	}
+
+	beforeIdx, beforeKeys := edgeKeys(before)
+	afterIdx, afterKeys := edgeKeys(after)
+	for i, k := range afterKeys {
+		e := after.Edges[i]
+		if e == nil {
+			continue
+		}
+		j, ok := beforeIdx[k]
+		if !ok {
+			d.AddedEdges = append(d.AddedEdges, edgeDiff(e, i))
+			continue
+		}
+		if changes := attrChanges(before.Edges[j].Attrs, e.Attrs); len(changes) > 0 {
+			ed := edgeDiff(e, i)
+			ed.Changes = changes
+			d.ChangedEdges = append(d.ChangedEdges, ed)
+		}
+	}
+	for i, k := range beforeKeys {
+		if before.Edges[i] == nil {
+			continue
+		}
+		if _, ok := afterIdx[k]; !ok {
+			d.RemovedEdges = append(d.RemovedEdges, edgeDiff(before.Edges[i], i))
+		}
+	}
+
+	// Detections are multisets: two identical findings before and one after leave one resolved.
+	beforeCount := map[string]int{}
+	for _, det := range beforeDets {
+		beforeCount[suggestion.DetectionKey(det)]++
+	}
+	afterCount := map[string]int{}
+	for _, det := range afterDets {
+		key := suggestion.DetectionKey(det)
+		afterCount[key]++
+		if beforeCount[key] > 0 {
+			beforeCount[key]--
+			d.UnchangedDetections = append(d.UnchangedDetections, det)
+		} else {
+			d.IntroducedDetections = append(d.IntroducedDetections, det)
+		}
+	}
+	for _, det := range beforeDets {
+		key := suggestion.DetectionKey(det)
+		if afterCount[key] > 0 {
+			afterCount[key]--
+		} else {
+			d.ResolvedDetections = append(d.ResolvedDetections, det)
+		}
+	}
+
+	d.RiskBefore = scoring.RiskScore(beforeDets)
+	d.RiskAfter = scoring.RiskScore(afterDets)
+	d.RiskDelta = d.RiskAfter - d.RiskBefore
+	return d
+}
+
+// DiffGraph merges both versions for an annotated rendering: the after graph plus the removed
+// nodes and edges, with marks for export.ToDiffDOT / ToDiffSVG.
+func DiffGraph(before, after *domain.Graph, d *ArchitectureDiff) (*domain.Graph, export.DiffMarks) {
+	before, after = emptyGraph(before), emptyGraph(after)
+	g := domain.NewGraph()
+	marks := export.DiffMarks{Nodes: map[string]export.DiffStatus{}, Edges: map[int]export.DiffStatus{}}
+	for _, n := range after.Nodes {
+		g.AddNode(n)
+	}
+	for _, n := range d.RemovedNodes {
+		g.AddNode(before.Nodes[n.ID])
+		marks.Nodes[n.ID] = export.DiffRemoved
+	}
+	for _, n := range d.AddedNodes {
+		marks.Nodes[n.ID] = export.DiffAdded
+	}
+	for _, n := range d.ChangedNodes {
+		marks.Nodes[n.ID] = export.DiffChanged
+	}
+	for _, e := range after.Edges {
+		if e == nil {
+			g.Edges = append(g.Edges, nil) // keeps the indices the diff refers to
+			continue
+		}
+		g.AddEdge(e)
+	}
+	for _, e := range d.AddedEdges {
+		marks.Edges[e.Index] = export.DiffAdded
+	}
+	for _, e := range d.ChangedEdges {
+		marks.Edges[e.Index] = export.DiffChanged
+	}
+	for _, e := range d.RemovedEdges {
+		marks.Edges[len(g.Edges)] = export.DiffRemoved
+		g.AddEdge(before.Edges[e.Index])
+	}
+	return g, marks
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/graph/export"
+)
+
+func TestDiffArchitectures(t *testing.T) {
+	before, _, err := AnalyzeYAMLBytesInMemory([]byte(cycleYAML+`  - from: orders
+    to: legacy
+    kind: rest
+    sync: true
+`), "before", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, _, err := AnalyzeYAMLBytesInMemory([]byte(`
+services:
+  - name: orders
+  - name: billing
+  - name: stock
+dependencies:
+  - from: orders
+    to: billing
+    kind: rest
+    sync: false
+  - from: orders
+    to: stock
+    kind: grpc
+    sync: true
+`), "after", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The stored side goes through JSON, as in CompareVersions.
+	var stored domain.Graph
+	b, _ := json.Marshal(before.Graph)
+	if err := json.Unmarshal(b, &stored); err != nil {
+		t.Fatal(err)
+	}
+	stored.RebuildOutIn()
+
+	d := DiffArchitectures(&stored, before.Detections, after.Graph, after.Detections)
+	if len(d.AddedNodes) != 1 || d.AddedNodes[0].ID != "SERVICE:stock" {
+		t.Fatalf("added nodes: %+v", d.AddedNodes)
+	}
+	if len(d.RemovedNodes) != 1 || d.RemovedNodes[0].ID != "SERVICE:legacy" {
+		t.Fatalf("removed nodes: %+v", d.RemovedNodes)
+	}
+	if len(d.AddedEdges) != 1 || d.AddedEdges[0].To != "SERVICE:stock" {
+		t.Fatalf("added edges: %+v", d.AddedEdges)
+	}
+	if len(d.RemovedEdges) != 2 {
+		t.Fatalf("expected billing->orders and orders->legacy removed, got %+v", d.RemovedEdges)
+	}
+	if len(d.ChangedEdges) != 1 || len(d.ChangedEdges[0].Changes) != 1 || d.ChangedEdges[0].Changes[0].Attr != "sync" {
+		t.Fatalf("changed edges: %+v", d.ChangedEdges)
+	}
+	if len(d.ResolvedDetections) == 0 || kinds(d.ResolvedDetections)[domain.APCycles] == 0 {
+		t.Fatalf("the cycle should be resolved, got %+v", d.ResolvedDetections)
+	}
+	if d.RiskDelta >= 0 || d.RiskDelta != d.RiskAfter-d.RiskBefore {
+		t.Fatalf("risk should drop: before=%d after=%d delta=%d", d.RiskBefore, d.RiskAfter, d.RiskDelta)
+	}
+
+	g, marks := DiffGraph(&stored, after.Graph, d)
+	if g.Nodes["SERVICE:legacy"] == nil || marks.Nodes["SERVICE:legacy"] != export.DiffRemoved || marks.Nodes["SERVICE:stock"] != export.DiffAdded {
+		t.Fatalf("diff graph should keep removed nodes and mark both sides: %+v", marks)
+	}
+	if len(g.Edges) != len(after.Graph.Edges)+2 {
+		t.Fatalf("diff graph edges = %d", len(g.Edges))
+	}
+	dot := export.ToDiffDOT(g, "diff", marks)
+	if !strings.Contains(dot, "style=dashed") || !strings.Contains(dot, "#1e8449") {
+		t.Fatalf("dot should show removals dashed and additions green:\n%s", dot)
+	}
+}
+
+func TestDiffArchitectures_RepeatedDetectionsAndNilEdges(t *testing.T) {
+	g := domain.NewGraph()
+	g.AddNode(&domain.Node{ID: "SERVICE:a", Name: "a", Kind: domain.NodeService})
+	g.Edges = append(g.Edges, nil)
+	det := domain.Detection{Kind: domain.APGodService, Severity: domain.SeverityHigh, Nodes: []string{"SERVICE:a"}}
+
+	d := DiffArchitectures(g, []domain.Detection{det, det}, g, []domain.Detection{det})
+	if len(d.UnchangedDetections) != 1 || len(d.ResolvedDetections) != 1 || len(d.IntroducedDetections) != 0 {
+		t.Fatalf("unchanged %d, resolved %d, introduced %d", len(d.UnchangedDetections), len(d.ResolvedDetections), len(d.IntroducedDetections))
+	}
+	if len(d.AddedEdges)+len(d.RemovedEdges)+len(d.ChangedEdges) != 0 {
+		t.Fatalf("edges: %+v", d)
+	}
+	if merged, _ := DiffGraph(g, g, d); len(merged.Edges) != 1 {
+		t.Fatalf("diff graph edges: %v", merged.Edges)
+	}
+}
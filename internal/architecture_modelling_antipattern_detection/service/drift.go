@@ -0,0 +1,162 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
+)
+
+// DriftEdge is one dependency that differs between the designed and observed architecture.
+// Sync flags are set when the edge exists on that side.
+type DriftEdge struct {
+	From         string `json:"from" yaml:"from"`
+	To           string `json:"to" yaml:"to"`
+	DesignedSync *bool  `json:"designed_sync,omitempty" yaml:"designed_sync,omitempty"`
+	ObservedSync *bool  `json:"observed_sync,omitempty" yaml:"observed_sync,omitempty"`
+}
+
+// DriftReport compares a designed architecture (a stored version) with one observed at runtime
+// (e.g. imported from traces). Missing entries are designed but never observed; Unexpected
+// entries are observed but not in the design.
+type DriftReport struct {
+	DesignedVersionID string      `json:"designed_version_id,omitempty" yaml:"designed_version_id,omitempty"`
+	MissingNodes      []string    `json:"missing_nodes" yaml:"missing_nodes"`
+	UnexpectedNodes   []string    `json:"unexpected_nodes" yaml:"unexpected_nodes"`
+	MissingEdges      []DriftEdge `json:"missing_edges" yaml:"missing_edges"`
+	UnexpectedEdges   []DriftEdge `json:"unexpected_edges" yaml:"unexpected_edges"`
+	SyncMismatches    []DriftEdge `json:"sync_mismatches" yaml:"sync_mismatches"`
+	Drifted           bool        `json:"drifted" yaml:"drifted"`
+}
+
+type driftLink struct {
+	from, to string // display names
+	sync     bool
+	// data marks a datastore access (READS/WRITES, or a call into a datastore as trace importers
+	// produce). Its sync flag is not meaningful and is never compared.
+	data bool
+}
+
+// syncPtr is the sync flag reported for l, nil for datastore accesses.
+func (l *driftLink) syncPtr() *bool {
+	if l.data {
+		return nil
+	}
+	return &l.sync
+}
+
+type driftSide struct {
+	nodes map[string]string        // lower name -> display name
+	edges map[[2]string]*driftLink // (lower from, lower to)
+}
+
+// driftIndex keys nodes by lowercased name, ignoring kind: an importer may not know whether a
+// peer is a service or an external system. Parallel edges collapse; a link is sync if any call
+// is, and a datastore access if any edge is one.
+func driftIndex(g *domain.Graph) driftSide {
+	s := driftSide{nodes: map[string]string{}, edges: map[[2]string]*driftLink{}}
+	if g == nil {
+		return s
+	}
+	for _, n := range g.Nodes {
+		s.nodes[strings.ToLower(n.Name)] = n.Name
+	}
+	name := func(id string) string {
+		if n := g.Nodes[id]; n != nil {
+			return n.Name
+		}
+		return id
+	}
+	for _, e := range g.Edges {
+		if e == nil {
+			continue
+		}
+		from, to := name(e.From), name(e.To)
+		k := [2]string{strings.ToLower(from), strings.ToLower(to)}
+		data := e.Kind != domain.EdgeCalls
+		if n := g.Nodes[e.To]; n != nil && n.Kind == domain.NodeDB {
+			data = true
+		}
+		sync, _ := e.Attrs["sync"].(bool)
+		if data {
+			sync = false
+		}
+		if l := s.edges[k]; l != nil {
+			l.sync = l.sync || sync
+			l.data = l.data || data
+			continue
+		}
+		s.edges[k] = &driftLink{from: from, to: to, sync: sync, data: data}
+	}
+	return s
+}
+
+func sortedKeys(m map[[2]string]*driftLink) [][2]string {
+	keys := make([][2]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	return keys
+}
+
+// CompareArchitectures reports nodes and dependencies present on only one side and
+// service-to-service calls whose sync/async mode differs. Datastore accesses match whatever
+// their edge kind (a designed READS edge and an observed call into the datastore are the same
+// dependency) and are not checked for sync.
+func CompareArchitectures(designed, observed *domain.Graph) *DriftReport {
+	d, o := driftIndex(designed), driftIndex(observed)
+	rep := &DriftReport{MissingNodes: []string{}, UnexpectedNodes: []string{}}
+	for k, name := range d.nodes {
+		if _, ok := o.nodes[k]; !ok {
+			rep.MissingNodes = append(rep.MissingNodes, name)
+		}
+	}
+	for k, name := range o.nodes {
+		if _, ok := d.nodes[k]; !ok {
+			rep.UnexpectedNodes = append(rep.UnexpectedNodes, name)
+		}
+	}
+	sort.Strings(rep.MissingNodes)
+	sort.Strings(rep.UnexpectedNodes)
+
+	rep.MissingEdges, rep.UnexpectedEdges, rep.SyncMismatches = []DriftEdge{}, []DriftEdge{}, []DriftEdge{}
+	for _, k := range sortedKeys(d.edges) {
+		dl, ol := d.edges[k], o.edges[k]
+		switch {
+		case ol == nil:
+			rep.MissingEdges = append(rep.MissingEdges, DriftEdge{From: dl.from, To: dl.to, DesignedSync: dl.syncPtr()})
+		case dl.data || ol.data:
+		case dl.sync != ol.sync:
+			rep.SyncMismatches = append(rep.SyncMismatches, DriftEdge{From: dl.from, To: dl.to, DesignedSync: &dl.sync, ObservedSync: &ol.sync})
+		}
+	}
+	for _, k := range sortedKeys(o.edges) {
+		if ol := o.edges[k]; d.edges[k] == nil {
+			rep.UnexpectedEdges = append(rep.UnexpectedEdges, DriftEdge{From: ol.from, To: ol.to, ObservedSync: ol.syncPtr()})
+		}
+	}
+	rep.Drifted = len(rep.MissingNodes)+len(rep.UnexpectedNodes)+len(rep.MissingEdges)+
+		len(rep.UnexpectedEdges)+len(rep.SyncMismatches) > 0
+	return rep
+}
+
+// LoadDesignFile reads an AMG/APD YAML spec as the designed side of CompareArchitectures.
+func LoadDesignFile(path string) (*domain.Graph, error) {
+	ys, err := parser.ParseYAML(path)
+	if err != nil {
+		return nil, fmt.Errorf("design %s: %w", path, err)
+	}
+	g, _, err := prepareSpec(ys)
+	if err != nil {
+		return nil, fmt.Errorf("design %s: %w", path, err)
+	}
+	return g, nil
+}
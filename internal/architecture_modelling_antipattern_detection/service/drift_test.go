@@ -0,0 +1,135 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/mapper"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
+)
+
+func TestCompareArchitectures(t *testing.T) {
+	designed := `
+services:
+  - name: orders
+  - name: billing
+  - name: legacy
+datastores:
+  - name: orders-db
+dependencies:
+  - from: orders
+    to: billing
+    kind: rest
+    sync: true
+  - from: orders
+    to: legacy
+    kind: rest
+    sync: true
+  - from: orders
+    to: orders-db
+    kind: db
+    sync: true
+`
+	observed := `
+services:
+  - name: Orders
+  - name: billing
+  - name: stripe
+    type: external_system
+datastores:
+  - name: orders-db
+dependencies:
+  - from: Orders
+    to: billing
+    kind: event
+    sync: false
+  - from: Orders
+    to: orders-db
+    kind: db
+    sync: true
+  - from: billing
+    to: stripe
+    kind: rest
+    sync: true
+`
+	graph := func(y string) *domain.Graph {
+		ys, err := parser.ParseYAMLString(y)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return mapper.ToGraph(ys)
+	}
+	rep := CompareArchitectures(graph(designed), graph(observed))
+	if !rep.Drifted {
+		t.Fatal("expected drift")
+	}
+	if len(rep.MissingNodes) != 1 || rep.MissingNodes[0] != "legacy" {
+		t.Fatalf("missing nodes: %v", rep.MissingNodes)
+	}
+	if len(rep.UnexpectedNodes) != 1 || rep.UnexpectedNodes[0] != "stripe" {
+		t.Fatalf("unexpected nodes: %v", rep.UnexpectedNodes)
+	}
+	if len(rep.MissingEdges) != 1 || rep.MissingEdges[0].To != "legacy" {
+		t.Fatalf("missing edges: %+v", rep.MissingEdges)
+	}
+	if len(rep.UnexpectedEdges) != 1 || rep.UnexpectedEdges[0].To != "stripe" {
+		t.Fatalf("unexpected edges: %+v", rep.UnexpectedEdges)
+	}
+	if len(rep.SyncMismatches) != 1 || rep.SyncMismatches[0].To != "billing" ||
+		!*rep.SyncMismatches[0].DesignedSync || *rep.SyncMismatches[0].ObservedSync {
+		t.Fatalf("sync mismatches: %+v", rep.SyncMismatches)
+	}
+
+	if CompareArchitectures(graph(designed), graph(designed)).Drifted {
+		t.Fatal("identical graphs must not drift")
+	}
+}
+
+func TestCompareArchitectures_DatastoreAccessIsNotSyncChecked(t *testing.T) {
+	// Designed datastore access as READS/WRITES edges, which carry no sync flag.
+	designed := `
+services:
+  - name: orders
+    databases:
+      reads: [orders-db]
+      writes: [orders-db]
+    calls:
+      - to: billing
+  - name: billing
+    databases:
+      reads: [billing-db]
+`
+	// Observed as trace importers produce it: sync dependencies of kind db.
+	observed := `
+services:
+  - name: orders
+  - name: billing
+datastores:
+  - name: orders-db
+  - name: billing-db
+dependencies:
+  - from: orders
+    to: billing
+    kind: rest
+    sync: true
+  - from: orders
+    to: orders-db
+    kind: db
+    sync: true
+  - from: billing
+    to: billing-db
+    kind: db
+    sync: true
+`
+	graph := func(y string) *domain.Graph {
+		ys, err := parser.ParseYAMLString(y)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return mapper.ToGraph(ys)
+	}
+	rep := CompareArchitectures(graph(designed), graph(observed))
+	if rep.Drifted {
+		t.Fatalf("expected no drift, got %+v", rep)
+	}
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection"
+	specpkg "github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/spec"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/suggestion"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/versioning"
+)
+
+type FixPatchResult struct {
+	// Patch is the patch that was applied: the inverse of the fix for an undo, the fix itself
+	// for a replay. It is stored with FixedVersion, so an undo can itself be undone.
+	Patch         versioning.FixPatch `json:"patch" yaml:"patch"`
+	FixedYAML     string              `json:"fixed_yaml" yaml:"fixed_yaml"`
+	FixedDiff     string              `json:"fixed_diff" yaml:"fixed_diff"`
+	FixedVersion  *versioning.Version `json:"fixed_version" yaml:"fixed_version"`
+	FixedAnalysis *Result             `json:"fixed_analysis" yaml:"fixed_analysis"`
+}
+
+// UndoFix reverts the fix fixID of an auto_fix version (one fix of a multi-fix apply) and
+// stores the result as an undo_fix version. It fails with spec.ErrOpConflict when a later fix
+// of the same apply built on it. The new version is analyzed with cfg (nil for built-in defaults).
+func UndoFix(jobID, outBaseDir, versionID, fixID, title string, cfg *detection.DetectorConfig) (*FixPatchResult, error) {
+	ver, err := versioning.ReadVersion(outBaseDir, jobID, versionID)
+	if err != nil {
+		return nil, err
+	}
+	fix, err := versioning.FindPatch(ver, fixID)
+	if err != nil {
+		return nil, err
+	}
+	yamlBytes, err := os.ReadFile(ver.YAMLPath)
+	if err != nil {
+		return nil, err
+	}
+	undo := *fix
+	undo.Ops = specpkg.InvertOps(fix.Ops)
+	return applyFixPatch(jobID, outBaseDir, "undo_fix", title, yamlBytes, undo, cfg)
+}
+
+// ReplayFix applies the fix fixID stored with versionID to yamlBytes (typically a newer version
+// of the architecture) and stores the result as a replay_fix version. It fails with
+// spec.ErrOpConflict when yamlBytes changed what the fix edits. The new version is analyzed
+// with cfg (nil for built-in defaults).
+func ReplayFix(jobID, outBaseDir, versionID, fixID string, yamlBytes []byte, title string, cfg *detection.DetectorConfig) (*FixPatchResult, error) {
+	ver, err := versioning.ReadVersion(outBaseDir, jobID, versionID)
+	if err != nil {
+		return nil, err
+	}
+	fix, err := versioning.FindPatch(ver, fixID)
+	if err != nil {
+		return nil, err
+	}
+	return applyFixPatch(jobID, outBaseDir, "replay_fix", title, yamlBytes, *fix, cfg)
+}
+
+func applyFixPatch(jobID, outBaseDir, label, title string, yamlBytes []byte, patch versioning.FixPatch, cfg *detection.DetectorConfig) (*FixPatchResult, error) {
+	fixed, err := suggestion.ApplyOpsYAMLBytes(yamlBytes, patch.Ops)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := specpkg.UnifiedDiff("original.yaml", "fixed.yaml", yamlBytes, fixed)
+	if err != nil {
+		return nil, err
+	}
+
+	ver, err := versioning.CreateVersion(jobID, outBaseDir, label, fixed)
+	if err != nil {
+		return nil, fmt.Errorf("versioning: %w", err)
+	}
+	if err := versioning.WritePatches(ver, []versioning.FixPatch{patch}); err != nil {
+		return nil, fmt.Errorf("versioning: %w", err)
+	}
+
+	fixedAnalysis, err := AnalyzeYAMLBytesToDir(fixed, ver.Dir, title, os.Getenv("DOT_BIN"), cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &FixPatchResult{
+		Patch:         patch,
+		FixedYAML:     string(fixed),
+		FixedDiff:     diff,
+		FixedVersion:  ver,
+		FixedAnalysis: fixedAnalysis,
+	}, nil
+}
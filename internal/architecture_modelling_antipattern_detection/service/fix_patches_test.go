@@ -0,0 +1,145 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	specpkg "github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/spec"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/versioning"
+)
+
+const twoPingPongsYAML = `services:
+  - name: cart
+    type: service
+  - name: catalog
+    type: service
+  - name: orders
+    type: service
+  - name: billing
+    type: service
+dependencies:
+  - from: cart
+    to: catalog
+    kind: rest
+    sync: true
+  - from: catalog
+    to: cart
+    kind: rest
+    sync: true
+  - from: orders
+    to: billing
+    kind: rest
+    sync: true
+  - from: billing
+    to: orders
+    kind: rest
+    sync: true
+`
+
+func TestUndoAndReplayFix(t *testing.T) {
+	t.Setenv("AMG_APD_SVG_RENDERER", "")
+	t.Setenv("DOT_BIN", "/nonexistent/dot")
+	dir := t.TempDir()
+	prev, err := PreviewSuggestionsYAMLBytes([]byte(twoPingPongsYAML), dir, "t", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, s := range prev.Suggestions {
+		if s.Kind == domain.APPingPongDependency {
+			ids = append(ids, s.ID)
+		}
+	}
+	res, err := ApplySuggestionsYAMLBytes("job", []byte(twoPingPongsYAML), dir, "t", ids, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.AppliedFixes) != 2 {
+		t.Fatalf("expected both ping-pong fixes, got %+v", res.AppliedFixes)
+	}
+	patches, err := versioning.ReadPatches(res.FixedVersion)
+	if err != nil || len(patches) != 2 {
+		t.Fatalf("patches = %+v, err = %v", patches, err)
+	}
+	var cartFix string
+	for _, p := range patches {
+		if len(p.Ops) == 0 {
+			t.Errorf("fix %s has no ops", p.ID)
+		}
+		if strings.Contains(p.ID, "cart") {
+			cartFix = p.ID
+		}
+	}
+
+	undone, err := UndoFix("job", dir, res.FixedVersion.VersionID, cartFix, "t", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if undone.FixedVersion.Label != "undo_fix" {
+		t.Errorf("label = %q", undone.FixedVersion.Label)
+	}
+	// The cart <-> catalog pair is back; the orders <-> billing fix stays applied.
+	if !strings.Contains(undone.FixedYAML, "from: cart") || !strings.Contains(undone.FixedYAML, "from: catalog") {
+		t.Errorf("undo did not restore cart <-> catalog:\n%s", undone.FixedYAML)
+	}
+	if strings.Contains(undone.FixedYAML, "from: orders") && strings.Contains(undone.FixedYAML, "from: billing") {
+		t.Errorf("undo reverted the other fix too:\n%s", undone.FixedYAML)
+	}
+
+	// Replay the fix on a newer version that added a service meanwhile.
+	newer := twoPingPongsYAML + "  - from: cart\n    to: search\n    kind: rest\n"
+	replayed, err := ReplayFix("job", dir, res.FixedVersion.VersionID, cartFix, []byte(newer), "t", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(replayed.FixedYAML, "to: search") {
+		t.Errorf("replay lost the newer dependency:\n%s", replayed.FixedYAML)
+	}
+	if replayed.FixedYAML == newer {
+		t.Error("replay changed nothing")
+	}
+	// Replaying it again on its own result conflicts: the removed dependency is gone.
+	if _, err := ReplayFix("job", dir, res.FixedVersion.VersionID, cartFix, []byte(replayed.FixedYAML), "t", nil); !errors.Is(err, specpkg.ErrOpConflict) {
+		t.Errorf("err = %v, want a conflict", err)
+	}
+}
+
+func TestReplayFix_RecordedOnSanitizedSpec(t *testing.T) {
+	t.Setenv("AMG_APD_SVG_RENDERER", "")
+	t.Setenv("DOT_BIN", "/nonexistent/dot")
+	dir := t.TempDir()
+	// Leaked graph prefixes and upper-case kinds are cleaned by SanitizeSpec before ops are replayed.
+	src := strings.NewReplacer("kind: rest", "kind: REST", "from: cart", "from: SERVICE:cart").Replace(twoPingPongsYAML)
+	prev, err := PreviewSuggestionsYAMLBytes([]byte(src), dir, "t", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, s := range prev.Suggestions {
+		if s.Kind == domain.APPingPongDependency {
+			ids = append(ids, s.ID)
+		}
+	}
+	res, err := ApplySuggestionsYAMLBytes("job", []byte(src), dir, "t", ids, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.FixedVersion == nil {
+		t.Fatalf("no fix applied: %+v", res.Plan)
+	}
+	patches, err := versioning.ReadPatches(res.FixedVersion)
+	if err != nil || len(patches) != 2 {
+		t.Fatalf("patches = %+v, err = %v", patches, err)
+	}
+	for _, p := range patches {
+		if ops := fmt.Sprintf("%+v", p.Ops); strings.Contains(ops, "REST") || strings.Contains(ops, "SERVICE:") {
+			t.Errorf("fix %s recorded unsanitized values: %s", p.ID, ops)
+		}
+		if _, err := ReplayFix("job", dir, res.FixedVersion.VersionID, p.ID, []byte(src), "t", nil); err != nil {
+			t.Errorf("replaying %s on its own source: %v", p.ID, err)
+		}
+	}
+}
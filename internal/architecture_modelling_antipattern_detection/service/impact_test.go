@@ -0,0 +1,79 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/suggestion"
+)
+
+func TestPreviewSuggestions_Impact(t *testing.T) {
+	t.Setenv("AMG_APD_SVG_RENDERER", "")
+	t.Setenv("DOT_BIN", "/nonexistent/dot")
+	prev, err := PreviewSuggestionsYAMLBytes([]byte(pingPongYAML), t.TempDir(), "t", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	impacts := map[domain.AntiPatternKind]*suggestion.Impact{}
+	for _, s := range prev.Suggestions {
+		if s.Impact == nil {
+			t.Fatalf("suggestion %s has no impact", s.ID)
+		}
+		impacts[s.Kind] = s.Impact
+	}
+
+	// Removing one leg of cart <-> catalog clears all three detections; making it async only
+	// clears the tight coupling, so it ranks last.
+	cyc, tc := impacts[domain.APCycles], impacts[domain.APTightCoupling]
+	if cyc == nil || tc == nil {
+		t.Fatalf("impacts = %+v", impacts)
+	}
+	if !cyc.AutoFix || cyc.RiskAfter != 0 || cyc.RiskDelta != -cyc.RiskBefore || len(cyc.Resolves) != 3 || len(cyc.Introduces) != 0 {
+		t.Errorf("cycles impact = %+v", *cyc)
+	}
+	if cyc.DependenciesChanged != 1 || cyc.ServicesAdded != 0 || cyc.Effort != suggestion.EffortLow {
+		t.Errorf("cycles effort = %+v", *cyc)
+	}
+	if tc.RiskDelta >= 0 || tc.RiskDelta <= cyc.RiskDelta || len(tc.Resolves) != 1 || tc.Rank != 3 {
+		t.Errorf("tight coupling impact = %+v", *tc)
+	}
+	if cyc.Rank != 1 || impacts[domain.APPingPongDependency].Rank != 2 {
+		t.Errorf("ranks: cycles %d, ping-pong %d", cyc.Rank, impacts[domain.APPingPongDependency].Rank)
+	}
+}
+
+// pingPongPairsYAML has n independent service pairs calling each other, so every pair is a cycle,
+// a ping-pong and a tight coupling with an auto-fix.
+func pingPongPairsYAML(n int) string {
+	var b strings.Builder
+	b.WriteString("services:\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "  - name: a%d\n    type: service\n  - name: b%d\n    type: service\n", i, i)
+	}
+	b.WriteString("dependencies:\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "  - from: a%d\n    to: b%d\n    kind: rest\n    sync: true\n", i, i)
+		fmt.Fprintf(&b, "  - from: b%d\n    to: a%d\n    kind: rest\n    sync: true\n", i, i)
+	}
+	return b.String()
+}
+
+func BenchmarkEstimateImpacts(b *testing.B) {
+	for _, n := range []int{5, 50} {
+		yamlBytes := []byte(pingPongPairsYAML(n))
+		res, _, err := AnalyzeYAMLBytesInMemory(yamlBytes, "bench", "", nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+		sugs := suggestion.BuildSuggestions(res.Graph, res.Detections)
+		b.Run(fmt.Sprintf("pairs=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := suggestion.EstimateImpacts(yamlBytes, res.Graph, res.Detections, sugs, detectWith(nil)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
+)
+
+// Importer converts a foreign architecture description (docker-compose, Kubernetes manifests,
+// OTLP traces, OpenAPI/AsyncAPI documents) into an AMG/APD spec.
+type Importer func(b []byte) (*parser.YSpec, error)
+
+// importers are keyed by format name; importAliases maps alternative names onto them.
+var importers = map[string]Importer{
+	"compose":    parser.ParseComposeBytes,
+	"kubernetes": parser.ParseK8sBytes,
+	"otlp":       parser.ParseOTLPBytes,
+	"api-docs":   parser.ParseAPIDocsBytes,
+}
+
+var importAliases = map[string]string{
+	"docker-compose": "compose",
+	"k8s":            "kubernetes",
+	"otel":           "otlp",
+	"openapi":        "api-docs",
+	"asyncapi":       "api-docs",
+}
+
+// ImportFormats lists the source formats ImportToYAML accepts, sorted.
+func ImportFormats() []string {
+	out := make([]string, 0, len(importers))
+	for name := range importers {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// ImportSpec converts b from the named format into a spec.
+func ImportSpec(format string, b []byte) (*parser.YSpec, error) {
+	name := strings.ToLower(strings.TrimSpace(format))
+	if a, ok := importAliases[name]; ok {
+		name = a
+	}
+	imp, ok := importers[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported import format %q (want one of %s)", format, strings.Join(ImportFormats(), ", "))
+	}
+	return imp(b)
+}
+
+// ImportToYAML converts b from the named format into AMG/APD YAML. The YAML is what gets
+// analyzed and stored, so an imported version can be edited and re-analyzed like any other.
+func ImportToYAML(format string, b []byte) ([]byte, error) {
+	ys, err := ImportSpec(format, b)
+	if err != nil {
+		return nil, err
+	}
+	out, err := yaml.Marshal(ys)
+	if err != nil {
+		return nil, fmt.Errorf("encode imported spec: %w", err)
+	}
+	return out, nil
+}
+
+// JoinDocuments concatenates YAML or JSON documents into one "---" separated stream, the input
+// of importers that take several documents (api-docs, kubernetes).
+func JoinDocuments(docs [][]byte) []byte {
+	var b bytes.Buffer
+	for i, d := range docs {
+		if i > 0 {
+			b.WriteString("\n---\n")
+		}
+		b.Write(d)
+	}
+	return b.Bytes()
+}
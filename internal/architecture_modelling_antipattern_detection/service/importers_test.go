@@ -0,0 +1,138 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+func TestImportToYAML_ComposeRunsThroughPipeline(t *testing.T) {
+	compose := `
+services:
+  api:
+    image: traefik:v3
+    depends_on: [orders, billing]
+  orders:
+    build: .
+    environment:
+      DB_HOST: shared-db
+      BILLING_URL: http://billing:8080
+  billing:
+    build: .
+    environment:
+      DB_HOST: shared-db
+  shared-db:
+    image: mysql:8
+`
+	y, err := ImportToYAML("docker-compose", []byte(compose))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, _, err := AnalyzeYAMLBytesInMemory(y, "compose", "", nil)
+	if err != nil {
+		t.Fatalf("analyze imported yaml: %v\n%s", err, y)
+	}
+	for id, kind := range map[string]domain.NodeKind{
+		"API_GATEWAY:api":    domain.NodeAPIGateway,
+		"SERVICE:orders":     domain.NodeService,
+		"DATABASE:shared-db": domain.NodeDB,
+	} {
+		if n := res.Graph.Nodes[id]; n == nil || n.Kind != kind {
+			t.Fatalf("missing %s in %v", id, res.Graph.Nodes)
+		}
+	}
+	if kinds(res.Detections)[domain.APSharedDatabase] == 0 {
+		t.Fatalf("expected shared_database for orders+billing on shared-db, got %+v", res.Detections)
+	}
+
+	if _, err := ImportToYAML("helm", []byte("x")); err == nil || !strings.Contains(err.Error(), "compose") {
+		t.Fatalf("unsupported format should list supported ones, got %v", err)
+	}
+}
+
+func TestImportToYAML_KubernetesKeepsSizing(t *testing.T) {
+	manifests := `
+apiVersion: apps/v1
+kind: Deployment
+metadata: {name: web}
+spec:
+  replicas: 4
+  template:
+    metadata: {labels: {app: web}}
+    spec:
+      containers:
+        - image: acme/web:1
+          env:
+            - {name: API, value: "http://api.default.svc.cluster.local"}
+          resources: {requests: {cpu: 250m}}
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata: {name: api}
+spec:
+  template:
+    metadata: {labels: {app: api}}
+    spec:
+      containers: [{image: acme/api:1}]
+---
+apiVersion: v1
+kind: Service
+metadata: {name: api}
+spec: {selector: {app: api}}
+`
+	y, err := ImportToYAML("k8s", []byte(manifests))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(y), "replicas: 4") || !strings.Contains(string(y), "cpu_cores: 0.25") {
+		t.Fatalf("sizing missing from imported yaml:\n%s", y)
+	}
+	res, _, err := AnalyzeYAMLBytesInMemory(y, "k8s", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Graph.Edges) != 1 || res.Graph.Edges[0].From != "SERVICE:web" || res.Graph.Edges[0].To != "SERVICE:api" {
+		t.Fatalf("edges: %+v", res.Graph.Edges)
+	}
+}
+
+func TestImportToYAML_APIDocsMixCallsAndEvents(t *testing.T) {
+	orders := []byte(`
+openapi: 3.0.3
+info: {title: Orders API}
+paths:
+  /orders: {post: {}}
+x-calls:
+  - {service: billing, endpoints: ["POST /invoices"]}
+`)
+	billing := []byte(`{"openapi": "3.0.3", "info": {"title": "Billing API"}, "paths": {"/invoices": {"post": {}}}}`)
+	events := []byte(`
+asyncapi: 3.0.0
+info: {title: Orders Events}
+channels:
+  created: {address: order.created}
+operations:
+  emit: {action: send, channel: {$ref: "#/channels/created"}}
+`)
+	y, err := ImportToYAML("openapi", JoinDocuments([][]byte{orders, billing, events}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, _, err := AnalyzeYAMLBytesInMemory(y, "api", "", nil)
+	if err != nil {
+		t.Fatalf("analyze imported yaml: %v\n%s", err, y)
+	}
+	var call, publish bool
+	for _, e := range res.Graph.Edges {
+		switch {
+		case e.From == "SERVICE:orders" && e.To == "SERVICE:billing":
+			call = e.Attrs["count"] == 1 && e.Attrs["sync"] == true
+		case e.From == "SERVICE:orders" && e.To == "EVENT_TOPIC:order.created":
+			publish = e.Attrs["sync"] == false
+		}
+	}
+	if !call || !publish {
+		t.Fatalf("expected sync call with endpoints and async publish, got %+v\n%s", res.Graph.Edges, y)
+	}
+}
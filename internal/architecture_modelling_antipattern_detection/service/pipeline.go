@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection"
 	_ "github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection/rules"
@@ -12,6 +14,7 @@ import (
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/mapper"
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/validator"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ml/features"
 	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/utils"
 )
 
@@ -20,62 +23,165 @@ type Result struct {
 	DOTPath    string             `json:"dot_path" yaml:"dot_path"`
 	SVGPath    string             `json:"svg_path" yaml:"svg_path"`
 	Detections []domain.Detection `json:"detections" yaml:"detections"`
+	// DetectorConfig is the effective (fully populated) config the detections were produced with.
+	DetectorConfig *detection.DetectorConfig `json:"detector_config,omitempty" yaml:"detector_config,omitempty"`
+	// Metrics are structural graph metrics (fan-in/out, instability, centrality, sync depth, SCCs, density).
+	Metrics *features.GraphMetrics `json:"metrics,omitempty" yaml:"metrics,omitempty"`
+	// Suppressed are detections silenced by the spec's metadata.suppressions (not part of Detections).
+	Suppressed []domain.Detection `json:"suppressed,omitempty" yaml:"suppressed,omitempty"`
+	// SVGRenderer is the backend that produced SVGPath: "dot" (Graphviz) or "builtin".
+	SVGRenderer string `json:"svg_renderer,omitempty" yaml:"svg_renderer,omitempty"`
+	// Issues are validation warnings for the source YAML (errors abort the analysis instead).
+	Issues validator.Issues `json:"issues,omitempty" yaml:"issues,omitempty"`
+	// RuleErrors are the custom rules that failed on this graph; the other detections are kept.
+	RuleErrors []detection.RuleError `json:"rule_errors,omitempty" yaml:"rule_errors,omitempty"`
 }
 
-func AnalyzeYAML(path string, outDir string, title string, dotBin string) (*Result, error) {
-	ys, err := parser.ParseYAML(path)
+// SVG renderers selectable with AMG_APD_SVG_RENDERER. RendererAuto (the default) uses Graphviz
+// when the dot binary is available and falls back to the built-in layout otherwise.
+const (
+	RendererAuto    = "auto"
+	RendererDot     = "dot"
+	RendererBuiltin = "builtin"
+)
+
+// renderSVG writes svgPath from the DOT file (Graphviz) or straight from g (built-in layout),
+// returning the renderer used.
+func renderSVG(g *domain.Graph, title string, dets []domain.Detection, dotPath, svgPath, dotBin string) (string, error) {
+	mode := strings.ToLower(strings.TrimSpace(os.Getenv("AMG_APD_SVG_RENDERER")))
+	switch mode {
+	case "", RendererAuto:
+		if utils.HasDot(dotBin) {
+			if err := utils.DotTo(dotPath, svgPath, "svg", dotBin); err == nil {
+				return RendererDot, nil
+			}
+		}
+	case RendererDot:
+		if err := utils.DotTo(dotPath, svgPath, "svg", dotBin); err != nil {
+			return "", fmt.Errorf("graphviz render: %w", err)
+		}
+		return RendererDot, nil
+	case RendererBuiltin:
+	default:
+		return "", fmt.Errorf("unknown AMG_APD_SVG_RENDERER %q (want %s, %s or %s)", mode, RendererAuto, RendererDot, RendererBuiltin)
+	}
+	if err := utils.WriteFile(svgPath, export.ToSVG(g, title, dets)); err != nil {
+		return "", err
+	}
+	return RendererBuiltin, nil
+}
+
+// suppressionsOf converts the spec's metadata.suppressions for detection.ApplySuppressions.
+func suppressionsOf(ys *parser.YSpec) ([]detection.Suppression, error) {
+	list, err := ys.Suppressions()
 	if err != nil {
 		return nil, err
 	}
+	out := make([]detection.Suppression, 0, len(list))
+	for _, s := range list {
+		exp, err := s.ExpiresAt()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, detection.Suppression{
+			Kind:    domain.AntiPatternKind(s.Kind),
+			Nodes:   s.Nodes,
+			Reason:  s.Reason,
+			Expires: exp,
+		})
+	}
+	return out, nil
+}
+
+// prepareSpec normalizes and validates ys and reads its suppressions.
+func prepareSpec(ys *parser.YSpec) (*domain.Graph, []detection.Suppression, error) {
 	mapper.NormalizeYAMLSpecInPlace(ys)
 	if err := validator.Validate(ys); err != nil {
+		return nil, nil, err
+	}
+	sups, err := suppressionsOf(ys)
+	if err != nil {
+		return nil, nil, err
+	}
+	return mapper.ToGraph(ys), sups, nil
+}
+
+// parseSpec parses and validates YAML. Error-severity issues are returned as the error (a
+// validator.Issues); warnings are returned for the Result.
+func parseSpec(yamlBytes []byte) (*parser.YSpec, validator.Issues, error) {
+	ys, issues := validator.ValidateYAML(yamlBytes)
+	if errs := issues.Errors(); len(errs) > 0 {
+		return nil, nil, errs
+	}
+	return ys, issues.Warnings(), nil
+}
+
+// AnalyzeYAML analyzes the spec at path into outDir. cfg is the detector config (nil for
+// built-in defaults), as for AnalyzeYAMLBytesInMemory.
+func AnalyzeYAML(path string, outDir string, title string, dotBin string, cfg *detection.DetectorConfig) (*Result, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
 		return nil, err
 	}
-	g := mapper.ToGraph(ys)
-	return analyzeGraphToDir(g, outDir, title, dotBin)
+	return AnalyzeYAMLBytesToDir(b, outDir, title, dotBin, cfg)
 }
 
-func AnalyzeYAMLBytesToDir(yamlBytes []byte, outDir string, title string, dotBin string) (*Result, error) {
-	ys, err := parser.ParseYAMLBytes(yamlBytes)
+func AnalyzeYAMLBytesToDir(yamlBytes []byte, outDir string, title string, dotBin string, cfg *detection.DetectorConfig) (*Result, error) {
+	ys, issues, err := parseSpec(yamlBytes)
 	if err != nil {
 		return nil, err
 	}
-	mapper.NormalizeYAMLSpecInPlace(ys)
-	if err := validator.Validate(ys); err != nil {
+	g, sups, err := prepareSpec(ys)
+	if err != nil {
+		return nil, err
+	}
+	res, err := analyzeGraphToDir(g, outDir, title, dotBin, cfg, sups)
+	if err != nil {
 		return nil, err
 	}
-	g := mapper.ToGraph(ys)
-	return analyzeGraphToDir(g, outDir, title, dotBin)
+	res.Issues = issues
+	return res, nil
 }
 
-func AnalyzeYAMLBytes(yamlBytes []byte, outBaseDir string, title string, dotBin string) (*Result, error) {
+func AnalyzeYAMLBytes(yamlBytes []byte, outBaseDir string, title string, dotBin string, cfg *detection.DetectorConfig) (*Result, error) {
 	if outBaseDir == "" {
 		outBaseDir = "out"
 	}
 	runDir := filepath.Join(outBaseDir, "runs", utils.NewID())
-	return AnalyzeYAMLBytesToDir(yamlBytes, runDir, title, dotBin)
+	return AnalyzeYAMLBytesToDir(yamlBytes, runDir, title, dotBin, cfg)
 }
 
 // AnalyzeYAMLBytesInMemory runs analysis without writing to the filesystem.
 // Returns Result (with DOTPath/SVGPath empty) and the DOT content string for storage/rendering.
-func AnalyzeYAMLBytesInMemory(yamlBytes []byte, title string, dotBin string) (*Result, string, error) {
-	ys, err := parser.ParseYAMLBytes(yamlBytes)
+// cfg is the project's detector config (nil for built-in defaults).
+func AnalyzeYAMLBytesInMemory(yamlBytes []byte, title string, dotBin string, cfg *detection.DetectorConfig) (*Result, string, error) {
+	ys, issues, err := parseSpec(yamlBytes)
 	if err != nil {
 		return nil, "", err
 	}
-	mapper.NormalizeYAMLSpecInPlace(ys)
-	if err := validator.Validate(ys); err != nil {
+	g, sups, err := prepareSpec(ys)
+	if err != nil {
+		return nil, "", err
+	}
+	res, dot, err := analyzeGraphInMemory(g, title, dotBin, cfg, sups)
+	if err != nil {
 		return nil, "", err
 	}
-	g := mapper.ToGraph(ys)
-	return analyzeGraphInMemory(g, title, dotBin)
+	res.Issues = issues
+	return res, dot, nil
 }
 
-func analyzeGraphInMemory(g *domain.Graph, title string, dotBin string) (*Result, string, error) {
-	dot := export.ToDOT(g, title)
-	all, err := detection.RunAll(g)
+// runDetectors runs the detectors enabled by cfg on g and splits the detections into those kept
+// and those silenced by sups; the kept ones are ordered by the ranking model when one is set.
+// Custom rules that failed are returned next to them. m is g's metrics, shared by the custom
+// rules and the ranking model; nil computes them only when one of those needs them.
+func runDetectors(g *domain.Graph, m *features.GraphMetrics, cfg *detection.DetectorConfig, sups []detection.Suppression) (kept, suppressed []domain.Detection, ruleErrs []detection.RuleError, err error) {
+	if m == nil && (ranking.model != nil || (cfg != nil && len(cfg.CustomRules) > 0)) {
+		m = features.Compute(g)
+	}
+	all, ruleErrs, err := detection.RunAllReport(g, cfg, m)
 	if err != nil {
-		return nil, "", err
+		return nil, nil, nil, err
 	}
 	for i := range all {
 		if all[i].Nodes == nil {
@@ -85,11 +191,22 @@ func analyzeGraphInMemory(g *domain.Graph, title string, dotBin string) (*Result
 			all[i].Edges = []int{}
 		}
 	}
-	res := &Result{Graph: g, DOTPath: "", SVGPath: "", Detections: all}
+	kept, suppressed = detection.ApplySuppressions(all, sups, time.Now())
+	return rankDetections(m, kept), suppressed, ruleErrs, nil
+}
+
+func analyzeGraphInMemory(g *domain.Graph, title string, dotBin string, cfg *detection.DetectorConfig, sups []detection.Suppression) (*Result, string, error) {
+	m := features.Compute(g)
+	kept, suppressed, ruleErrs, err := runDetectors(g, m, cfg, sups)
+	if err != nil {
+		return nil, "", err
+	}
+	dot := export.ToDOT(g, title, kept)
+	res := &Result{Graph: g, DOTPath: "", SVGPath: "", Detections: kept, DetectorConfig: detection.Effective(cfg), Metrics: m, Suppressed: suppressed, RuleErrors: ruleErrs}
 	return res, dot, nil
 }
 
-func analyzeGraphToDir(g *domain.Graph, outDir string, title string, dotBin string) (*Result, error) {
+func analyzeGraphToDir(g *domain.Graph, outDir string, title string, dotBin string, cfg *detection.DetectorConfig, sups []detection.Suppression) (*Result, error) {
 	if outDir == "" {
 		outDir = "out"
 	}
@@ -97,7 +214,13 @@ func analyzeGraphToDir(g *domain.Graph, outDir string, title string, dotBin stri
 		return nil, err
 	}
 
-	dot := export.ToDOT(g, title)
+	m := features.Compute(g)
+	kept, suppressed, ruleErrs, err := runDetectors(g, m, cfg, sups)
+	if err != nil {
+		return nil, err
+	}
+
+	dot := export.ToDOT(g, title, kept)
 	dotPath := filepath.Join(outDir, "graph.dot")
 	if err := utils.WriteFile(dotPath, dot); err != nil {
 		return nil, err
@@ -107,25 +230,11 @@ func analyzeGraphToDir(g *domain.Graph, outDir string, title string, dotBin stri
 	if dotBin == "" {
 		dotBin = "dot"
 	}
-	if err := utils.DotTo(dotPath, svgPath, "svg", dotBin); err != nil {
-		return nil, fmt.Errorf("graphviz render: %w", err)
-	}
-
-	all, err := detection.RunAll(g)
+	renderer, err := renderSVG(g, title, kept, dotPath, svgPath, dotBin)
 	if err != nil {
 		return nil, err
 	}
-
-	for i := range all {
-		if all[i].Nodes == nil {
-			all[i].Nodes = []string{}
-		}
-		if all[i].Edges == nil {
-			all[i].Edges = []int{}
-		}
-	}
-
-	res := &Result{Graph: g, DOTPath: dotPath, SVGPath: svgPath, Detections: all}
+	res := &Result{Graph: g, DOTPath: dotPath, SVGPath: svgPath, Detections: kept, DetectorConfig: detection.Effective(cfg), Metrics: m, Suppressed: suppressed, SVGRenderer: renderer, RuleErrors: ruleErrs}
 
 	if err := export.WriteJSON(filepath.Join(outDir, "analysis.json"), res); err != nil {
 		return nil, err
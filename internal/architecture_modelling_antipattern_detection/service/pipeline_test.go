@@ -0,0 +1,116 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/validator"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/scoring"
+)
+
+func TestAnalyzeToDir_BuiltinRendererWithoutGraphviz(t *testing.T) {
+	t.Setenv("AMG_APD_SVG_RENDERER", "")
+	dir := t.TempDir()
+	res, err := AnalyzeYAMLBytesToDir([]byte(cycleYAML), dir, "t", "/nonexistent/dot", nil)
+	if err != nil {
+		t.Fatalf("auto renderer should fall back when dot is missing: %v", err)
+	}
+	if res.SVGRenderer != RendererBuiltin {
+		t.Fatalf("renderer = %q, want %q", res.SVGRenderer, RendererBuiltin)
+	}
+	b, err := os.ReadFile(res.SVGPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(b), "<svg") || !strings.Contains(string(b), "SERVICE:orders") {
+		t.Fatalf("unexpected svg:\n%s", b)
+	}
+
+	t.Setenv("AMG_APD_SVG_RENDERER", RendererDot)
+	if _, err := AnalyzeYAMLBytesToDir([]byte(cycleYAML), t.TempDir(), "t", "/nonexistent/dot", nil); err == nil || !strings.Contains(err.Error(), "graphviz render") {
+		t.Fatalf("forced dot renderer should fail without dot, got %v", err)
+	}
+}
+
+func TestAnalyzeToDir_HonorsDetectorConfig(t *testing.T) {
+	t.Setenv("AMG_APD_SVG_RENDERER", RendererBuiltin)
+	off := false
+	cfg := &detection.DetectorConfig{Rules: map[domain.AntiPatternKind]detection.RuleConfig{
+		domain.APCycles:             {Enabled: &off},
+		domain.APPingPongDependency: {Enabled: &off},
+	}}
+	res, err := AnalyzeYAMLBytesToDir([]byte(cycleYAML), t.TempDir(), "t", "", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range res.Detections {
+		if d.Kind == domain.APCycles {
+			t.Fatalf("disabled cycles detector still reported %+v", d)
+		}
+	}
+	if res.DetectorConfig.Enabled(domain.APCycles) {
+		t.Fatal("result must record the effective config, with cycles disabled")
+	}
+
+	prev, err := PreviewSuggestionsYAMLBytes([]byte(pingPongYAML), t.TempDir(), "t", nil, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range prev.Suggestions {
+		if s.Kind == domain.APPingPongDependency {
+			t.Fatalf("preview ignored the detector config: %+v", s)
+		}
+	}
+}
+
+func TestAnalyzeInMemory_ValidationIssues(t *testing.T) {
+	res, _, err := AnalyzeYAMLBytesInMemory([]byte(cycleYAML+"  - from: orders\n    to: ledger\n"), "t", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Issues) != 1 || res.Issues[0].Code != validator.CodeUnknownService || res.Issues[0].Line == 0 {
+		t.Fatalf("expected an unknown_service warning with a position, got %+v", res.Issues)
+	}
+
+	_, _, err = AnalyzeYAMLBytesInMemory([]byte("services:\n  - name: a\n  - name: a\n  - name: ''\n"), "t", "", nil)
+	var issues validator.Issues
+	if !errors.As(err, &issues) || len(issues) != 2 {
+		t.Fatalf("expected both errors as validator.Issues, got %v", err)
+	}
+}
+
+func TestAnalyzeInMemory_RanksWithModelFromEnv(t *testing.T) {
+	t.Setenv(ModelPathEnv, "../ml/model/testdata/logistic.json")
+	t.Setenv(ModelBlendEnv, "1")
+	name, err := LoadRankingModelFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer SetRankingModel(nil, 0)
+	if name != "test-lr" {
+		t.Fatalf("model name = %q", name)
+	}
+	res, _, err := AnalyzeYAMLBytesInMemory([]byte(cycleYAML), "t", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Detections) == 0 {
+		t.Fatal("expected detections")
+	}
+	last := 101.0
+	for _, d := range res.Detections {
+		note, ok := d.Evidence[scoring.ModelEvidenceKey].(domain.Attrs)
+		if !ok {
+			t.Fatalf("%s has no model evidence: %+v", d.Kind, d.Evidence)
+		}
+		s := note["score"].(float64)
+		if s > last {
+			t.Fatalf("detections not ranked by model score: %+v", res.Detections)
+		}
+		last = s
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/validator"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/suggestion"
+)
+
+func TestApplySuggestions_PlanSkipsResolvedFixes(t *testing.T) {
+	t.Setenv("AMG_APD_SVG_RENDERER", "")
+	t.Setenv("DOT_BIN", "/nonexistent/dot")
+	dir := t.TempDir()
+	// cart <-> catalog is a cycle, a ping-pong and a tight coupling at once.
+	prev, err := PreviewSuggestionsYAMLBytes([]byte(pingPongYAML), dir, "t", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, s := range prev.Suggestions {
+		ids = append(ids, s.ID)
+	}
+	if len(prev.Plan) != 3 || prev.Plan[0].Kind != domain.APCycles {
+		t.Fatalf("plan = %+v", prev.Plan)
+	}
+	for _, p := range prev.Plan {
+		if p.Status != suggestion.FixPlanned || len(p.ConflictsWith) != 2 || len(p.DependsOn) != p.Step-1 {
+			t.Errorf("planned fix = %+v", p)
+		}
+	}
+
+	res, err := ApplySuggestionsYAMLBytes("job", []byte(pingPongYAML), dir, "t", ids, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.AppliedFixes) != 1 || res.AppliedFixes[0].Kind != domain.APCycles {
+		t.Fatalf("applied = %+v", res.AppliedFixes)
+	}
+	if strings.Count(res.FixedYAML, "- from:") != 1 {
+		t.Errorf("expected exactly one dependency removed:\n%s", res.FixedYAML)
+	}
+	for _, p := range res.Plan[1:] {
+		if p.Status != suggestion.FixSkipped || !strings.HasPrefix(p.Reason, "no longer detected after cycles|") {
+			t.Errorf("fix %s: status %s, reason %q", p.ID, p.Status, p.Reason)
+		}
+	}
+}
+
+func TestPreviewSuggestions_PlanForSelection(t *testing.T) {
+	t.Setenv("AMG_APD_SVG_RENDERER", "")
+	t.Setenv("DOT_BIN", "/nonexistent/dot")
+	prev, err := PreviewSuggestionsYAMLBytes([]byte(twoPingPongsYAML), t.TempDir(), "t", []string{
+		"ping_pong_dependency|SERVICE:billing,SERVICE:orders",
+		"ping_pong_dependency|SERVICE:cart,SERVICE:catalog",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prev.Plan) != 2 {
+		t.Fatalf("plan = %+v", prev.Plan)
+	}
+	for _, p := range prev.Plan {
+		// The two pairs share no services, so the fixes are independent.
+		if p.Kind != domain.APPingPongDependency || len(p.ConflictsWith) != 0 {
+			t.Errorf("planned fix = %+v", p)
+		}
+	}
+}
+
+func TestApplyFixPlan_InvalidIntermediateSpecSkipsFix(t *testing.T) {
+	res, _, err := AnalyzeYAMLBytesInMemory([]byte(twoPingPongsYAML), "t", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	selected := map[string]bool{
+		"ping_pong_dependency|SERVICE:billing,SERVICE:orders": true,
+		"ping_pong_dependency|SERVICE:cart,SERVICE:catalog":   true,
+	}
+	invalid := func(*parser.YSpec) (*domain.Graph, []domain.Detection, error) {
+		return nil, nil, validator.Issues{{Path: "services", Severity: validator.SeverityError, Code: "test", Message: "broken"}}
+	}
+	_, applied, plan, err := suggestion.ApplyFixPlan([]byte(twoPingPongsYAML), res.Graph, res.Detections, selected, invalid)
+	if err != nil {
+		t.Fatalf("an invalid intermediate spec must not abort the plan: %v", err)
+	}
+	if len(applied) != 1 || plan[0].Status != suggestion.FixApplied {
+		t.Fatalf("applied = %+v, plan = %+v", applied, plan)
+	}
+	if plan[1].Status != suggestion.FixSkipped || !strings.Contains(plan[1].Reason, "broken") {
+		t.Fatalf("second fix: %+v", plan[1])
+	}
+}
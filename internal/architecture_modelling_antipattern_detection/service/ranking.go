@@ -0,0 +1,65 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ml/features"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ml/model"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/scoring"
+)
+
+// Environment read by LoadRankingModelFromEnv: ModelPathEnv names an ml/model weights file and
+// ModelBlendEnv the share of the model's confidence in the ranking score (0..1, default 0.5).
+const (
+	ModelPathEnv  = "AMG_APD_MODEL_PATH"
+	ModelBlendEnv = "AMG_APD_MODEL_BLEND"
+)
+
+const defaultModelBlend = 0.5
+
+// ranking is the model analyses rank detections with; set once at startup, nil for the fixed weights.
+var ranking struct {
+	model model.Model
+	blend float64
+}
+
+// SetRankingModel makes every analysis order its detections with scoring.PrioritizeDetections,
+// blending m's confidence by blend. A nil m restores the rule order. Call it before serving.
+func SetRankingModel(m model.Model, blend float64) {
+	ranking.model, ranking.blend = m, blend
+}
+
+// LoadRankingModelFromEnv loads the model named by ModelPathEnv and sets it with SetRankingModel.
+// It returns the model's name, or "" when ModelPathEnv is unset.
+func LoadRankingModelFromEnv() (string, error) {
+	path := strings.TrimSpace(os.Getenv(ModelPathEnv))
+	if path == "" {
+		return "", nil
+	}
+	blend := defaultModelBlend
+	if v := strings.TrimSpace(os.Getenv(ModelBlendEnv)); v != "" {
+		b, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", ModelBlendEnv, err)
+		}
+		blend = b
+	}
+	m, err := model.LoadFile(path)
+	if err != nil {
+		return "", err
+	}
+	SetRankingModel(m, blend)
+	return m.Name(), nil
+}
+
+// rankDetections orders dets by the ranking model, if one is set; m is the analyzed graph's metrics.
+func rankDetections(m *features.GraphMetrics, dets []domain.Detection) []domain.Detection {
+	if ranking.model == nil || len(dets) == 0 {
+		return dets
+	}
+	return scoring.PrioritizeDetections(dets, scoring.WithModel(ranking.model, m, ranking.blend))
+}
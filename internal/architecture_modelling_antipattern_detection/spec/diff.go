@@ -0,0 +1,14 @@
+package spec
+
+import "github.com/pmezard/go-difflib/difflib"
+
+// UnifiedDiff returns a unified diff (3 lines of context) from a to b, or "" when they are equal.
+func UnifiedDiff(aName, bName string, a, b []byte) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(a)),
+		B:        difflib.SplitLines(string(b)),
+		FromFile: aName,
+		ToFile:   bName,
+		Context:  3,
+	})
+}
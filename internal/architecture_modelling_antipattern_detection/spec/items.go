@@ -0,0 +1,118 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
+)
+
+// Item identity, emptiness and the plain-tree form of a spec, shared by the YAML patcher, the
+// patch ops and the version merge (versioning.MergeSpecs) so all three match list items alike.
+
+// identity is the identity of a list item whose fields are read with field: its name, from->to
+// for dependencies, or the target for calls. References are compared without their graph-ID
+// prefix and case-insensitively, so "SERVICE:Orders" and "orders" are the same item.
+func identity(field func(key string) string) (string, bool) {
+	str := func(k string) string {
+		return strings.ToLower(cleanRef(field(k)))
+	}
+	switch {
+	case str("name") != "":
+		return str("name"), true
+	case str("from") != "" && str("to") != "":
+		return str("from") + "->" + str("to"), true
+	case str("to") != "":
+		return str("to"), true
+	}
+	return "", false
+}
+
+// ItemKey is the identity of a list item of a spec tree (see Tree); ok is false for items that
+// are not mappings or have none of the identifying fields.
+func ItemKey(v any) (string, bool) {
+	item, ok := v.(map[string]any)
+	if !ok {
+		return "", false
+	}
+	return identity(func(k string) string {
+		s, _ := item[k].(string)
+		return s
+	})
+}
+
+// KeyedItems indexes list items by ItemKey, also returning the keys in list order; ok is false
+// when an item has no identity or two items share one.
+func KeyedItems(items []any) (map[string]any, []string, bool) {
+	out := map[string]any{}
+	var order []string
+	for _, it := range items {
+		k, ok := ItemKey(it)
+		if !ok {
+			return nil, nil, false
+		}
+		if _, dup := out[k]; dup {
+			return nil, nil, false
+		}
+		out[k] = it
+		order = append(order, k)
+	}
+	return out, order, true
+}
+
+// SameValue compares tree values by their JSON encoding, so values read back from JSON (numbers
+// as float64) still match the spec; absent, null and empty values are equal.
+func SameValue(a, b any) bool {
+	if IsEmptyValue(a) || IsEmptyValue(b) {
+		return IsEmptyValue(a) && IsEmptyValue(b)
+	}
+	ja, err1 := json.Marshal(a)
+	jb, err2 := json.Marshal(b)
+	return err1 == nil && err2 == nil && bytes.Equal(ja, jb)
+}
+
+// IsEmptyValue reports whether v is absent: nil, or an empty map or list.
+func IsEmptyValue(v any) bool {
+	switch x := v.(type) {
+	case nil:
+		return true
+	case map[string]any:
+		return len(x) == 0
+	case []any:
+		return len(x) == 0
+	}
+	return false
+}
+
+// Tree converts s to plain maps and lists, as YAML would decode it; a nil spec is empty.
+func Tree(s *parser.YSpec) (map[string]any, error) {
+	t := map[string]any{}
+	if s == nil {
+		return t, nil
+	}
+	b, err := yaml.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(b, &t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// FromTree converts a tree back to a spec.
+func FromTree(t any) (*parser.YSpec, error) {
+	b, err := yaml.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	var s parser.YSpec
+	if err := yaml.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("spec tree: %w", err)
+	}
+	return &s, nil
+}
@@ -0,0 +1,303 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
+)
+
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+)
+
+// ErrOpConflict is wrapped by ApplyOps errors when the spec no longer holds what an op expects
+// (the item to add exists with another value, the item to remove is gone, or the value to
+// replace was changed since).
+var ErrOpConflict = errors.New("patch conflict")
+
+// PatchOp is one JSON Patch–like edit of a spec. Path is a JSON Pointer whose list items are
+// addressed by identity instead of index: services, datastores, topics and APIs by name,
+// dependencies by from->to and calls by target, e.g. "/services/orders/type" or
+// "/dependencies/orders->billing/sync". Old is the value replaced or removed, which makes every
+// op invertible and lets ApplyOps detect edits made in between.
+type PatchOp struct {
+	Op    string `json:"op" yaml:"op"`
+	Path  string `json:"path" yaml:"path"`
+	Value any    `json:"value,omitempty" yaml:"value,omitempty"`
+	Old   any    `json:"old,omitempty" yaml:"old,omitempty"`
+}
+
+// DiffSpecs returns the ops that turn before into after, in path order. Whole items are added
+// or removed; fields of an item present on both sides are replaced one by one.
+func DiffSpecs(before, after *parser.YSpec) ([]PatchOp, error) {
+	b, err := Tree(before)
+	if err != nil {
+		return nil, err
+	}
+	return DiffFromTree(b, after)
+}
+
+// DiffFromTree is DiffSpecs for a before already converted with Tree, so one spec can be diffed
+// against many without converting it each time. before is not modified.
+func DiffFromTree(before map[string]any, after *parser.YSpec) ([]PatchOp, error) {
+	a, err := Tree(after)
+	if err != nil {
+		return nil, err
+	}
+	ops := []PatchOp{}
+	diffValue(&ops, "", before, a)
+	return ops, nil
+}
+
+// ApplyOps applies ops to a copy of s. It fails with ErrOpConflict on the first op that does not
+// apply; s is never modified.
+func ApplyOps(s *parser.YSpec, ops []PatchOp) (*parser.YSpec, error) {
+	tree, err := Tree(s)
+	if err != nil {
+		return nil, err
+	}
+	var root any = tree
+	for _, op := range ops {
+		segs, err := SplitPath(op.Path)
+		if err != nil {
+			return nil, err
+		}
+		if root, err = applyOp(root, segs, op); err != nil {
+			return nil, err
+		}
+	}
+	return FromTree(root)
+}
+
+// InvertOps returns the ops that undo ops: reversed, with adds and removes swapped and replaced
+// values exchanged.
+func InvertOps(ops []PatchOp) []PatchOp {
+	out := make([]PatchOp, 0, len(ops))
+	for i := len(ops) - 1; i >= 0; i-- {
+		op := ops[i]
+		switch op.Op {
+		case OpAdd:
+			out = append(out, PatchOp{Op: OpRemove, Path: op.Path, Old: op.Value})
+		case OpRemove:
+			out = append(out, PatchOp{Op: OpAdd, Path: op.Path, Value: op.Old})
+		default:
+			out = append(out, PatchOp{Op: op.Op, Path: op.Path, Value: op.Old, Old: op.Value})
+		}
+	}
+	return out
+}
+
+// CloneSpec returns a deep copy of s (an empty spec when s is nil).
+func CloneSpec(s *parser.YSpec) (*parser.YSpec, error) {
+	var out parser.YSpec
+	if s == nil {
+		return &out, nil
+	}
+	b, err := yaml.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func diffValue(ops *[]PatchOp, path string, b, a any) {
+	if SameValue(b, a) {
+		return
+	}
+	bm, bIsMap := b.(map[string]any)
+	am, aIsMap := a.(map[string]any)
+	if bIsMap && aIsMap {
+		keys := map[string]bool{}
+		for k := range bm {
+			keys[k] = true
+		}
+		for k := range am {
+			keys[k] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+		for _, k := range sorted {
+			diffMember(ops, path+"/"+escapeSeg(k), bm[k], am[k])
+		}
+		return
+	}
+	bl, bIsList := b.([]any)
+	al, aIsList := a.([]any)
+	if bIsList && aIsList {
+		bk, bOrder, ok1 := KeyedItems(bl)
+		ak, aOrder, ok2 := KeyedItems(al)
+		if ok1 && ok2 {
+			for _, k := range bOrder {
+				if _, kept := ak[k]; !kept {
+					diffMember(ops, path+"/"+escapeSeg(k), bk[k], nil)
+				}
+			}
+			for _, k := range aOrder {
+				diffMember(ops, path+"/"+escapeSeg(k), bk[k], ak[k])
+			}
+			return
+		}
+	}
+	*ops = append(*ops, PatchOp{Op: OpReplace, Path: path, Value: a, Old: b})
+}
+
+// diffMember diffs a map value or list item that may be absent on either side.
+func diffMember(ops *[]PatchOp, path string, b, a any) {
+	switch {
+	case SameValue(b, a):
+	case IsEmptyValue(b):
+		*ops = append(*ops, PatchOp{Op: OpAdd, Path: path, Value: a})
+	case IsEmptyValue(a):
+		*ops = append(*ops, PatchOp{Op: OpRemove, Path: path, Old: b})
+	default:
+		diffValue(ops, path, b, a)
+	}
+}
+
+// applyOp applies op at segs below node and returns the updated node.
+func applyOp(node any, segs []string, op PatchOp) (any, error) {
+	key := segs[0]
+	cur, found := lookupMember(node, key)
+	if len(segs) > 1 {
+		if !found {
+			if op.Op != OpAdd {
+				return nil, fmt.Errorf("%w: %s %s: %s not found", ErrOpConflict, op.Op, op.Path, key)
+			}
+			cur = newContainer(segs[1], len(segs) == 2, op.Value)
+		}
+		child, err := applyOp(cur, segs[1:], op)
+		if err != nil {
+			return nil, err
+		}
+		return setMember(node, key, child, op)
+	}
+	switch op.Op {
+	case OpAdd:
+		if found {
+			if SameValue(cur, op.Value) {
+				return node, nil
+			}
+			return nil, fmt.Errorf("%w: add %s: already exists", ErrOpConflict, op.Path)
+		}
+		return setMember(node, key, cloneValue(op.Value), op)
+	case OpRemove:
+		if !found {
+			return nil, fmt.Errorf("%w: remove %s: not found", ErrOpConflict, op.Path)
+		}
+		return removeMember(node, key), nil
+	case OpReplace:
+		if !SameValue(cur, op.Old) {
+			return nil, fmt.Errorf("%w: replace %s: value changed since the patch was made", ErrOpConflict, op.Path)
+		}
+		return setMember(node, key, cloneValue(op.Value), op)
+	}
+	return nil, fmt.Errorf("unknown patch op %q at %s", op.Op, op.Path)
+}
+
+// lookupMember finds key in a map, or the item with identity key in a list; empty values count
+// as absent.
+func lookupMember(node any, key string) (any, bool) {
+	switch n := node.(type) {
+	case map[string]any:
+		v, ok := n[key]
+		return v, ok && !IsEmptyValue(v)
+	case []any:
+		for _, it := range n {
+			if k, ok := ItemKey(it); ok && k == strings.ToLower(key) {
+				return it, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func setMember(node any, key string, v any, op PatchOp) (any, error) {
+	switch n := node.(type) {
+	case map[string]any:
+		n[key] = v
+		return n, nil
+	case []any:
+		for i, it := range n {
+			if k, ok := ItemKey(it); ok && k == strings.ToLower(key) {
+				n[i] = v
+				return n, nil
+			}
+		}
+		return append(n, v), nil
+	}
+	return nil, fmt.Errorf("%w: %s %s: %s is not a map or list", ErrOpConflict, op.Op, op.Path, key)
+}
+
+func removeMember(node any, key string) any {
+	switch n := node.(type) {
+	case map[string]any:
+		delete(n, key)
+		return n
+	case []any:
+		kept := n[:0]
+		for _, it := range n {
+			if k, ok := ItemKey(it); ok && k == strings.ToLower(key) {
+				continue
+			}
+			kept = append(kept, it)
+		}
+		return kept
+	}
+	return node
+}
+
+// newContainer creates the missing parent of an added value: a list when the value is an item
+// identified by the last path segment, a map otherwise.
+func newContainer(next string, last bool, value any) any {
+	if k, ok := ItemKey(value); ok && last && k == strings.ToLower(next) {
+		return []any{}
+	}
+	return map[string]any{}
+}
+
+func cloneValue(v any) any {
+	switch x := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(x))
+		for k, e := range x {
+			out[k] = cloneValue(e)
+		}
+		return out
+	case []any:
+		out := make([]any, len(x))
+		for i, e := range x {
+			out[i] = cloneValue(e)
+		}
+		return out
+	}
+	return v
+}
+
+func escapeSeg(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+// SplitPath splits a PatchOp path into its unescaped segments.
+func SplitPath(p string) ([]string, error) {
+	if !strings.HasPrefix(p, "/") || len(p) < 2 {
+		return nil, fmt.Errorf("invalid patch path %q", p)
+	}
+	segs := strings.Split(p[1:], "/")
+	for i, s := range segs {
+		segs[i] = strings.ReplaceAll(strings.ReplaceAll(s, "~1", "/"), "~0", "~")
+	}
+	return segs, nil
+}
@@ -0,0 +1,84 @@
+package spec
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
+)
+
+func TestDiffSpecs_ApplyAndInvert(t *testing.T) {
+	before := mustParse(t, patchDoc)
+	after := mustParse(t, patchDoc)
+	after.Dependencies[0].Sync = false
+	after.Dependencies = after.Dependencies[:1]
+	after.Services = append(after.Services, parser.YService{Name: "audit", Type: "service", Replicas: 2})
+	after.Topics = []parser.YTopic{{Name: "orders/events"}}
+
+	ops, err := DiffSpecs(before, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"/dependencies/billing->orders":      OpRemove,
+		"/dependencies/orders->billing/sync": OpRemove,
+		"/services/audit":                    OpAdd,
+		"/topics":                            OpAdd,
+	}
+	if len(ops) != len(want) {
+		t.Fatalf("ops = %+v", ops)
+	}
+	for _, op := range ops {
+		if want[op.Path] != op.Op {
+			t.Errorf("unexpected op %s %s", op.Op, op.Path)
+		}
+	}
+
+	// Ops survive a JSON round trip (numbers come back as float64).
+	b, _ := json.Marshal(ops)
+	var stored []PatchOp
+	if err := json.Unmarshal(b, &stored); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ApplyOps(before, stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rest, _ := DiffSpecs(got, after); len(rest) != 0 {
+		t.Fatalf("applied spec differs from after: %+v", rest)
+	}
+	back, err := ApplyOps(got, InvertOps(stored))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rest, _ := DiffSpecs(back, before); len(rest) != 0 {
+		t.Fatalf("inverted ops did not restore before: %+v", rest)
+	}
+}
+
+func TestApplyOps_Conflicts(t *testing.T) {
+	s := mustParse(t, patchDoc)
+	for _, op := range []PatchOp{
+		{Op: OpAdd, Path: "/services/orders", Value: map[string]any{"name": "orders", "type": "gateway"}},
+		{Op: OpRemove, Path: "/services/audit", Old: map[string]any{"name": "audit"}},
+		{Op: OpReplace, Path: "/services/billing/type", Value: "database", Old: "gateway"},
+		{Op: OpReplace, Path: "/dependencies/orders->audit/kind", Value: "grpc", Old: "rest"},
+	} {
+		if _, err := ApplyOps(s, []PatchOp{op}); !errors.Is(err, ErrOpConflict) {
+			t.Errorf("%s %s: err = %v, want a conflict", op.Op, op.Path, err)
+		}
+	}
+	if _, err := ApplyOps(s, []PatchOp{{Op: "move", Path: "/services"}}); err == nil || errors.Is(err, ErrOpConflict) {
+		t.Errorf("unknown op: err = %v", err)
+	}
+	replace := PatchOp{Op: OpReplace, Path: "/services/billing/type", Value: "database", Old: "service"}
+	got, err := ApplyOps(s, []PatchOp{replace})
+	if err != nil || got.Services[1].Type != "database" || s.Services[1].Type != "service" {
+		t.Fatalf("replace: err = %v, services = %+v", err, got)
+	}
+	// Adding what is already there is a no-op, so a replayed fix is idempotent.
+	if _, err := ApplyOps(got, []PatchOp{{Op: OpAdd, Path: "/services/billing/type", Value: "database"}}); err != nil {
+		t.Errorf("re-adding an equal value: %v", err)
+	}
+}
@@ -0,0 +1,271 @@
+package spec
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
+)
+
+// PatchYAML rewrites doc, the user's YAML that before was parsed from, so that it describes
+// after. Only what differs between before and after is edited on the yaml.Node tree: comments,
+// key order, anchors elsewhere and keys YSpec does not know are kept. List items are matched by
+// name (services, datastores, topics, apis), from/to (dependencies) or to (calls). doc is
+// returned unchanged when before and after are equal.
+func PatchYAML(doc []byte, before, after *parser.YSpec) ([]byte, error) {
+	var b, a yaml.Node
+	if err := b.Encode(before); err != nil {
+		return nil, err
+	}
+	if err := a.Encode(after); err != nil {
+		return nil, err
+	}
+	if sameNode(&b, &a) {
+		return doc, nil
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(doc, &root); err != nil {
+		return nil, err
+	}
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 {
+		return yaml.Marshal(after)
+	}
+	patchNode(root.Content[0], &b, &a)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(indentOf(doc))
+	if err := enc.Encode(&root); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// indentOf guesses the indentation step of doc (the smallest indentation of a content line).
+func indentOf(doc []byte) int {
+	indent := 0
+	for _, line := range strings.Split(string(doc), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		n := len(line) - len(trimmed)
+		if n == 0 || trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if indent == 0 || n < indent {
+			indent = n
+		}
+	}
+	if indent < 2 {
+		return 2
+	}
+	return indent
+}
+
+// patchNode edits dst (the user's node for before) so that it matches after.
+func patchNode(dst, before, after *yaml.Node) {
+	if sameNode(before, after) {
+		return
+	}
+	switch {
+	case dst.Kind == yaml.AliasNode:
+		// Editing the anchored node would change every alias of it; break this one out instead.
+		replaceNode(dst, after)
+	case dst.Kind == yaml.MappingNode && before.Kind == yaml.MappingNode && after.Kind == yaml.MappingNode:
+		patchMapping(dst, before, after)
+	case dst.Kind == yaml.SequenceNode && before.Kind == yaml.SequenceNode && after.Kind == yaml.SequenceNode:
+		if !patchKeyedSequence(dst, before, after) && !patchScalarSequence(dst, before, after) {
+			replaceNode(dst, after)
+		}
+	default:
+		replaceNode(dst, after)
+	}
+}
+
+func patchMapping(dst, before, after *yaml.Node) {
+	for i := 0; i+1 < len(after.Content); i += 2 {
+		key, av := after.Content[i], after.Content[i+1]
+		bi, di := valueIndex(before, key.Value), valueIndex(dst, key.Value)
+		switch {
+		case di < 0:
+			dst.Content = append(dst.Content, copyNode(key), copyNode(av))
+		case bi < 0:
+			replaceNode(dst.Content[di], av)
+		default:
+			patchNode(dst.Content[di], before.Content[bi], av)
+		}
+	}
+	for i := 0; i+1 < len(before.Content); i += 2 {
+		key, bv := before.Content[i], before.Content[i+1]
+		if valueIndex(after, key.Value) >= 0 {
+			continue
+		}
+		di := valueIndex(dst, key.Value)
+		if di < 0 {
+			continue
+		}
+		// Encoding drops false booleans (omitempty); say false rather than deleting the key.
+		if bv.Kind == yaml.ScalarNode && bv.Tag == "!!bool" {
+			replaceNode(dst.Content[di], &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "false"})
+			continue
+		}
+		dst.Content = append(dst.Content[:di-1], dst.Content[di+1:]...)
+	}
+}
+
+// patchKeyedSequence patches a list of mappings item by item; false when the items have no
+// identity (see itemKey) or share one.
+func patchKeyedSequence(dst, before, after *yaml.Node) bool {
+	bk, ok1 := keyedItems(before)
+	ak, ok2 := keyedItems(after)
+	dk, ok3 := keyedItems(dst)
+	if !ok1 || !ok2 || !ok3 {
+		return false
+	}
+	kept := dst.Content[:0]
+	for _, item := range dst.Content {
+		k, _ := itemKey(item)
+		if ak[k] == nil && bk[k] != nil {
+			continue
+		}
+		kept = append(kept, item)
+	}
+	dst.Content = kept
+	for _, a := range after.Content {
+		k, _ := itemKey(a)
+		d := dk[k]
+		switch {
+		case d == nil:
+			appendItem(dst, a)
+		case bk[k] == nil:
+			replaceNode(d, a)
+		default:
+			patchNode(d, bk[k], a)
+		}
+	}
+	return true
+}
+
+// patchScalarSequence patches a list of scalars (e.g. endpoints) as a set; false when an item
+// is not a scalar.
+func patchScalarSequence(dst, before, after *yaml.Node) bool {
+	values := func(n *yaml.Node) (map[string]bool, bool) {
+		set := map[string]bool{}
+		for _, it := range n.Content {
+			if it.Kind != yaml.ScalarNode {
+				return nil, false
+			}
+			set[it.Value] = true
+		}
+		return set, true
+	}
+	inB, ok1 := values(before)
+	inA, ok2 := values(after)
+	inD, ok3 := values(dst)
+	if !ok1 || !ok2 || !ok3 {
+		return false
+	}
+	kept := dst.Content[:0]
+	for _, it := range dst.Content {
+		if inB[it.Value] && !inA[it.Value] {
+			continue
+		}
+		kept = append(kept, it)
+	}
+	dst.Content = kept
+	for _, it := range after.Content {
+		if !inD[it.Value] {
+			appendItem(dst, it)
+			inD[it.Value] = true
+		}
+	}
+	return true
+}
+
+// appendItem appends a copy of item to seq, quoted like the scalars already there.
+func appendItem(seq, item *yaml.Node) {
+	c := copyNode(item)
+	if len(seq.Content) == 0 && c.Kind != yaml.ScalarNode {
+		// An empty "[]" would otherwise stay in flow style with the new item inlined.
+		seq.Style &^= yaml.FlowStyle
+	}
+	if n := len(seq.Content); n > 0 && c.Kind == yaml.ScalarNode && seq.Content[n-1].ShortTag() == c.ShortTag() {
+		c.Style = seq.Content[n-1].Style
+	}
+	seq.Content = append(seq.Content, c)
+}
+
+// itemKey is the identity of a list item node, as ItemKey is for spec trees.
+func itemKey(n *yaml.Node) (string, bool) {
+	if n.Kind != yaml.MappingNode {
+		return "", false
+	}
+	return identity(func(k string) string {
+		if i := valueIndex(n, k); i >= 0 {
+			return n.Content[i].Value
+		}
+		return ""
+	})
+}
+
+func keyedItems(seq *yaml.Node) (map[string]*yaml.Node, bool) {
+	out := map[string]*yaml.Node{}
+	for _, it := range seq.Content {
+		k, ok := itemKey(it)
+		if !ok || out[k] != nil {
+			return nil, false
+		}
+		out[k] = it
+	}
+	return out, true
+}
+
+// valueIndex returns the index in n.Content of the value for key, or -1.
+func valueIndex(n *yaml.Node, key string) int {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// replaceNode overwrites dst with a copy of src, keeping dst's comments and, for a scalar of
+// the same type, its quoting style.
+func replaceNode(dst, src *yaml.Node) {
+	c := copyNode(src)
+	c.HeadComment, c.LineComment, c.FootComment = dst.HeadComment, dst.LineComment, dst.FootComment
+	if dst.Kind == yaml.ScalarNode && c.Kind == yaml.ScalarNode && dst.ShortTag() == c.ShortTag() {
+		c.Style = dst.Style
+	}
+	*dst = *c
+}
+
+func copyNode(n *yaml.Node) *yaml.Node {
+	c := *n
+	c.Content = make([]*yaml.Node, len(n.Content))
+	for i, ch := range n.Content {
+		c.Content[i] = copyNode(ch)
+	}
+	return &c
+}
+
+// sameNode compares two nodes by the values they decode to.
+func sameNode(a, b *yaml.Node) bool {
+	var va, vb any
+	if err := a.Decode(&va); err != nil {
+		return false
+	}
+	if err := b.Decode(&vb); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
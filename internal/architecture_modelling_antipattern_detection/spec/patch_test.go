@@ -0,0 +1,120 @@
+package spec
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
+)
+
+const patchDoc = `# Checkout architecture
+services:
+  - name: orders # owned by team A
+    type: svc
+    owner: team-a
+  - name: billing
+    type: service
+dependencies:
+  # orders charges synchronously
+  - from: orders
+    to: billing
+    kind: rest
+    sync: true
+  - to: orders
+    from: billing
+    kind: rest
+    sync: true
+`
+
+func mustParse(t *testing.T, doc string) *parser.YSpec {
+	t.Helper()
+	s, err := parser.ParseYAMLString(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// changedLines returns the lines of b that are not lines of a.
+func changedLines(a, b string) []string {
+	seen := map[string]int{}
+	for _, l := range strings.Split(a, "\n") {
+		seen[l]++
+	}
+	var out []string
+	for _, l := range strings.Split(b, "\n") {
+		if seen[l] > 0 {
+			seen[l]--
+			continue
+		}
+		out = append(out, l)
+	}
+	return out
+}
+
+func TestPatchYAML_MinimalEdit(t *testing.T) {
+	before := mustParse(t, patchDoc)
+	after := mustParse(t, patchDoc)
+	after.Dependencies[0].Sync = false
+	after.Dependencies = after.Dependencies[:1]
+	after.Services = append(after.Services, parser.YService{Name: "audit", Type: "service"})
+
+	out, err := PatchYAML([]byte(patchDoc), before, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(out)
+	for _, keep := range []string{"# Checkout architecture", "# owned by team A", "# orders charges synchronously", "owner: team-a", "type: svc"} {
+		if !strings.Contains(got, keep) {
+			t.Errorf("lost %q:\n%s", keep, got)
+		}
+	}
+	if strings.Contains(got, "from: billing") {
+		t.Errorf("billing -> orders should be removed:\n%s", got)
+	}
+	added := changedLines(patchDoc, got)
+	if len(added) != 3 || added[0] != "  - name: audit" || added[1] != "    type: service" || added[2] != "    sync: false" {
+		t.Errorf("changed lines = %q\n%s", added, got)
+	}
+
+	diff, err := UnifiedDiff("a.yaml", "b.yaml", []byte(patchDoc), out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff, "-    sync: true\n+    sync: false\n") || !strings.Contains(diff, "-  - to: orders\n") {
+		t.Errorf("diff:\n%s", diff)
+	}
+}
+
+func TestPatchYAML_Unchanged(t *testing.T) {
+	s := mustParse(t, patchDoc)
+	out, err := PatchYAML([]byte(patchDoc), s, mustParse(t, patchDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != patchDoc {
+		t.Fatalf("expected the document back unchanged, got:\n%s", out)
+	}
+	if diff, _ := UnifiedDiff("a", "b", []byte(patchDoc), out); diff != "" {
+		t.Fatalf("diff = %q", diff)
+	}
+}
+
+func TestPatchYAML_EndpointsAsSet(t *testing.T) {
+	doc := `services:
+  - name: orders
+    calls:
+      - to: billing
+        endpoints: ["POST /charge", "POST /refund"]
+`
+	before := mustParse(t, doc)
+	after := mustParse(t, doc)
+	after.Services[0].Calls[0].Endpoints = []string{"POST /charge", "GET /invoice"}
+	out, err := PatchYAML([]byte(doc), before, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), `endpoints: ["POST /charge", "GET /invoice"]`) {
+		t.Fatalf("got:\n%s", out)
+	}
+}
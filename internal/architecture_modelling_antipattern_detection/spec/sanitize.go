@@ -1,6 +1,10 @@
 package spec
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
+)
 
 func cleanRef(s string) string {
 	t := strings.TrimSpace(s)
@@ -28,7 +32,7 @@ func cleanRef(s string) string {
 	return t
 }
 
-// canonicalServiceYAMLType normalizes services[].type for merge/sanitize (must stay in sync
+// canonicalServiceYAMLType normalizes services[].type for sanitize (must stay in sync
 // with ingest/mapper normalizeType).
 func canonicalServiceYAMLType(t string) string {
 	t = strings.ToLower(strings.TrimSpace(t))
@@ -52,48 +56,21 @@ func canonicalServiceYAMLType(t string) string {
 	}
 }
 
-func Sanitize(root map[string]any) {
-	if root == nil {
+// SanitizeSpec cleans references (leaked SERVICE:/DATABASE: prefixes), dependency kinds and
+// service types in place, so fixed specs are written in canonical form.
+func SanitizeSpec(s *parser.YSpec) {
+	if s == nil {
 		return
 	}
-
-	if deps, ok := root["dependencies"].([]any); ok {
-		for _, it := range deps {
-			m, ok := it.(map[string]any)
-			if !ok {
-				continue
-			}
-			if from, ok := m["from"].(string); ok {
-				m["from"] = cleanRef(from)
-			}
-			if to, ok := m["to"].(string); ok {
-				m["to"] = cleanRef(to)
-			}
-			if kind, ok := m["kind"].(string); ok {
-				m["kind"] = strings.ToLower(strings.TrimSpace(kind))
-			}
-		}
+	for i := range s.Dependencies {
+		d := &s.Dependencies[i]
+		d.From = cleanRef(d.From)
+		d.To = cleanRef(d.To)
+		d.Kind = strings.ToLower(strings.TrimSpace(d.Kind))
 	}
-
-	if svcs, ok := root["services"].([]any); ok {
-		for _, it := range svcs {
-			m, ok := it.(map[string]any)
-			if !ok {
-				continue
-			}
-			if name, ok := m["name"].(string); ok {
-				m["name"] = cleanRef(name)
-			}
-			if typ, ok := m["type"].(string); ok {
-				t := strings.TrimSpace(typ)
-				if t == "" {
-					m["type"] = "service"
-				} else {
-					m["type"] = canonicalServiceYAMLType(t)
-				}
-			} else {
-				m["type"] = "service"
-			}
-		}
+	for i := range s.Services {
+		svc := &s.Services[i]
+		svc.Name = cleanRef(svc.Name)
+		svc.Type = canonicalServiceYAMLType(svc.Type)
 	}
 }
@@ -13,10 +13,14 @@ import (
 )
 
 type Suggestion struct {
-	ID      string                 `json:"id" yaml:"id"`
-	Kind    domain.AntiPatternKind `json:"kind" yaml:"kind"`
-	Title   string                 `json:"title" yaml:"title"`
-	Bullets []string               `json:"bullets" yaml:"bullets"`
+	ID    string                 `json:"id" yaml:"id"`
+	Kind  domain.AntiPatternKind `json:"kind" yaml:"kind"`
+	Title string                 `json:"title" yaml:"title"`
+	// Bullets are the refactoring options listed in Guidance.
+	Bullets []string `json:"bullets" yaml:"bullets"`
+	// Guidance is Markdown rendered from the kind's template with the detection evidence
+	// (see Guidance).
+	Guidance string `json:"guidance,omitempty" yaml:"guidance,omitempty"`
 
 	// PreviewFrom / PreviewTo preserve dependency direction for UI previews.
 	// Suggestion id uses sorted node lists; these optional fields keep caller → callee order.
@@ -27,6 +31,12 @@ type Suggestion struct {
 
 	AutoFixApplied bool     `json:"auto_fix_applied" yaml:"auto_fix_applied"`
 	AutoFixNotes   []string `json:"auto_fix_notes,omitempty" yaml:"auto_fix_notes,omitempty"`
+	// AutoFixPatch is the edit the fix made to the spec; it is stored with the fixed version so
+	// the fix can be undone (spec.InvertOps) or replayed on another version.
+	AutoFixPatch []specpkg.PatchOp `json:"auto_fix_patch,omitempty" yaml:"auto_fix_patch,omitempty"`
+
+	// Impact is the estimated effect of applying this suggestion alone (see EstimateImpacts).
+	Impact *Impact `json:"impact,omitempty" yaml:"impact,omitempty"`
 }
 
 // DetectionKey returns a stable unique key for a detection (kind|nodes).
@@ -89,16 +99,14 @@ func BuildSuggestions(g *domain.Graph, dets []domain.Detection) []Suggestion {
 
 		s := findStrategy(d.Kind)
 		if s == nil {
-			out = append(out, Suggestion{
-				ID:      key,
-				Kind:    d.Kind,
-				Title:   d.Title,
-				Bullets: []string{"No suggestion strategy found for this anti-pattern yet."},
-			})
+			sug := Suggestion{ID: key, Kind: d.Kind, Title: d.Title}
+			withGuidance(&sug, d)
+			out = append(out, sug)
 			continue
 		}
 		sug := s.Suggest(g, d)
 		sug.ID = key
+		withGuidance(&sug, d)
 		out = append(out, sug)
 	}
 	return out
@@ -154,66 +162,30 @@ func ApplyFixesYAMLBytes(yamlBytes []byte, g *domain.Graph, dets []domain.Detect
 }
 
 // ApplyFixesYAMLBytesFiltered applies fixes only for detections whose DetectionKey is in selectedIDs.
-// When selectedIDs is nil or empty, no fixes are applied (user must explicitly select suggestions).
+// When selectedIDs is empty, no fixes are applied (user must explicitly select suggestions).
+// Fixes run in PlanFixes order on the given graph; see ApplyFixPlan to re-detect between fixes.
+// The fixes are written back as minimal edits of yamlBytes (see spec.PatchYAML), so comments,
+// key order and untouched services and dependencies stay as the user wrote them.
 func ApplyFixesYAMLBytesFiltered(yamlBytes []byte, g *domain.Graph, dets []domain.Detection, selectedIDs map[string]bool) ([]byte, []Suggestion, error) {
-	origRaw, err := specpkg.UnmarshalMap(yamlBytes)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	specStruct, err := parser.ParseYAMLBytes(yamlBytes)
-	if err != nil {
-		return nil, nil, err
-	}
-	mapper.NormalizeYAMLSpecInPlace(specStruct)
-
-	tmp := make([]domain.Detection, 0, len(dets))
-	tmp = append(tmp, dets...)
-	sort.SliceStable(tmp, func(i, j int) bool {
-		return severityWeight(tmp[i].Severity) > severityWeight(tmp[j].Severity)
-	})
-
-	var applied []Suggestion
-	for _, d := range tmp {
-		// If selection filter is provided, only apply selected detections
-		if selectedIDs != nil {
-			if len(selectedIDs) == 0 {
-				continue
-			}
-			if !selectedIDs[DetectionKey(d)] {
-				continue
-			}
-		}
-		s := findStrategy(d.Kind)
-		if s == nil {
-			continue
-		}
-		changed, notes := s.Apply(specStruct, g, d)
-		if changed {
-			ss := s.Suggest(g, d)
-			ss.AutoFixApplied = true
-			ss.AutoFixNotes = notes
-			applied = append(applied, ss)
-		}
-	}
+	out, applied, _, err := ApplyFixPlan(yamlBytes, g, dets, selectedIDs, nil)
+	return out, applied, err
+}
 
-	fixedBytes, err := marshalSpec(specStruct)
-	if err != nil {
-		return nil, nil, err
-	}
-	fixedRaw, err := specpkg.UnmarshalMap(fixedBytes)
+// ApplyOpsYAMLBytes applies ops (e.g. a stored fix patch or its inverse) to yamlBytes, parsed and
+// normalized as ApplyFixesYAMLBytesFiltered does, and writes the result back as minimal edits.
+// An op that no longer applies fails with an error wrapping spec.ErrOpConflict.
+func ApplyOpsYAMLBytes(yamlBytes []byte, ops []specpkg.PatchOp) ([]byte, error) {
+	origSpec, err := parser.ParseYAMLBytes(yamlBytes)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
+	mapper.NormalizeYAMLSpecInPlace(origSpec)
+	specpkg.SanitizeSpec(origSpec)
 
-	specpkg.Sanitize(fixedRaw)
-
-	merged := specpkg.Merge(origRaw, fixedRaw)
-
-	out, err := specpkg.MarshalMap(merged)
+	patched, err := specpkg.ApplyOps(origSpec, ops)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-
-	return out, applied, nil
+	specpkg.SanitizeSpec(patched)
+	return specpkg.PatchYAML(yamlBytes, origSpec, patched)
 }
@@ -0,0 +1,226 @@
+package suggestion
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/mapper"
+)
+
+//go:embed templates/*.md
+var templateFiles embed.FS
+
+// GuidanceDirEnv names a directory of <kind>.md files that override the built-in guidance
+// templates. Files are read on every render, so edits apply without a rebuild or restart.
+const GuidanceDirEnv = "AMG_APD_GUIDANCE_DIR"
+
+// Template sources reported by GuidanceTemplate.
+const (
+	SourceOverride = "override"
+	SourceBuiltin  = "builtin"
+	SourceDefault  = "default"
+)
+
+// guidanceData is what a guidance template is executed with. Evidence is the detection's
+// evidence as the rule produced it (see detection/rules).
+type guidanceData struct {
+	Kind     string
+	Severity string
+	Title    string
+	Summary  string
+	Nodes    []string
+	Evidence domain.Attrs
+}
+
+var guidanceFuncs = template.FuncMap{
+	// name strips the graph-ID prefix of a node reference ("SERVICE:orders" -> "orders").
+	"name": func(v any) string { return mapper.StripNodeNameRef(fmt.Sprint(v)) },
+	// names renders a list of node references as "a, b and c".
+	"names": func(v any) string {
+		var out []string
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Slice {
+			for i := 0; i < rv.Len(); i++ {
+				out = append(out, mapper.StripNodeNameRef(fmt.Sprint(rv.Index(i).Interface())))
+			}
+		} else if v != nil {
+			out = append(out, mapper.StripNodeNameRef(fmt.Sprint(v)))
+		}
+		if len(out) < 2 {
+			return strings.Join(out, "")
+		}
+		return strings.Join(out[:len(out)-1], ", ") + " and " + out[len(out)-1]
+	},
+}
+
+// TemplateInfo is the effective guidance template of one kind.
+type TemplateInfo struct {
+	Kind     domain.AntiPatternKind `json:"kind" yaml:"kind"`
+	Source   string                 `json:"source" yaml:"source"`
+	Template string                 `json:"template" yaml:"template"`
+	// Error is set when an override does not parse; the built-in template is used instead.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// templateKind reports whether kind can name a template file. Custom rules define their own
+// kinds, so one with a path separator or ".." must not reach outside the template directory.
+func templateKind(kind domain.AntiPatternKind) bool {
+	k := string(kind)
+	return k != "" && !strings.ContainsAny(k, `/\`) && !strings.Contains(k, "..")
+}
+
+// GuidanceTemplate returns the template used for kind: the override from GuidanceDirEnv when
+// there is one that parses, else the built-in one, else the generic default (always for a kind
+// that is not a plain file name).
+func GuidanceTemplate(kind domain.AntiPatternKind) TemplateInfo {
+	info := TemplateInfo{Kind: kind}
+	if !templateKind(kind) {
+		b, _ := templateFiles.ReadFile("templates/default.md")
+		info.Source, info.Template = SourceDefault, string(b)
+		return info
+	}
+	if dir := os.Getenv(GuidanceDirEnv); dir != "" {
+		if b, err := os.ReadFile(filepath.Join(dir, string(kind)+".md")); err == nil {
+			if _, err := parseGuidance(kind, string(b)); err != nil {
+				info.Error = err.Error()
+			} else {
+				info.Source, info.Template = SourceOverride, string(b)
+				return info
+			}
+		}
+	}
+	if b, err := templateFiles.ReadFile("templates/" + string(kind) + ".md"); err == nil {
+		info.Source, info.Template = SourceBuiltin, string(b)
+		return info
+	}
+	b, _ := templateFiles.ReadFile("templates/default.md")
+	info.Source, info.Template = SourceDefault, string(b)
+	return info
+}
+
+// GuidanceTemplates lists the effective template of every built-in kind, sorted by kind.
+func GuidanceTemplates() []TemplateInfo {
+	entries, _ := templateFiles.ReadDir("templates")
+	var out []TemplateInfo
+	for _, e := range entries {
+		kind := strings.TrimSuffix(e.Name(), ".md")
+		if kind == SourceDefault {
+			continue
+		}
+		out = append(out, GuidanceTemplate(domain.AntiPatternKind(kind)))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Kind < out[j].Kind })
+	return out
+}
+
+// Guidance renders the Markdown guidance for d (problem, consequences, refactoring options and
+// trade-offs) from its kind's template and evidence. An override that fails to render falls
+// back to the built-in template; "" when no template renders.
+func Guidance(d domain.Detection) string {
+	data := guidanceData{
+		Kind:     string(d.Kind),
+		Severity: string(d.Severity),
+		Title:    d.Title,
+		Summary:  d.Summary,
+		Nodes:    d.Nodes,
+		Evidence: d.Evidence,
+	}
+	if data.Evidence == nil {
+		data.Evidence = domain.Attrs{}
+	}
+	info := GuidanceTemplate(d.Kind)
+	out, err := renderGuidance(d.Kind, info.Template, data)
+	if err != nil && info.Source == SourceOverride { // an override implies a valid kind
+		if b, rerr := templateFiles.ReadFile("templates/" + string(d.Kind) + ".md"); rerr == nil {
+			out, err = renderGuidance(d.Kind, string(b), data)
+		}
+	}
+	if err != nil {
+		return ""
+	}
+	return out
+}
+
+func parseGuidance(kind domain.AntiPatternKind, text string) (*template.Template, error) {
+	return template.New(string(kind)).Funcs(guidanceFuncs).Parse(text)
+}
+
+func renderGuidance(kind domain.AntiPatternKind, text string, data guidanceData) (string, error) {
+	t, err := parseGuidance(kind, text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()) + "\n", nil
+}
+
+// withGuidance sets the guidance of s rendered for d and the bullets derived from it.
+func withGuidance(s *Suggestion, d domain.Detection) {
+	s.Guidance = Guidance(d)
+	s.Bullets = GuidanceBullets(s.Guidance)
+}
+
+// GuidanceBullets returns the items of the "Refactoring options" section of rendered guidance,
+// one string each with continuation lines joined and bold markers dropped. A section without a
+// list is returned as one item; empty when there is no such section.
+func GuidanceBullets(guidance string) []string {
+	out := []string{}
+	var cur []string
+	flush := func() {
+		if len(cur) > 0 {
+			out = append(out, strings.ReplaceAll(strings.Join(cur, " "), "**", ""))
+			cur = nil
+		}
+	}
+	in := false
+	for _, line := range strings.Split(guidance, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "## ") {
+			if in {
+				break
+			}
+			in = strings.EqualFold(strings.TrimSpace(trimmed[3:]), "Refactoring options")
+			continue
+		}
+		if !in {
+			continue
+		}
+		switch item := listItem(trimmed); {
+		case trimmed == "":
+			flush()
+		case item != "":
+			flush()
+			cur = append(cur, item)
+		default:
+			cur = append(cur, trimmed)
+		}
+	}
+	flush()
+	return out
+}
+
+// listItem returns the text of a Markdown list item ("- x", "* x", "1. x"), or "".
+func listItem(line string) string {
+	if strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") {
+		return strings.TrimSpace(line[2:])
+	}
+	i := 0
+	for i < len(line) && line[i] >= '0' && line[i] <= '9' {
+		i++
+	}
+	if i > 0 && strings.HasPrefix(line[i:], ". ") {
+		return strings.TrimSpace(line[i+2:])
+	}
+	return ""
+}
@@ -0,0 +1,148 @@
+package suggestion
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection"
+	_ "github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection/rules"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+)
+
+// sampleDetections has one detection per kind with the evidence its rule produces.
+var sampleDetections = []domain.Detection{
+	{Kind: domain.APCycles, Nodes: []string{"SERVICE:a", "SERVICE:b", "SERVICE:c"}, Evidence: domain.Attrs{"cycle_size": 3}},
+	{Kind: domain.APGodService, Nodes: []string{"SERVICE:hub"}, Evidence: domain.Attrs{"degree": 9, "threshold": 6}},
+	{Kind: domain.APTightCoupling, Nodes: []string{"SERVICE:a", "SERVICE:b"}, Evidence: domain.Attrs{"ab": 2, "ba": 1, "ra": 0.5, "rb": 0.4, "min_bidir": 1, "ratio": 0.3}},
+	{Kind: domain.APSharedDatabase, Nodes: []string{"DATABASE:db", "SERVICE:a", "SERVICE:b"}, Evidence: domain.Attrs{"db": "DATABASE:db", "clients": 2, "min_clients": 2}},
+	{Kind: domain.APSyncCallChain, Nodes: []string{"SERVICE:a", "SERVICE:b", "SERVICE:c", "SERVICE:d"}, Evidence: domain.Attrs{"edges": 3, "min_edges": 3, "approximate": true}},
+	{Kind: domain.APPingPongDependency, Nodes: []string{"SERVICE:a", "SERVICE:b"}, Evidence: domain.Attrs{"a": "SERVICE:a", "b": "SERVICE:b"}},
+	{Kind: domain.APReverseDependency, Nodes: []string{"SERVICE:api", "CLIENT:web"}, Evidence: domain.Attrs{"from": "SERVICE:api", "to": "CLIENT:web"}},
+	{Kind: domain.APUIOrchestrator, Nodes: []string{"CLIENT:web", "SERVICE:a", "SERVICE:b"}, Evidence: domain.Attrs{"ui": "CLIENT:web", "targets": 2, "sync_targets": 2, "min_out": 2}},
+	chattyDetection(),
+	{Kind: domain.APSinglePointOfFailure, Nodes: []string{"SERVICE:auth", "SERVICE:db"}, Evidence: domain.Attrs{
+		"node": "SERVICE:auth", "affected_clients": []string{"SERVICE:a"}, "unreachable": []string{"SERVICE:db"},
+		"broken_paths": []domain.Attrs{{"client": "SERVICE:a", "target": "SERVICE:db", "path": "a -> auth -> db", "sync": true}},
+		"broken_count": 1, "sync_broken": true, "bridges": []string{},
+	}},
+	{Kind: domain.APOrphanEventTopic, Nodes: []string{"EVENT_TOPIC:t", "SERVICE:a"}, Evidence: domain.Attrs{"topic": "EVENT_TOPIC:t", "reason": "no_consumers", "producers": []string{"SERVICE:a"}, "consumers": []string{}}},
+	{Kind: domain.APSelfConsumingService, Nodes: []string{"SERVICE:a", "EVENT_TOPIC:t"}, Evidence: domain.Attrs{"service": "SERVICE:a", "topic": "EVENT_TOPIC:t"}},
+	{Kind: domain.APEventLoop, Nodes: []string{"SERVICE:a", "SERVICE:b", "EVENT_TOPIC:t"}, Evidence: domain.Attrs{
+		"services": []string{"SERVICE:a", "SERVICE:b"}, "topics": []string{"EVENT_TOPIC:t"}, "loop_size": 2,
+		"event_legs": []domain.Attrs{{"from": "SERVICE:a", "topic": "EVENT_TOPIC:t", "to": "SERVICE:b"}},
+	}},
+}
+
+// chattyDetection is what the chatty_service rule reports for a caller with one per-item call
+// and one high-rate call.
+func chattyDetection() domain.Detection {
+	g := domain.NewGraph()
+	for _, n := range []string{"a", "b", "c"} {
+		g.AddNode(&domain.Node{ID: "SERVICE:" + n, Name: n, Kind: domain.NodeService})
+	}
+	g.AddEdge(&domain.Edge{From: "SERVICE:a", To: "SERVICE:b", Kind: domain.EdgeCalls, Attrs: domain.Attrs{"sync": true, "per_item": true}})
+	g.AddEdge(&domain.Edge{From: "SERVICE:a", To: "SERVICE:c", Kind: domain.EdgeCalls, Attrs: domain.Attrs{"sync": true, "rate_per_min": 1200}})
+	for _, d := range detection.All() {
+		if d.Name() != string(domain.APChattyService) {
+			continue
+		}
+		if dets, err := d.Detect(g, nil); err == nil && len(dets) == 1 {
+			return dets[0]
+		}
+	}
+	panic("chatty_service did not report the sample graph")
+}
+
+func TestGuidance_EveryKind(t *testing.T) {
+	t.Setenv(GuidanceDirEnv, "")
+	if got, want := len(GuidanceTemplates()), len(sampleDetections); got != want {
+		t.Errorf("%d built-in templates, want one per kind (%d)", got, want)
+	}
+	for _, d := range sampleDetections {
+		if info := GuidanceTemplate(d.Kind); info.Source != SourceBuiltin {
+			t.Errorf("%s: template source %q", d.Kind, info.Source)
+		}
+		g := Guidance(d)
+		for _, section := range []string{"## Problem", "## Consequences", "## Refactoring options", "## Trade-offs"} {
+			if !strings.Contains(g, section) {
+				t.Errorf("%s: missing %q:\n%s", d.Kind, section, g)
+			}
+		}
+		if strings.Contains(g, "<no value>") || strings.Contains(g, "SERVICE:") {
+			t.Errorf("%s: unfilled or raw references:\n%s", d.Kind, g)
+		}
+	}
+	if g := Guidance(sampleDetections[0]); !strings.Contains(g, "a, b and c depend on each other in a cycle of 3 services") {
+		t.Errorf("cycles guidance:\n%s", g)
+	}
+	if g := Guidance(chattyDetection()); !strings.Contains(g, "Calls to b are made once per item") ||
+		!strings.Contains(g, "/min:\n\n- c: 1200/min\n") || !strings.Contains(g, "Per-item calls:\n\n- b\n") {
+		t.Errorf("chatty guidance:\n%s", g)
+	}
+	if g := Guidance(domain.Detection{Kind: "custom_rule", Title: "Custom", Summary: "Something", Nodes: []string{"SERVICE:x"}}); !strings.Contains(g, "Affected: x.") {
+		t.Errorf("default guidance:\n%s", g)
+	}
+}
+
+func TestGuidance_Override(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(GuidanceDirEnv, dir)
+	write := func(kind, text string) {
+		if err := os.WriteFile(filepath.Join(dir, kind+".md"), []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	d := sampleDetections[1]
+
+	write("god_service", "Split {{names .Nodes}} ({{.Evidence.degree}} dependencies).")
+	if info := GuidanceTemplate(d.Kind); info.Source != SourceOverride {
+		t.Fatalf("source = %q", info.Source)
+	}
+	if g := Guidance(d); g != "Split hub (9 dependencies).\n" {
+		t.Errorf("override guidance = %q", g)
+	}
+
+	// A broken override is reported and the built-in template is used.
+	write("god_service", "{{.Evidence.degree")
+	info := GuidanceTemplate(d.Kind)
+	if info.Source != SourceBuiltin || info.Error == "" {
+		t.Errorf("broken override: %+v", info)
+	}
+	if g := Guidance(d); !strings.Contains(g, "## Problem") {
+		t.Errorf("fallback guidance:\n%s", g)
+	}
+}
+
+func TestGuidanceBullets(t *testing.T) {
+	t.Setenv(GuidanceDirEnv, "")
+	got := GuidanceBullets(Guidance(sampleDetections[0]))
+	if len(got) != 3 || !strings.HasPrefix(got[0], "Remove the weakest dependency. Find the call") ||
+		strings.Contains(got[0], "**") || strings.Contains(got[0], "\n") {
+		t.Errorf("cycles bullets: %q", got)
+	}
+	if got := GuidanceBullets(Guidance(domain.Detection{Kind: "custom_rule"})); len(got) != 1 || !strings.HasPrefix(got[0], "Review the affected services") {
+		t.Errorf("default bullets: %q", got)
+	}
+	if got := GuidanceBullets("## Problem\n\nNone.\n"); got == nil || len(got) != 0 {
+		t.Errorf("no options section: %#v", got)
+	}
+}
+
+func TestGuidanceTemplate_PathLikeKindUsesDefault(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "templates")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "secret.md"), []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(GuidanceDirEnv, sub)
+	for _, kind := range []domain.AntiPatternKind{"../secret", `..\secret`, "x/../../secret"} {
+		if info := GuidanceTemplate(kind); info.Source != SourceDefault || strings.Contains(info.Template, "secret") {
+			t.Errorf("%q: %+v", kind, info)
+		}
+	}
+}
@@ -1,12 +1,6 @@
 package suggestion
 
-import (
-	"strings"
-
-	"gopkg.in/yaml.v3"
-
-	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
-)
+import "strings"
 
 func join(xs []string, sep string) string {
 	if len(xs) == 0 {
@@ -21,7 +15,3 @@ func join(xs []string, sep string) string {
 	}
 	return b.String()
 }
-
-func marshalSpec(spec *parser.YSpec) ([]byte, error) {
-	return yaml.Marshal(spec)
-}
@@ -0,0 +1,234 @@
+package suggestion
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/mapper"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/scoring"
+	specpkg "github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/spec"
+)
+
+// Effort levels of an Impact.
+const (
+	EffortNone   = "none"
+	EffortLow    = "low"
+	EffortMedium = "medium"
+	EffortHigh   = "high"
+)
+
+// Impact is what applying one suggestion's auto-fix alone would do, estimated by a dry run.
+type Impact struct {
+	// AutoFix is false when the suggestion has no auto-fix that changes anything; the risk
+	// fields then stay at the current score.
+	AutoFix bool `json:"auto_fix" yaml:"auto_fix"`
+	// RiskBefore and RiskAfter are scoring.RiskScore of the detections before and after the
+	// fix; RiskDelta is RiskAfter - RiskBefore, so negative means less risk.
+	RiskBefore int `json:"risk_before" yaml:"risk_before"`
+	RiskAfter  int `json:"risk_after" yaml:"risk_after"`
+	RiskDelta  int `json:"risk_delta" yaml:"risk_delta"`
+	// Resolves and Introduces are the detection keys (see DetectionKey) the fix removes and adds.
+	Resolves   []string `json:"resolves" yaml:"resolves"`
+	Introduces []string `json:"introduces" yaml:"introduces"`
+	// ServicesAdded, ServicesRemoved and DependenciesChanged size the change for Effort.
+	ServicesAdded       int    `json:"services_added" yaml:"services_added"`
+	ServicesRemoved     int    `json:"services_removed" yaml:"services_removed"`
+	DependenciesChanged int    `json:"dependencies_changed" yaml:"dependencies_changed"`
+	Effort              string `json:"effort" yaml:"effort"`
+	// Rank orders the suggestions by benefit: 1 is the largest risk reduction, ties going to
+	// the smaller effort. Suggestions without an auto-fix rank last.
+	Rank int `json:"rank" yaml:"rank"`
+	// Error is set when the fixed spec could not be analyzed, or was not because the request
+	// had more distinct fixes than maxImpactDryRuns (the fix is then not ranked on risk).
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// maxImpactDryRuns caps the detection runs of one EstimateImpacts call. Suggestions come in
+// severity order, so the most severe are estimated first; fixes making the same edit as an
+// earlier one share its run.
+var maxImpactDryRuns = 16
+
+// EstimateImpacts dry-runs the auto-fix of each suggestion on its own copy of the spec parsed
+// from yamlBytes and of g, re-runs detection with detect, and sets Suggestion.Impact. Detection
+// runs once per distinct edit and at most maxImpactDryRuns times. sugs keep their order;
+// Impact.Rank gives the order by benefit (see SortByBenefit).
+func EstimateImpacts(yamlBytes []byte, g *domain.Graph, dets []domain.Detection, sugs []Suggestion, detect DetectFunc) error {
+	base, err := parser.ParseYAMLBytes(yamlBytes)
+	if err != nil {
+		return err
+	}
+	mapper.NormalizeYAMLSpecInPlace(base)
+	specpkg.SanitizeSpec(base)
+	baseTree, err := specpkg.Tree(base)
+	if err != nil {
+		return err
+	}
+
+	type dryRun struct {
+		after []domain.Detection
+		err   error
+	}
+	runs := map[string]*dryRun{}
+	riskBefore := scoring.RiskScore(dets)
+	for i := range sugs {
+		im := &Impact{RiskBefore: riskBefore, RiskAfter: riskBefore, Resolves: []string{}, Introduces: []string{}, Effort: EffortNone}
+		sugs[i].Impact = im
+
+		d, ok := findDetection(dets, sugs[i].ID)
+		s := findStrategy(d.Kind)
+		if !ok || s == nil {
+			continue
+		}
+		trial, err := specpkg.CloneSpec(base)
+		if err != nil {
+			return err
+		}
+		if changed, _ := s.Apply(trial, g.Clone(), d); !changed {
+			continue
+		}
+		specpkg.SanitizeSpec(trial)
+		ops, err := specpkg.DiffFromTree(baseTree, trial)
+		if err != nil {
+			return err
+		}
+		im.AutoFix = true
+		sizeChange(im, ops)
+
+		key, err := json.Marshal(ops)
+		if err != nil {
+			return err
+		}
+		run, ok := runs[string(key)]
+		if !ok {
+			if len(runs) >= maxImpactDryRuns {
+				im.Error = fmt.Sprintf("not estimated: only the first %d distinct fixes are dry-run", maxImpactDryRuns)
+				continue
+			}
+			run = &dryRun{}
+			_, run.after, run.err = detect(trial)
+			runs[string(key)] = run
+		}
+		if run.err != nil {
+			im.Error = run.err.Error()
+			continue
+		}
+		im.RiskAfter = scoring.RiskScore(run.after)
+		im.RiskDelta = im.RiskAfter - riskBefore
+		im.Resolves, im.Introduces = detectionChanges(dets, run.after)
+	}
+	rankByBenefit(sugs)
+	return nil
+}
+
+// sizeChange counts the services and dependencies (or calls) ops touch and sets the effort:
+// a service added or removed weighs three dependency changes.
+func sizeChange(im *Impact, ops []specpkg.PatchOp) {
+	deps := map[string]bool{}
+	for _, op := range ops {
+		segs, err := specpkg.SplitPath(op.Path)
+		if err != nil {
+			continue
+		}
+		switch {
+		case segs[0] == "services" && len(segs) == 2 && op.Op == specpkg.OpAdd:
+			im.ServicesAdded++
+		case segs[0] == "services" && len(segs) == 2 && op.Op == specpkg.OpRemove:
+			im.ServicesRemoved++
+		case segs[0] == "dependencies" && len(segs) >= 2:
+			deps["dep:"+segs[1]] = true
+		case segs[0] == "services" && len(segs) >= 4 && segs[2] == "calls":
+			deps["call:"+segs[1]+"->"+segs[3]] = true
+		case segs[0] == "dependencies" || (segs[0] == "services" && len(segs) == 3 && segs[2] == "calls"):
+			// A whole list added or removed at once.
+			for _, v := range []any{op.Value, op.Old} {
+				if items, ok := v.([]any); ok {
+					for j := range items {
+						deps[fmt.Sprintf("%s#%d", op.Path, j)] = true
+					}
+				}
+			}
+		}
+	}
+	im.DependenciesChanged = len(deps)
+
+	points := effortPoints(im)
+	switch {
+	case len(ops) == 0:
+		im.Effort = EffortNone
+	case points <= 2:
+		im.Effort = EffortLow
+	case points <= 5:
+		im.Effort = EffortMedium
+	default:
+		im.Effort = EffortHigh
+	}
+}
+
+// detectionChanges returns the detection keys only in before (resolved) and only in after
+// (introduced), sorted.
+func detectionChanges(before, after []domain.Detection) (resolved, introduced []string) {
+	inBefore, inAfter := map[string]bool{}, map[string]bool{}
+	for _, d := range before {
+		inBefore[DetectionKey(d)] = true
+	}
+	for _, d := range after {
+		inAfter[DetectionKey(d)] = true
+	}
+	resolved, introduced = []string{}, []string{}
+	for k := range inBefore {
+		if !inAfter[k] {
+			resolved = append(resolved, k)
+		}
+	}
+	for k := range inAfter {
+		if !inBefore[k] {
+			introduced = append(introduced, k)
+		}
+	}
+	sort.Strings(resolved)
+	sort.Strings(introduced)
+	return resolved, introduced
+}
+
+func effortPoints(im *Impact) int {
+	return 3*(im.ServicesAdded+im.ServicesRemoved) + im.DependenciesChanged
+}
+
+// rankByBenefit sets Impact.Rank: auto-fixes first, by risk delta then effort; ties and
+// suggestions without an auto-fix keep their (severity) order.
+func rankByBenefit(sugs []Suggestion) {
+	idx := make([]int, len(sugs))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		ia, ib := sugs[idx[a]].Impact, sugs[idx[b]].Impact
+		fa, fb := ia.AutoFix && ia.Error == "", ib.AutoFix && ib.Error == ""
+		if fa != fb {
+			return fa
+		}
+		if ia.RiskDelta != ib.RiskDelta {
+			return ia.RiskDelta < ib.RiskDelta
+		}
+		return effortPoints(ia) < effortPoints(ib)
+	})
+	for rank, i := range idx {
+		sugs[i].Impact.Rank = rank + 1
+	}
+}
+
+// SortByBenefit reorders sugs by Impact.Rank (suggestions without an Impact last). "idx:N"
+// selections keep referring to the severity order of BuildSuggestions, so select sorted
+// suggestions by ID.
+func SortByBenefit(sugs []Suggestion) {
+	sort.SliceStable(sugs, func(a, b int) bool {
+		ia, ib := sugs[a].Impact, sugs[b].Impact
+		if ia == nil || ib == nil {
+			return ia != nil
+		}
+		return ia.Rank < ib.Rank
+	})
+}
@@ -0,0 +1,65 @@
+package suggestion
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
+)
+
+// addServiceFix adds a service named after the detection's first node, so detections on the same
+// node make the same edit.
+type addServiceFix struct{}
+
+func (addServiceFix) Kind() domain.AntiPatternKind { return "test_add_service" }
+
+func (addServiceFix) Suggest(g *domain.Graph, d domain.Detection) Suggestion {
+	return Suggestion{Kind: d.Kind, Title: d.Title}
+}
+
+func (addServiceFix) Apply(spec *parser.YSpec, g *domain.Graph, d domain.Detection) (bool, []string) {
+	spec.Services = append(spec.Services, parser.YService{Name: d.Nodes[0] + "-helper", Type: "service"})
+	return true, nil
+}
+
+func TestEstimateImpacts_SharesAndCapsDryRuns(t *testing.T) {
+	Register(addServiceFix{})
+	defer func() { strategies = strategies[:len(strategies)-1] }()
+	defer func(n int) { maxImpactDryRuns = n }(maxImpactDryRuns)
+	maxImpactDryRuns = 2
+
+	var dets []domain.Detection
+	var sugs []Suggestion
+	for _, nodes := range [][]string{{"a"}, {"a", "x"}, {"b"}, {"c"}} {
+		d := domain.Detection{Kind: "test_add_service", Severity: domain.SeverityMedium, Nodes: nodes}
+		dets = append(dets, d)
+		sugs = append(sugs, Suggestion{ID: DetectionKey(d), Kind: d.Kind})
+	}
+	runs := 0
+	detect := func(*parser.YSpec) (*domain.Graph, []domain.Detection, error) {
+		runs++
+		return domain.NewGraph(), nil, nil
+	}
+	if err := EstimateImpacts([]byte("services:\n  - name: a\n"), domain.NewGraph(), dets, sugs, detect); err != nil {
+		t.Fatal(err)
+	}
+	if runs != 2 {
+		t.Fatalf("detection ran %d times, want 2 (a and a/x make the same edit, c is over the cap)", runs)
+	}
+	for _, s := range sugs[:3] {
+		if s.Impact.Error != "" || s.Impact.RiskAfter != 0 {
+			t.Errorf("%s: %+v", s.ID, *s.Impact)
+		}
+	}
+	if last := sugs[3].Impact; !last.AutoFix || !strings.HasPrefix(last.Error, "not estimated") || last.Rank != 4 {
+		t.Errorf("over the cap: %+v", *last)
+	}
+
+	SortByBenefit(sugs)
+	for i, s := range sugs {
+		if s.Impact.Rank != i+1 {
+			t.Fatalf("sorted by benefit: %d has rank %d", i, s.Impact.Rank)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package suggestion
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/mapper"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/validator"
+	specpkg "github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/spec"
+)
+
+// Status of a PlannedFix.
+const (
+	FixPlanned = "planned"
+	FixApplied = "applied"
+	FixSkipped = "skipped"
+)
+
+// fixStage orders the kinds of fixes: first the ones that remove dependencies outright (which
+// often resolves other detections on the same services), then the ones that retune or flip a
+// single dependency, and last the ones that restructure services (new gateway, split service)
+// so they work on the cleaned-up graph.
+var fixStage = map[domain.AntiPatternKind]int{
+	domain.APCycles:               0,
+	domain.APPingPongDependency:   0,
+	domain.APEventLoop:            0,
+	domain.APSelfConsumingService: 0,
+	domain.APOrphanEventTopic:     0,
+	domain.APReverseDependency:    1,
+	domain.APTightCoupling:        1,
+	domain.APSyncCallChain:        1,
+	domain.APChattyService:        1,
+	domain.APUIOrchestrator:       2,
+	domain.APSharedDatabase:       2,
+	domain.APGodService:           2,
+	domain.APSinglePointOfFailure: 2,
+}
+
+func stageOf(k domain.AntiPatternKind) int {
+	if s, ok := fixStage[k]; ok {
+		return s
+	}
+	return 1
+}
+
+// PlannedFix is one selected suggestion in the order it is (or would be) applied.
+type PlannedFix struct {
+	ID    string                 `json:"id" yaml:"id"`
+	Kind  domain.AntiPatternKind `json:"kind" yaml:"kind"`
+	Title string                 `json:"title" yaml:"title"`
+	// Step is the 1-based position in the plan.
+	Step int `json:"step" yaml:"step"`
+	// Nodes are the graph nodes the fix touches (the detection's nodes and edge endpoints).
+	Nodes []string `json:"nodes" yaml:"nodes"`
+	// ConflictsWith are the other selected fixes touching any of Nodes; DependsOn are those of
+	// them planned earlier, after which this fix is checked again against a fresh detection.
+	ConflictsWith []string `json:"conflicts_with,omitempty" yaml:"conflicts_with,omitempty"`
+	DependsOn     []string `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
+	Status        string   `json:"status" yaml:"status"`
+	// Reason says why a fix was skipped.
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// DetectFunc runs detection on a spec being fixed; ApplyFixPlan calls it between fixes so each
+// fix works on the current graph. It must not modify spec.
+type DetectFunc func(spec *parser.YSpec) (*domain.Graph, []domain.Detection, error)
+
+// PlanFixes orders the selected detections (all when selectedIDs is nil) for fixing: by fix
+// stage (see fixStage), then severity, then id. Fixes sharing nodes are linked through
+// ConflictsWith/DependsOn; detections without a strategy are skipped. Nothing is applied.
+func PlanFixes(g *domain.Graph, dets []domain.Detection, selectedIDs map[string]bool) []PlannedFix {
+	var picked []domain.Detection
+	seen := map[string]bool{}
+	for _, d := range dets {
+		key := DetectionKey(d)
+		if seen[key] || (selectedIDs != nil && !selectedIDs[key]) {
+			continue
+		}
+		seen[key] = true
+		picked = append(picked, d)
+	}
+	sort.SliceStable(picked, func(i, j int) bool {
+		si, sj := stageOf(picked[i].Kind), stageOf(picked[j].Kind)
+		if si != sj {
+			return si < sj
+		}
+		wi, wj := severityWeight(picked[i].Severity), severityWeight(picked[j].Severity)
+		if wi != wj {
+			return wi > wj
+		}
+		return DetectionKey(picked[i]) < DetectionKey(picked[j])
+	})
+
+	plan := make([]PlannedFix, len(picked))
+	touched := make([]map[string]bool, len(picked))
+	for i, d := range picked {
+		touched[i] = fixFootprint(g, d)
+		plan[i] = PlannedFix{
+			ID:     DetectionKey(d),
+			Kind:   d.Kind,
+			Title:  d.Title,
+			Step:   i + 1,
+			Nodes:  parser.SortedSet(touched[i]),
+			Status: FixPlanned,
+		}
+		if findStrategy(d.Kind) == nil {
+			plan[i].Status = FixSkipped
+			plan[i].Reason = fmt.Sprintf("no auto-fix for %s", d.Kind)
+		}
+	}
+	for i := range plan {
+		for j := range plan {
+			if i == j || plan[j].Status == FixSkipped || !overlaps(touched[i], touched[j]) {
+				continue
+			}
+			plan[i].ConflictsWith = append(plan[i].ConflictsWith, plan[j].ID)
+			if j < i {
+				plan[i].DependsOn = append(plan[i].DependsOn, plan[j].ID)
+			}
+		}
+	}
+	return plan
+}
+
+// ApplyFixPlan applies the selected fixes (all when selectedIDs is nil) in PlanFixes order.
+// Before a fix runs after an earlier one changed the spec, detection is re-run with detect and
+// the fix uses the fresh graph and detection; a fix whose detection is gone, or whose strategy
+// changes nothing, is skipped with a reason, as is every fix after earlier ones left a spec that
+// detect rejects as invalid (a validator.Issues error). With a nil detect every fix uses g and
+// dets as given. The plan's Nodes, ConflictsWith and DependsOn stay those PlanFixes computed on g.
+// The returned plan carries the outcome of every fix; the YAML is patched as in
+// ApplyFixesYAMLBytesFiltered.
+func ApplyFixPlan(yamlBytes []byte, g *domain.Graph, dets []domain.Detection, selectedIDs map[string]bool, detect DetectFunc) ([]byte, []Suggestion, []PlannedFix, error) {
+	origSpec, err := parser.ParseYAMLBytes(yamlBytes)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	mapper.NormalizeYAMLSpecInPlace(origSpec)
+
+	specStruct, err := parser.ParseYAMLBytes(yamlBytes)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	mapper.NormalizeYAMLSpecInPlace(specStruct)
+	// Fixes edit (and their ops are recorded on) the sanitized spec, the form ApplyOpsYAMLBytes
+	// replays ops on.
+	specpkg.SanitizeSpec(specStruct)
+
+	plan := PlanFixes(g, dets, selectedIDs)
+	applied := []Suggestion{}
+	appliedIDs := map[string]bool{}
+	curG, curDets := g, dets
+	stale := false
+	for i := range plan {
+		p := &plan[i]
+		if p.Status == FixSkipped {
+			continue
+		}
+		if stale && detect != nil {
+			fresh, freshDets, err := detect(specStruct)
+			var issues validator.Issues
+			if errors.As(err, &issues) {
+				p.Status = FixSkipped
+				p.Reason = "cannot re-detect after the earlier fixes: " + issues.Error()
+				continue
+			}
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("detection before %s: %w", p.ID, err)
+			}
+			curG, curDets, stale = fresh, freshDets, false
+		}
+		d, ok := findDetection(curDets, p.ID)
+		if !ok {
+			p.Status = FixSkipped
+			p.Reason = "no longer detected"
+			var by []string
+			for _, dep := range p.DependsOn {
+				if appliedIDs[dep] {
+					by = append(by, dep)
+				}
+			}
+			if len(by) > 0 {
+				p.Reason = "no longer detected after " + strings.Join(by, ", ")
+			}
+			continue
+		}
+
+		s := findStrategy(d.Kind)
+		before, err := specpkg.CloneSpec(specStruct)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		changed, notes := s.Apply(specStruct, curG, d)
+		if !changed {
+			p.Status = FixSkipped
+			p.Reason = "the auto-fix found nothing to change"
+			continue
+		}
+		specpkg.SanitizeSpec(specStruct)
+		ops, err := specpkg.DiffSpecs(before, specStruct)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		ss := s.Suggest(curG, d)
+		ss.ID = p.ID
+		withGuidance(&ss, d)
+		ss.AutoFixApplied = true
+		ss.AutoFixNotes = notes
+		ss.AutoFixPatch = ops
+		applied = append(applied, ss)
+		appliedIDs[p.ID] = true
+		p.Status = FixApplied
+		stale = true
+	}
+
+	// Sanitize both sides alike so only the fixes show up as edits.
+	specpkg.SanitizeSpec(origSpec)
+	specpkg.SanitizeSpec(specStruct)
+	out, err := specpkg.PatchYAML(yamlBytes, origSpec, specStruct)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return out, applied, plan, nil
+}
+
+func findDetection(dets []domain.Detection, key string) (domain.Detection, bool) {
+	for _, d := range dets {
+		if DetectionKey(d) == key {
+			return d, true
+		}
+	}
+	return domain.Detection{}, false
+}
+
+// fixFootprint is the set of nodes a fix for d may touch: its nodes and the endpoints of its edges.
+// It is computed once on the graph the plan starts from, not again after each fix.
+func fixFootprint(g *domain.Graph, d domain.Detection) map[string]bool {
+	out := map[string]bool{}
+	for _, n := range d.Nodes {
+		out[n] = true
+	}
+	if g == nil {
+		return out
+	}
+	for _, i := range d.Edges {
+		if i >= 0 && i < len(g.Edges) {
+			out[g.Edges[i].From] = true
+			out[g.Edges[i].To] = true
+		}
+	}
+	return out
+}
+
+func overlaps(a, b map[string]bool) bool {
+	for n := range a {
+		if b[n] {
+			return true
+		}
+	}
+	return false
+}
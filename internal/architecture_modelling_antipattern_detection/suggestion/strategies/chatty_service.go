@@ -0,0 +1,66 @@
+package strategies
+
+import (
+	"fmt"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/suggestion"
+)
+
+type chattyService struct{}
+
+func (chattyService) Kind() domain.AntiPatternKind { return domain.APChattyService }
+
+func (chattyService) Suggest(g *domain.Graph, det domain.Detection) suggestion.Suggestion {
+	caller := "service"
+	if len(det.Nodes) > 0 {
+		caller = cleanRef(det.Nodes[0])
+	}
+	title := fmt.Sprintf("Reduce chatty calls from %s", caller)
+	return suggestion.Suggestion{Kind: det.Kind, Title: title}
+}
+
+func (chattyService) Apply(spec *parser.YSpec, g *domain.Graph, det domain.Detection) (bool, []string) {
+	if spec == nil || len(det.Nodes) < 2 {
+		return false, nil
+	}
+	from := det.Nodes[0]
+	changed := false
+	var notes []string
+
+	for _, to := range det.Nodes[1:] {
+		if ok, note := batchCall(spec, from, to); ok {
+			changed = true
+			notes = append(notes, note)
+			continue
+		}
+		if ok, note := setDependencySync(spec, from, to, false); ok {
+			changed = true
+			notes = append(notes, note)
+		}
+	}
+	return changed, notes
+}
+
+// batchCall clears per_item on from→to (new-style dependency or legacy services[].calls).
+func batchCall(spec *parser.YSpec, from, to string) (bool, string) {
+	f := cleanRef(from)
+	t := cleanRef(to)
+	if i := findDepIndex(spec, f, t); i >= 0 && spec.Dependencies[i].PerItem {
+		spec.Dependencies[i].PerItem = false
+		return true, fmt.Sprintf("Batched per-item calls: %s → %s", f, t)
+	}
+	if si := findServiceIndexByRef(spec, f); si >= 0 {
+		svc := &spec.Services[si]
+		for i := range svc.Calls {
+			if eqRef(svc.Calls[i].To, t) && svc.Calls[i].PerItem {
+				svc.Calls[i].PerItem = false
+				return true, fmt.Sprintf("Batched per-item calls: %s → %s", f, t)
+			}
+		}
+	}
+	return false, ""
+}
+
+func init() { suggestion.Register(chattyService{}) }
@@ -0,0 +1,174 @@
+package strategies
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/detection/rules"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/suggestion"
+)
+
+const topicIDPrefix = string(domain.NodeEventTopic) + ":"
+
+func isTopicRef(id string) bool {
+	return strings.HasPrefix(strings.ToUpper(strings.TrimSpace(id)), topicIDPrefix)
+}
+
+// removeTopic drops a topic declaration (topics[] or a services[] entry typed event_topic).
+func removeTopic(spec *parser.YSpec, name string) bool {
+	if spec == nil {
+		return false
+	}
+	n := cleanRef(name)
+	for i := range spec.Topics {
+		if eqRef(spec.Topics[i].Name, n) {
+			spec.Topics = append(spec.Topics[:i], spec.Topics[i+1:]...)
+			return true
+		}
+	}
+	return removeService(spec, n)
+}
+
+// removeConsumeLeg removes the edge delivering topic events to svc: topic → svc,
+// or svc → topic with a subscribe/consume dependency kind.
+func removeConsumeLeg(spec *parser.YSpec, svc, topic string) (bool, string) {
+	if ok, note := removeDependencyOrLegacyCall(spec, topic, svc); ok {
+		return true, note
+	}
+	s := cleanRef(svc)
+	t := cleanRef(topic)
+	for i := range spec.Dependencies {
+		d := spec.Dependencies[i]
+		if eqRef(d.From, s) && eqRef(d.To, t) && rules.IsSubscribeDepKind(d.Kind) {
+			spec.Dependencies = append(spec.Dependencies[:i], spec.Dependencies[i+1:]...)
+			return true, fmt.Sprintf("Removed subscription: %s ← %s", s, t)
+		}
+	}
+	return false, ""
+}
+
+// removeAllTopicEdges removes every dependency / legacy call touching topic.
+func removeAllTopicEdges(spec *parser.YSpec, topic string) []string {
+	t := cleanRef(topic)
+	var notes []string
+	kept := spec.Dependencies[:0]
+	for _, d := range spec.Dependencies {
+		if eqRef(d.From, t) || eqRef(d.To, t) {
+			notes = append(notes, fmt.Sprintf("Removed dependency: %s → %s", cleanRef(d.From), cleanRef(d.To)))
+			continue
+		}
+		kept = append(kept, d)
+	}
+	spec.Dependencies = kept
+	for i := range spec.Services {
+		svc := &spec.Services[i]
+		calls := svc.Calls[:0]
+		for _, c := range svc.Calls {
+			if eqRef(c.To, t) {
+				notes = append(notes, fmt.Sprintf("Removed call %s → %s", cleanRef(svc.Name), t))
+				continue
+			}
+			calls = append(calls, c)
+		}
+		svc.Calls = calls
+	}
+	return notes
+}
+
+type orphanEventTopic struct{}
+
+func (orphanEventTopic) Kind() domain.AntiPatternKind { return domain.APOrphanEventTopic }
+
+func (orphanEventTopic) Suggest(g *domain.Graph, det domain.Detection) suggestion.Suggestion {
+	topic := ""
+	if len(det.Nodes) > 0 {
+		topic = cleanRef(det.Nodes[0])
+	}
+	title := "Fix orphan event topic"
+	if topic != "" {
+		title = fmt.Sprintf("Fix orphan event topic (%s)", topic)
+	}
+	return suggestion.Suggestion{Kind: det.Kind, Title: title}
+}
+
+func (orphanEventTopic) Apply(spec *parser.YSpec, g *domain.Graph, det domain.Detection) (bool, []string) {
+	if spec == nil || len(det.Nodes) < 1 {
+		return false, nil
+	}
+	topic := det.Nodes[0]
+	notes := removeAllTopicEdges(spec, topic)
+	if removeTopic(spec, topic) {
+		notes = append(notes, fmt.Sprintf("Removed topic %s", cleanRef(topic)))
+	}
+	return len(notes) > 0, notes
+}
+
+type selfConsumingService struct{}
+
+func (selfConsumingService) Kind() domain.AntiPatternKind { return domain.APSelfConsumingService }
+
+func (selfConsumingService) Suggest(g *domain.Graph, det domain.Detection) suggestion.Suggestion {
+	title := "Stop consuming own events"
+	if len(det.Nodes) >= 2 {
+		title = fmt.Sprintf("Stop %s consuming its own events on %s", cleanRef(det.Nodes[0]), cleanRef(det.Nodes[1]))
+	}
+	return suggestion.Suggestion{Kind: det.Kind, Title: title}
+}
+
+func (selfConsumingService) Apply(spec *parser.YSpec, g *domain.Graph, det domain.Detection) (bool, []string) {
+	if spec == nil || len(det.Nodes) < 2 {
+		return false, nil
+	}
+	if ok, note := removeConsumeLeg(spec, det.Nodes[0], det.Nodes[1]); ok {
+		return true, []string{note}
+	}
+	return false, nil
+}
+
+type eventLoop struct{}
+
+func (eventLoop) Kind() domain.AntiPatternKind { return domain.APEventLoop }
+
+func (eventLoop) Suggest(g *domain.Graph, det domain.Detection) suggestion.Suggestion {
+	var svcs, topics []string
+	for _, n := range det.Nodes {
+		if isTopicRef(n) {
+			topics = append(topics, cleanRef(n))
+		} else {
+			svcs = append(svcs, cleanRef(n))
+		}
+	}
+	title := "Break event loop"
+	if len(svcs) > 0 {
+		title = fmt.Sprintf("Break event loop: %s (via %s)", joinNice(svcs), joinNice(topics))
+	}
+	return suggestion.Suggestion{Kind: det.Kind, Title: title}
+}
+
+func (eventLoop) Apply(spec *parser.YSpec, g *domain.Graph, det domain.Detection) (bool, []string) {
+	if spec == nil || len(det.Nodes) < 3 {
+		return false, nil
+	}
+	for _, t := range det.Nodes {
+		if !isTopicRef(t) {
+			continue
+		}
+		for _, s := range det.Nodes {
+			if isTopicRef(s) {
+				continue
+			}
+			if ok, note := removeConsumeLeg(spec, s, t); ok {
+				return true, []string{note, fmt.Sprintf("Broke event loop at %s → %s", cleanRef(t), cleanRef(s))}
+			}
+		}
+	}
+	return false, nil
+}
+
+func init() {
+	suggestion.Register(orphanEventTopic{})
+	suggestion.Register(selfConsumingService{})
+	suggestion.Register(eventLoop{})
+}
@@ -0,0 +1,52 @@
+package strategies
+
+import (
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
+)
+
+func TestEventLoopApply_RemovesSubscriptionLeg(t *testing.T) {
+	y := `
+services:
+  - name: orders
+  - name: billing
+topics:
+  - name: order-created
+  - name: invoice-paid
+dependencies:
+  - from: orders
+    to: order-created
+    kind: event
+  - from: order-created
+    to: billing
+    kind: event
+  - from: billing
+    to: invoice-paid
+    kind: event
+  - from: orders
+    to: invoice-paid
+    kind: subscribe
+`
+	spec, err := parser.ParseYAMLString(y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	det := domain.Detection{
+		Kind:  domain.APEventLoop,
+		Nodes: []string{"SERVICE:billing", "SERVICE:orders", "EVENT_TOPIC:invoice-paid", "EVENT_TOPIC:order-created"},
+	}
+	changed, notes := eventLoop{}.Apply(spec, nil, det)
+	if !changed {
+		t.Fatal("expected Apply to remove one subscription")
+	}
+	if len(spec.Dependencies) != 3 {
+		t.Fatalf("expected 3 dependencies left, got %+v (notes %v)", spec.Dependencies, notes)
+	}
+	for _, d := range spec.Dependencies {
+		if d.From == "orders" && d.To == "invoice-paid" {
+			t.Fatalf("subscription orders ← invoice-paid should be removed, notes %v", notes)
+		}
+	}
+}
@@ -24,13 +24,7 @@ func (godService) Suggest(g *domain.Graph, det domain.Detection) suggestion.Sugg
 		title = fmt.Sprintf("Split god service (%s)", main)
 	}
 
-	bullets := []string{
-		"One service has too many dependencies (high centrality).",
-		"Fix: split responsibilities into smaller services.",
-		"Auto-fix: moves some outgoing dependencies onto a new split service and adds a main → split delegate dependency (if missing).",
-	}
-
-	return suggestion.Suggestion{Kind: det.Kind, Title: title, Bullets: bullets}
+	return suggestion.Suggestion{Kind: det.Kind, Title: title}
 }
 
 func (godService) Apply(spec *parser.YSpec, g *domain.Graph, det domain.Detection) (bool, []string) {
@@ -14,11 +14,6 @@ func (pingPongDependency) Suggest(g *domain.Graph, det domain.Detection) suggest
 	sug := suggestion.Suggestion{
 		Kind:  det.Kind,
 		Title: "Reduce ping-pong dependency",
-		Bullets: []string{
-			"Two services call each other (back-and-forth).",
-			"Fix: remove one direction so they no longer mutually depend, or replace with events.",
-			"Auto-fix: removes one call/dependency — prefers dropping backend → UI if detected, else the second → first leg, else the other direction.",
-		},
 	}
 	if len(det.Nodes) < 2 {
 		return sug
@@ -20,11 +20,6 @@ func (reverseDependency) Suggest(g *domain.Graph, det domain.Detection) suggesti
 		Title:       "Fix reverse dependency",
 		PreviewFrom: from,
 		PreviewTo:   to,
-		Bullets: []string{
-			"Backend depends on UI/frontend (wrong direction).",
-			"Fix: dependency should go UI → backend (presentation calls APIs), not backend → UI.",
-			"Auto-fix: flips the dependency — removes backend → UI and adds UI → backend (works for top-level dependencies and legacy services[].calls).",
-		},
 	}
 }
 
@@ -15,12 +15,7 @@ func (sharedDatabase) Kind() domain.AntiPatternKind { return domain.APSharedData
 
 func (sharedDatabase) Suggest(g *domain.Graph, det domain.Detection) suggestion.Suggestion {
 	title := "Reduce shared database"
-	bullets := []string{
-		"Multiple services depend on the same database component.",
-		"Fix: split DB per bounded context or access via one owning service.",
-		"Auto-fix: creates per-service DB nodes, retargets dependencies, and removes the old shared DB entry from the spec when it becomes unused.",
-	}
-	return suggestion.Suggestion{Kind: det.Kind, Title: title, Bullets: bullets}
+	return suggestion.Suggestion{Kind: det.Kind, Title: title}
 }
 
 func (sharedDatabase) Apply(spec *parser.YSpec, g *domain.Graph, det domain.Detection) (bool, []string) {
@@ -0,0 +1,27 @@
+package strategies
+
+import (
+	"fmt"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/suggestion"
+)
+
+type singlePointOfFailure struct{}
+
+func (singlePointOfFailure) Kind() domain.AntiPatternKind { return domain.APSinglePointOfFailure }
+
+func (singlePointOfFailure) Suggest(g *domain.Graph, det domain.Detection) suggestion.Suggestion {
+	title := "Remove single point of failure"
+	if len(det.Nodes) > 0 {
+		title = fmt.Sprintf("Remove single point of failure (%s)", cleanRef(det.Nodes[0]))
+	}
+	return suggestion.Suggestion{Kind: det.Kind, Title: title}
+}
+
+func (singlePointOfFailure) Apply(spec *parser.YSpec, g *domain.Graph, det domain.Detection) (bool, []string) {
+	return false, nil
+}
+
+func init() { suggestion.Register(singlePointOfFailure{}) }
@@ -14,12 +14,7 @@ func (syncCallChain) Kind() domain.AntiPatternKind { return domain.APSyncCallCha
 
 func (syncCallChain) Suggest(g *domain.Graph, det domain.Detection) suggestion.Suggestion {
 	title := "Shorten sync call chain"
-	bullets := []string{
-		"Long synchronous chains increase latency and failure blast radius.",
-		"Fix: make one hop async (sync=false) or add a BFF/cache.",
-		"Auto-fix: sets sync=false on a middle hop of the longest detected sync chain (does not remove or redirect edges).",
-	}
-	return suggestion.Suggestion{Kind: det.Kind, Title: title, Bullets: bullets}
+	return suggestion.Suggestion{Kind: det.Kind, Title: title}
 }
 
 func (syncCallChain) Apply(spec *parser.YSpec, g *domain.Graph, det domain.Detection) (bool, []string) {
@@ -24,13 +24,8 @@ func (tightCoupling) Suggest(g *domain.Graph, det domain.Detection) suggestion.S
 	}
 
 	sug := suggestion.Suggestion{
-		Kind:    det.Kind,
-		Title:   title,
-		Bullets: []string{
-			"Two services heavily depend on each other (often sync both ways).",
-			"Fix: make one direction async or remove one direction.",
-			"Auto-fix: tries to set sync=false on the second service → first service edge first, then the other direction if needed.",
-		},
+		Kind:  det.Kind,
+		Title: title,
 	}
 	if a != "" && b != "" {
 		sug.PreviewFrom, sug.PreviewTo = a, b
@@ -14,11 +14,6 @@ func (uiOrchestratorStrategy) Suggest(g *domain.Graph, det domain.Detection) sug
 	return suggestion.Suggestion{
 		Kind:  det.Kind,
 		Title: "Introduce BFF for UI",
-		Bullets: []string{
-			"UI calls multiple backend services directly.",
-			"Fix: add a BFF (backend-for-frontend) or gateway so UI calls one endpoint.",
-			"Auto-fix: inserts a BFF node, adds UI → BFF, removes direct UI → backend edges, and adds BFF → each former target.",
-		},
 	}
 }
 
@@ -10,6 +10,15 @@ func WriteFile(path, data string) error {
 	return os.WriteFile(path, []byte(data), 0644)
 }
 
+// HasDot reports whether the Graphviz dot binary (default "dot") can be found.
+func HasDot(dotBin string) bool {
+	if dotBin == "" {
+		dotBin = "dot"
+	}
+	_, err := exec.LookPath(dotBin)
+	return err == nil
+}
+
 func DotTo(pathDOT, outPath, format, dotBin string) error {
 	if format == "" {
 		format = "svg"
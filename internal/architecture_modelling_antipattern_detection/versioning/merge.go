@@ -0,0 +1,235 @@
+package versioning
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/spec"
+)
+
+type ConflictKind string
+
+const (
+	// ConflictBothModified: both sides changed the same value differently.
+	ConflictBothModified ConflictKind = "both_modified"
+	// ConflictModifyDelete: one side changed a value the other side removed.
+	ConflictModifyDelete ConflictKind = "modify_delete"
+	// ConflictBothAdded: both sides added the same value (e.g. a service of the same name) differently.
+	ConflictBothAdded ConflictKind = "both_added"
+)
+
+// Resolution picks the side a conflict is resolved to.
+type Resolution string
+
+const (
+	ResolveOurs   Resolution = "ours"
+	ResolveTheirs Resolution = "theirs"
+	ResolveBase   Resolution = "base"
+)
+
+// MergeConflict is a value both sides changed. Path is a YAML path with list items named by
+// their identity, e.g. "services[orders].calls[billing].endpoints" or
+// "dependencies[orders->billing].sync"; Base, Ours and Theirs are nil where the value is absent.
+type MergeConflict struct {
+	Path   string       `json:"path" yaml:"path"`
+	Kind   ConflictKind `json:"kind" yaml:"kind"`
+	Base   any          `json:"base" yaml:"base"`
+	Ours   any          `json:"ours" yaml:"ours"`
+	Theirs any          `json:"theirs" yaml:"theirs"`
+	// Resolution is set when the conflict was resolved by the caller.
+	Resolution Resolution `json:"resolution,omitempty" yaml:"resolution,omitempty"`
+}
+
+type MergeResult struct {
+	// Spec is the merged spec; unresolved conflicts keep the ours value.
+	Spec *parser.YSpec `json:"-" yaml:"-"`
+	// Conflicts are the unresolved conflicts; the merge is clean when empty.
+	Conflicts []MergeConflict `json:"conflicts" yaml:"conflicts"`
+	// Resolved are the conflicts settled by the given resolutions.
+	Resolved []MergeConflict `json:"resolved" yaml:"resolved"`
+}
+
+// MergeSpecs merges the changes ours and theirs made to base. Services, datastores, topics and
+// APIs are matched by name, dependencies by from->to and calls by target, so edits to different
+// items (or different fields of one item) merge cleanly; string lists such as endpoints merge as
+// sets. resolutions (keyed by MergeConflict.Path, may be nil) settle conflicts reported by an
+// earlier merge. A nil spec is treated as empty.
+func MergeSpecs(base, ours, theirs *parser.YSpec, resolutions map[string]Resolution) (*MergeResult, error) {
+	for path, r := range resolutions {
+		switch r {
+		case ResolveOurs, ResolveTheirs, ResolveBase:
+		default:
+			return nil, fmt.Errorf("resolution for %q must be ours, theirs or base, got %q", path, r)
+		}
+	}
+	var trees [3]any
+	for i, s := range []*parser.YSpec{base, ours, theirs} {
+		t, err := spec.Tree(s)
+		if err != nil {
+			return nil, err
+		}
+		trees[i] = t
+	}
+	m := &merger{resolutions: resolutions}
+	merged := m.merge("", trees[0], trees[1], trees[2])
+
+	out, err := spec.FromTree(merged)
+	if err != nil {
+		return nil, fmt.Errorf("merged spec: %w", err)
+	}
+	return &MergeResult{Spec: out, Conflicts: m.conflicts, Resolved: m.resolved}, nil
+}
+
+type merger struct {
+	resolutions map[string]Resolution
+	conflicts   []MergeConflict
+	resolved    []MergeConflict
+}
+
+// merge returns the merged value at path; nil means absent.
+func (m *merger) merge(path string, b, o, t any) any {
+	switch {
+	case spec.SameValue(o, t):
+		return o
+	case spec.SameValue(b, o):
+		return t
+	case spec.SameValue(b, t):
+		return o
+	}
+
+	om, oIsMap := o.(map[string]any)
+	tm, tIsMap := t.(map[string]any)
+	bm, bIsMap := b.(map[string]any)
+	if oIsMap && tIsMap && (bIsMap || b == nil) {
+		return m.mergeMaps(path, bm, om, tm)
+	}
+
+	ol, oIsList := o.([]any)
+	tl, tIsList := t.([]any)
+	bl, bIsList := b.([]any)
+	if oIsList && tIsList && (bIsList || b == nil) {
+		if merged, ok := m.mergeKeyed(path, bl, ol, tl); ok {
+			return merged
+		}
+		if merged, ok := mergeSet(bl, ol, tl); ok {
+			return merged
+		}
+	}
+	return m.conflict(path, b, o, t)
+}
+
+func (m *merger) conflict(path string, b, o, t any) any {
+	c := MergeConflict{Path: path, Kind: ConflictBothModified, Base: b, Ours: o, Theirs: t}
+	switch {
+	case spec.IsEmptyValue(b):
+		c.Kind = ConflictBothAdded
+	case spec.IsEmptyValue(o) || spec.IsEmptyValue(t):
+		c.Kind = ConflictModifyDelete
+	}
+	r, ok := m.resolutions[path]
+	if !ok {
+		m.conflicts = append(m.conflicts, c)
+		return o
+	}
+	c.Resolution = r
+	m.resolved = append(m.resolved, c)
+	switch r {
+	case ResolveTheirs:
+		return t
+	case ResolveBase:
+		return b
+	}
+	return o
+}
+
+func (m *merger) mergeMaps(path string, b, o, t map[string]any) any {
+	keys := map[string]bool{}
+	for _, x := range []map[string]any{b, o, t} {
+		for k := range x {
+			keys[k] = true
+		}
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	out := map[string]any{}
+	for _, k := range sorted {
+		p := k
+		if path != "" {
+			p = path + "." + k
+		}
+		if v := m.merge(p, b[k], o[k], t[k]); !spec.IsEmptyValue(v) {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// mergeKeyed merges lists of mappings that have an identity (see spec.ItemKey). Merged items keep
+// ours order, followed by the items only theirs added. ok is false when the lists are not keyed.
+func (m *merger) mergeKeyed(path string, b, o, t []any) ([]any, bool) {
+	bk, _, ok1 := spec.KeyedItems(b)
+	ours, oOrder, ok2 := spec.KeyedItems(o)
+	tk, tOrder, ok3 := spec.KeyedItems(t)
+	if !ok1 || !ok2 || !ok3 {
+		return nil, false
+	}
+	order := oOrder
+	for _, k := range tOrder {
+		if _, inOurs := ours[k]; !inOurs {
+			order = append(order, k)
+		}
+	}
+	merged := []any{}
+	for _, k := range order {
+		if v := m.merge(fmt.Sprintf("%s[%s]", path, k), bk[k], ours[k], tk[k]); !spec.IsEmptyValue(v) {
+			merged = append(merged, v)
+		}
+	}
+	return merged, true
+}
+
+// mergeSet merges lists of scalars as sets: an element is kept unless one side removed it.
+// ok is false when a list holds non-scalar elements.
+func mergeSet(b, o, t []any) ([]any, bool) {
+	index := func(items []any) (map[string]bool, bool) {
+		set := map[string]bool{}
+		for _, it := range items {
+			switch it.(type) {
+			case map[string]any, []any:
+				return nil, false
+			}
+			set[scalarKey(it)] = true
+		}
+		return set, true
+	}
+	inB, ok1 := index(b)
+	inO, ok2 := index(o)
+	inT, ok3 := index(t)
+	if !ok1 || !ok2 || !ok3 {
+		return nil, false
+	}
+	out := []any{}
+	seen := map[string]bool{}
+	for _, items := range [][]any{o, t} {
+		for _, it := range items {
+			k := scalarKey(it)
+			if seen[k] {
+				continue
+			}
+			if (inO[k] && inT[k]) || !inB[k] {
+				out = append(out, it)
+				seen[k] = true
+			}
+		}
+	}
+	return out, true
+}
+
+func scalarKey(v any) string {
+	return fmt.Sprintf("%T:%v", v, v)
+}
@@ -0,0 +1,272 @@
+package versioning
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/ingest/parser"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/spec"
+)
+
+func mustSpec(t *testing.T, y string) *parser.YSpec {
+	t.Helper()
+	s, err := parser.ParseYAMLString(y)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	return s
+}
+
+const mergeBase = `
+services:
+  - name: orders
+    type: service
+    calls:
+      - to: billing
+        endpoints: ["POST /charge"]
+  - name: billing
+    type: service
+  - name: legacy
+    type: service
+dependencies:
+  - from: orders
+    to: billing
+    kind: rest
+    sync: true
+`
+
+func TestMergeSpecsNonOverlapping(t *testing.T) {
+	base := mustSpec(t, mergeBase)
+	// ours: add an endpoint, remove legacy, make the dependency async
+	ours := mustSpec(t, `
+services:
+  - name: orders
+    type: service
+    calls:
+      - to: billing
+        endpoints: ["POST /charge", "POST /refund"]
+  - name: billing
+    type: service
+dependencies:
+  - from: orders
+    to: billing
+    kind: rest
+    sync: false
+`)
+	// theirs: retype billing, add an endpoint, add a service and a dependency
+	theirs := mustSpec(t, `
+services:
+  - name: orders
+    type: service
+    calls:
+      - to: billing
+        endpoints: ["POST /charge", "GET /invoice"]
+  - name: billing
+    type: gateway
+  - name: legacy
+    type: service
+  - name: audit
+    type: service
+dependencies:
+  - from: orders
+    to: billing
+    kind: rest
+    sync: true
+  - from: billing
+    to: audit
+    kind: event
+`)
+	res, err := MergeSpecs(base, ours, theirs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Conflicts) != 0 {
+		t.Fatalf("unexpected conflicts: %+v", res.Conflicts)
+	}
+	var names []string
+	types := map[string]string{}
+	for _, s := range res.Spec.Services {
+		names = append(names, s.Name)
+		types[s.Name] = s.Type
+	}
+	if len(names) != 3 || names[0] != "orders" || names[1] != "billing" || names[2] != "audit" {
+		t.Fatalf("services = %v", names)
+	}
+	if types["billing"] != "gateway" {
+		t.Errorf("billing type = %q", types["billing"])
+	}
+	eps := res.Spec.Services[0].Calls[0].Endpoints
+	if len(eps) != 3 || eps[0] != "POST /charge" || eps[1] != "POST /refund" || eps[2] != "GET /invoice" {
+		t.Errorf("endpoints = %v", eps)
+	}
+	if len(res.Spec.Dependencies) != 2 {
+		t.Fatalf("dependencies = %+v", res.Spec.Dependencies)
+	}
+	if d := res.Spec.Dependencies[0]; d.Sync {
+		t.Error("orders->billing should be async")
+	}
+}
+
+func TestMergeSpecsConflicts(t *testing.T) {
+	base := mustSpec(t, mergeBase)
+	ours := mustSpec(t, `
+services:
+  - name: orders
+    type: service
+    calls:
+      - to: billing
+        endpoints: ["POST /charge"]
+  - name: billing
+    type: gateway
+  - name: legacy
+    type: service
+    replicas: 3
+dependencies:
+  - from: orders
+    to: billing
+    kind: rest
+    sync: true
+`)
+	theirs := mustSpec(t, `
+services:
+  - name: orders
+    type: service
+    calls:
+      - to: billing
+        endpoints: ["POST /charge"]
+  - name: billing
+    type: database
+dependencies:
+  - from: orders
+    to: billing
+    kind: rest
+    sync: true
+`)
+	res, err := MergeSpecs(base, ours, theirs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]ConflictKind{}
+	for _, c := range res.Conflicts {
+		got[c.Path] = c.Kind
+	}
+	if len(got) != 2 || got["services[billing].type"] != ConflictBothModified || got["services[legacy]"] != ConflictModifyDelete {
+		t.Fatalf("conflicts = %+v", res.Conflicts)
+	}
+	// Unresolved conflicts keep ours.
+	if len(res.Spec.Services) != 3 || res.Spec.Services[1].Type != "gateway" {
+		t.Errorf("services = %+v", res.Spec.Services)
+	}
+
+	res, err = MergeSpecs(base, ours, theirs, map[string]Resolution{
+		"services[billing].type": ResolveTheirs,
+		"services[legacy]":       ResolveTheirs,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Conflicts) != 0 || len(res.Resolved) != 2 {
+		t.Fatalf("conflicts = %+v, resolved = %+v", res.Conflicts, res.Resolved)
+	}
+	if len(res.Spec.Services) != 2 || res.Spec.Services[1].Type != "database" {
+		t.Errorf("services = %+v", res.Spec.Services)
+	}
+
+	if _, err := MergeSpecs(base, ours, theirs, map[string]Resolution{"x": "mine"}); err == nil {
+		t.Error("expected an error for an unknown resolution")
+	}
+}
+
+// Items are matched like spec.PatchYAML and the patch ops match them: "SERVICE:orders" is orders.
+func TestMergeSpecsMatchesPrefixedReferences(t *testing.T) {
+	base := mustSpec(t, mergeBase)
+	ours := mustSpec(t, `
+services:
+  - name: orders
+    type: service
+    calls:
+      - to: SERVICE:billing
+        endpoints: ["POST /charge"]
+  - name: billing
+    type: service
+  - name: legacy
+    type: service
+dependencies:
+  - from: SERVICE:orders
+    to: SERVICE:billing
+    kind: rest
+    sync: false
+`)
+	theirs := mustSpec(t, `
+services:
+  - name: orders
+    type: service
+    calls:
+      - to: billing
+        endpoints: ["POST /charge"]
+  - name: billing
+    type: service
+  - name: legacy
+    type: service
+dependencies:
+  - from: orders
+    to: billing
+    kind: grpc
+    sync: true
+`)
+	res, err := MergeSpecs(base, ours, theirs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Conflicts) != 0 {
+		t.Fatalf("expected a clean merge, got %+v", res.Conflicts)
+	}
+	if len(res.Spec.Dependencies) != 1 || res.Spec.Dependencies[0].Kind != "grpc" || res.Spec.Dependencies[0].Sync {
+		t.Fatalf("dependencies = %+v", res.Spec.Dependencies)
+	}
+}
+
+func TestMergeSpecsPatchedIntoOursKeepsItsFormatting(t *testing.T) {
+	base := mustSpec(t, mergeBase)
+	oursYAML := `
+# orders platform
+services:
+  - name: orders
+    type: service
+    owner: team-a # not part of YSpec
+    calls:
+      - to: billing
+        endpoints: ["POST /charge"]
+  - name: billing
+    type: service
+  - name: legacy
+    type: service
+dependencies:
+  - from: orders
+    to: billing
+    kind: rest
+    sync: true
+`
+	ours := mustSpec(t, oursYAML)
+	theirs := mustSpec(t, mergeBase+`  - from: billing
+    to: legacy
+    kind: rest
+    sync: false
+`)
+	res, err := MergeSpecs(base, ours, theirs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := spec.PatchYAML([]byte(oursYAML), ours, res.Spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# orders platform", "owner: team-a # not part of YSpec", "from: billing"} {
+		if !strings.Contains(string(out), want) {
+			t.Fatalf("patched yaml lost %q:\n%s", want, out)
+		}
+	}
+	if got := mustSpec(t, string(out)); len(got.Dependencies) != 2 {
+		t.Fatalf("merged dependencies: %+v", got.Dependencies)
+	}
+}
@@ -0,0 +1,62 @@
+package versioning
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/spec"
+)
+
+// FixPatch is the edit one applied fix made, stored with the version it produced. ID is the
+// suggestion (detection key) the fix came from.
+type FixPatch struct {
+	ID    string                 `json:"id" yaml:"id"`
+	Kind  domain.AntiPatternKind `json:"kind" yaml:"kind"`
+	Title string                 `json:"title" yaml:"title"`
+	Ops   []spec.PatchOp         `json:"ops" yaml:"ops"`
+}
+
+const patchesFile = "patches.json"
+
+// WritePatches stores the fix patches of v next to its architecture.yaml.
+func WritePatches(v *Version, patches []FixPatch) error {
+	b, err := json.MarshalIndent(patches, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(v.Dir, patchesFile), b, 0644)
+}
+
+// ReadPatches returns the fix patches stored with v; none for a version not made by fixes.
+func ReadPatches(v *Version) ([]FixPatch, error) {
+	b, err := os.ReadFile(filepath.Join(v.Dir, patchesFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var patches []FixPatch
+	if err := json.Unmarshal(b, &patches); err != nil {
+		return nil, fmt.Errorf("%s: %w", patchesFile, err)
+	}
+	return patches, nil
+}
+
+// FindPatch returns the patch of fix id stored with v.
+func FindPatch(v *Version, id string) (*FixPatch, error) {
+	patches, err := ReadPatches(v)
+	if err != nil {
+		return nil, err
+	}
+	for i := range patches {
+		if patches[i].ID == id {
+			return &patches[i], nil
+		}
+	}
+	return nil, fmt.Errorf("version %s has no fix %q", v.VersionID, id)
+}
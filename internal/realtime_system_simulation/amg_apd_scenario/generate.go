@@ -45,6 +45,26 @@ type svcInfo struct {
 	isIngress bool   // gateway-shaped entry (api_gateway kind): single /ingress endpoint
 	simKind   string // api_gateway, service, database, queue, topic
 	roleField string // YAML role: ingress when client-facing heuristic applies
+	// Deployment sizing from the AMG/APD entry (replicas, resources.cpu_cores, resources.memory_mb);
+	// zero means use the generator defaults.
+	replicas int
+	cpu      float64
+	memMB    float64
+}
+
+// applyDeployment reads replicas and per-replica resource requests (e.g. from a Kubernetes import).
+func (info *svcInfo) applyDeployment(m map[string]any) {
+	if f, ok := parsePositiveFloat(m["replicas"]); ok {
+		info.replicas = int(f)
+	}
+	if res, ok := m["resources"].(map[string]any); ok {
+		if f, ok := parsePositiveFloat(res["cpu_cores"]); ok {
+			info.cpu = f
+		}
+		if f, ok := parsePositiveFloat(res["memory_mb"]); ok {
+			info.memMB = f
+		}
+	}
 }
 
 // GenerateFromAMGAPDYAML parses AMG/APD diagram YAML and produces a scenario draft.
@@ -99,10 +119,12 @@ func GenerateFromAMGAPDYAMLWithOptions(amgYAML []byte, opts GenerationOptions) (
 		gwShape := ingressTypeOrRole(typ, role)
 		nameIngress := ingressNameHeuristic(id) && !gwShape && typ == "service"
 		simKind, roleField, isIngress := classifyService(typ, role, id, isDB, isQueue, isTopic, gwShape, nameIngress)
-		byID[id] = svcInfo{
+		info := svcInfo{
 			id: id, rawType: typ, role: role, isDB: isDB, isIngress: isIngress,
 			simKind: simKind, roleField: roleField,
 		}
+		info.applyDeployment(sm)
+		byID[id] = info
 		order = append(order, id)
 	}
 	if len(byID) == 0 {
@@ -133,7 +155,9 @@ func GenerateFromAMGAPDYAMLWithOptions(amgYAML []byte, opts GenerationOptions) (
 			if _, exists := byID[id]; exists {
 				continue
 			}
-			byID[id] = svcInfo{id: id, rawType: "database", isDB: true, simKind: "database"}
+			info := svcInfo{id: id, rawType: "database", isDB: true, simKind: "database"}
+			info.applyDeployment(dm)
+			byID[id] = info
 			order = append(order, id)
 		}
 	}
@@ -234,6 +258,15 @@ func GenerateFromAMGAPDYAMLWithOptions(amgYAML []byte, opts GenerationOptions) (
 			cpu = 0.5
 			mem = 1024.0
 		}
+		if info.replicas > 0 {
+			replicas = info.replicas
+		}
+		if info.cpu > 0 {
+			cpu = info.cpu
+		}
+		if info.memMB > 0 {
+			mem = info.memMB
+		}
 
 		var endpoints []EndpointDoc
 		switch info.simKind {
@@ -172,3 +172,48 @@ dependencies: []
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestGenerateFromAMGAPD_DeploymentSizing(t *testing.T) {
+	amg := []byte(`services:
+  - name: orders
+    type: service
+    replicas: 3
+    resources:
+      cpu_cores: 0.5
+      memory_mb: 256
+  - name: billing
+    type: service
+datastores:
+  - name: orders-db
+    type: postgres
+    replicas: 2
+    resources:
+      memory_mb: 2048
+dependencies:
+  - from: orders
+    to: billing
+    kind: rest
+    sync: true
+  - from: orders
+    to: orders-db
+    kind: db
+    sync: true
+`)
+	doc, _, err := GenerateFromAMGAPDYAML(amg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byID := map[string]ServiceDoc{}
+	for _, s := range doc.Services {
+		byID[s.ID] = s
+	}
+	if o := byID["orders"]; o.Replicas != 3 || o.CPUCores != 0.5 || o.MemoryMB != 256 {
+		t.Fatalf("orders sizing not applied: %+v", o)
+	}
+	if b := byID["billing"]; b.Replicas != 2 || b.CPUCores != 1.0 || b.MemoryMB != 512 {
+		t.Fatalf("billing should keep defaults: %+v", b)
+	}
+	if db := byID["orders-db"]; db.Replicas != 2 || db.CPUCores != 0.5 || db.MemoryMB != 2048 {
+		t.Fatalf("orders-db sizing not applied: %+v", db)
+	}
+}
@@ -0,0 +1,85 @@
+package amg_apd_version
+
+import (
+	"database/sql"
+	"time"
+)
+
+// BaselineRow is the version a project's analyses are compared against.
+type BaselineRow struct {
+	UserID          string
+	ProjectPublicID string
+	VersionID       string
+	UpdatedAt       time.Time
+}
+
+// GetBaseline returns the baseline for user + project. Returns nil if none is set.
+func (r *Repo) GetBaseline(userID, projectPublicID string) (*BaselineRow, error) {
+	if userID == "" {
+		userID = DefaultUserID
+	}
+	if projectPublicID == "" {
+		projectPublicID = DefaultChatID
+	}
+	row := &BaselineRow{UserID: userID, ProjectPublicID: projectPublicID}
+	err := r.db.QueryRow(`
+		SELECT version_id, updated_at
+		FROM amg_apd_project_baselines
+		WHERE user_firebase_uid = $1 AND project_public_id = $2
+	`, userID, projectPublicID).Scan(&row.VersionID, &row.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+// SetBaseline pins versionID as the baseline for user + project (replacing any previous one).
+func (r *Repo) SetBaseline(userID, projectPublicID, versionID string) (*BaselineRow, error) {
+	if userID == "" {
+		userID = DefaultUserID
+	}
+	if projectPublicID == "" {
+		projectPublicID = DefaultChatID
+	}
+	row := &BaselineRow{UserID: userID, ProjectPublicID: projectPublicID, VersionID: versionID}
+	err := r.db.QueryRow(`
+		INSERT INTO amg_apd_project_baselines (user_firebase_uid, project_public_id, version_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_firebase_uid, project_public_id)
+		DO UPDATE SET version_id = EXCLUDED.version_id, updated_at = now()
+		RETURNING updated_at
+	`, userID, projectPublicID, versionID).Scan(&row.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+// DeleteBaseline clears the project's baseline so every detection is reported again.
+func (r *Repo) DeleteBaseline(userID, projectPublicID string) (bool, error) {
+	if userID == "" {
+		userID = DefaultUserID
+	}
+	if projectPublicID == "" {
+		projectPublicID = DefaultChatID
+	}
+	res, err := r.db.Exec(`
+		DELETE FROM amg_apd_project_baselines
+		WHERE user_firebase_uid = $1 AND project_public_id = $2
+	`, userID, projectPublicID)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// clearBaselineOf removes the baselines pinned to versionID once that version is deleted, so
+// later analyses report every detection instead of comparing against a missing version.
+func (r *Repo) clearBaselineOf(versionID string) error {
+	_, err := r.db.Exec(`DELETE FROM amg_apd_project_baselines WHERE version_id = $1`, versionID)
+	return err
+}
@@ -0,0 +1,129 @@
+package amg_apd_version
+
+import (
+	"time"
+)
+
+// CustomRuleRow is one stored user-defined detection rule. OrgID is set for org rules;
+// UserID + ProjectPublicID for project rules.
+type CustomRuleRow struct {
+	OrgID           string
+	UserID          string
+	ProjectPublicID string
+	Kind            string
+	RuleJSON        []byte
+	UpdatedAt       time.Time
+}
+
+// ListProjectCustomRules returns the rules stored for user + project, ordered by kind.
+func (r *Repo) ListProjectCustomRules(userID, projectPublicID string) ([]CustomRuleRow, error) {
+	if userID == "" {
+		userID = DefaultUserID
+	}
+	if projectPublicID == "" {
+		projectPublicID = DefaultChatID
+	}
+	return r.listCustomRules("", userID, projectPublicID)
+}
+
+// ListOrgCustomRules returns the rules stored for an organization, ordered by kind.
+func (r *Repo) ListOrgCustomRules(orgID string) ([]CustomRuleRow, error) {
+	if orgID == "" {
+		return nil, nil
+	}
+	return r.listCustomRules(orgID, "", "")
+}
+
+// IsOrgMember reports whether the user belongs to the organization (users.organization).
+func (r *Repo) IsOrgMember(userID, orgID string) (bool, error) {
+	if userID == "" || orgID == "" {
+		return false, nil
+	}
+	var ok bool
+	err := r.db.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM users WHERE firebase_uid = $1 AND organization = $2)
+	`, userID, orgID).Scan(&ok)
+	return ok, err
+}
+
+func (r *Repo) listCustomRules(orgID, userID, projectPublicID string) ([]CustomRuleRow, error) {
+	rows, err := r.db.Query(`
+		SELECT kind, rule, updated_at
+		FROM amg_apd_custom_rules
+		WHERE org_id = $1 AND user_firebase_uid = $2 AND project_public_id = $3
+		ORDER BY kind
+	`, orgID, userID, projectPublicID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []CustomRuleRow
+	for rows.Next() {
+		row := CustomRuleRow{OrgID: orgID, UserID: userID, ProjectPublicID: projectPublicID}
+		if err := rows.Scan(&row.Kind, &row.RuleJSON, &row.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, row)
+	}
+	return out, rows.Err()
+}
+
+// UpsertProjectCustomRule stores (or replaces) a rule for user + project.
+func (r *Repo) UpsertProjectCustomRule(userID, projectPublicID, kind string, ruleJSON []byte) (*CustomRuleRow, error) {
+	if userID == "" {
+		userID = DefaultUserID
+	}
+	if projectPublicID == "" {
+		projectPublicID = DefaultChatID
+	}
+	return r.upsertCustomRule("", userID, projectPublicID, kind, ruleJSON)
+}
+
+// UpsertOrgCustomRule stores (or replaces) a rule for an organization.
+func (r *Repo) UpsertOrgCustomRule(orgID, kind string, ruleJSON []byte) (*CustomRuleRow, error) {
+	return r.upsertCustomRule(orgID, "", "", kind, ruleJSON)
+}
+
+func (r *Repo) upsertCustomRule(orgID, userID, projectPublicID, kind string, ruleJSON []byte) (*CustomRuleRow, error) {
+	row := &CustomRuleRow{OrgID: orgID, UserID: userID, ProjectPublicID: projectPublicID, Kind: kind, RuleJSON: ruleJSON}
+	err := r.db.QueryRow(`
+		INSERT INTO amg_apd_custom_rules (org_id, user_firebase_uid, project_public_id, kind, rule)
+		VALUES ($1, $2, $3, $4, $5::jsonb)
+		ON CONFLICT (org_id, user_firebase_uid, project_public_id, kind)
+		DO UPDATE SET rule = EXCLUDED.rule, updated_at = now()
+		RETURNING updated_at
+	`, orgID, userID, projectPublicID, kind, ruleJSON).Scan(&row.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+// DeleteProjectCustomRule removes one project rule. Returns whether a row was deleted.
+func (r *Repo) DeleteProjectCustomRule(userID, projectPublicID, kind string) (bool, error) {
+	if userID == "" {
+		userID = DefaultUserID
+	}
+	if projectPublicID == "" {
+		projectPublicID = DefaultChatID
+	}
+	return r.deleteCustomRules("", userID, projectPublicID, kind)
+}
+
+// DeleteOrgCustomRule removes one org rule. Returns whether a row was deleted.
+func (r *Repo) DeleteOrgCustomRule(orgID, kind string) (bool, error) {
+	return r.deleteCustomRules(orgID, "", "", kind)
+}
+
+// deleteCustomRules deletes the rule of kind in the given scope, or all of them when kind is "".
+func (r *Repo) deleteCustomRules(orgID, userID, projectPublicID, kind string) (bool, error) {
+	res, err := r.db.Exec(`
+		DELETE FROM amg_apd_custom_rules
+		WHERE org_id = $1 AND user_firebase_uid = $2 AND project_public_id = $3 AND ($4 = '' OR kind = $4)
+	`, orgID, userID, projectPublicID, kind)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
@@ -0,0 +1,78 @@
+package amg_apd_version
+
+import (
+	"database/sql"
+	"time"
+)
+
+// DetectorConfigRow is the stored per-project AMG-APD detector config.
+type DetectorConfigRow struct {
+	UserID          string
+	ProjectPublicID string
+	ConfigJSON      []byte
+	UpdatedAt       time.Time
+}
+
+// GetDetectorConfig returns the stored detector config for user + project. Returns nil if none is stored.
+func (r *Repo) GetDetectorConfig(userID, projectPublicID string) (*DetectorConfigRow, error) {
+	if userID == "" {
+		userID = DefaultUserID
+	}
+	if projectPublicID == "" {
+		projectPublicID = DefaultChatID
+	}
+	row := &DetectorConfigRow{UserID: userID, ProjectPublicID: projectPublicID}
+	err := r.db.QueryRow(`
+		SELECT config, updated_at
+		FROM amg_apd_detector_configs
+		WHERE user_firebase_uid = $1 AND project_public_id = $2
+	`, userID, projectPublicID).Scan(&row.ConfigJSON, &row.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+// UpsertDetectorConfig stores (or replaces) the detector config for user + project.
+func (r *Repo) UpsertDetectorConfig(userID, projectPublicID string, configJSON []byte) (*DetectorConfigRow, error) {
+	if userID == "" {
+		userID = DefaultUserID
+	}
+	if projectPublicID == "" {
+		projectPublicID = DefaultChatID
+	}
+	row := &DetectorConfigRow{UserID: userID, ProjectPublicID: projectPublicID, ConfigJSON: configJSON}
+	err := r.db.QueryRow(`
+		INSERT INTO amg_apd_detector_configs (user_firebase_uid, project_public_id, config)
+		VALUES ($1, $2, $3::jsonb)
+		ON CONFLICT (user_firebase_uid, project_public_id)
+		DO UPDATE SET config = EXCLUDED.config, updated_at = now()
+		RETURNING updated_at
+	`, userID, projectPublicID, configJSON).Scan(&row.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+// DeleteDetectorConfig removes the stored config so the project falls back to defaults.
+func (r *Repo) DeleteDetectorConfig(userID, projectPublicID string) (bool, error) {
+	if userID == "" {
+		userID = DefaultUserID
+	}
+	if projectPublicID == "" {
+		projectPublicID = DefaultChatID
+	}
+	res, err := r.db.Exec(`
+		DELETE FROM amg_apd_detector_configs
+		WHERE user_firebase_uid = $1 AND project_public_id = $2
+	`, userID, projectPublicID)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
@@ -30,7 +30,69 @@ type VersionRow struct {
 	GraphJSON      []byte
 	DOTContent     string
 	DetectionsJSON []byte
-	CreatedAt      time.Time
+	// DetectorConfigJSON is the effective detector config the detections were produced with (may be empty for older rows).
+	DetectorConfigJSON []byte
+	// MetricsJSON holds the structural graph metrics computed with the analysis (may be empty for older rows).
+	MetricsJSON []byte
+	// ParentVersionIDs are the versions this one was merged from (empty unless it is a merge).
+	ParentVersionIDs []string
+	CreatedAt        time.Time
+}
+
+// SaveExtras carries optional per-version analysis metadata stored next to diagram_json.
+// A nil *SaveExtras leaves those columns NULL (or unchanged on update).
+type SaveExtras struct {
+	DetectorConfigJSON []byte
+	MetricsJSON        []byte
+	// ParentVersionIDs records the merged versions (ours, theirs) for a merge result.
+	ParentVersionIDs []string
+}
+
+func (x *SaveExtras) detectorConfig() []byte {
+	if x == nil || len(x.DetectorConfigJSON) == 0 {
+		return nil
+	}
+	return x.DetectorConfigJSON
+}
+
+func (x *SaveExtras) metrics() []byte {
+	if x == nil || len(x.MetricsJSON) == 0 {
+		return nil
+	}
+	return x.MetricsJSON
+}
+
+func (x *SaveExtras) parents() []byte {
+	if x == nil || len(x.ParentVersionIDs) == 0 {
+		return nil
+	}
+	b, _ := json.Marshal(x.ParentVersionIDs)
+	return b
+}
+
+// nullJSON passes b as a JSONB argument, or SQL NULL when it is empty. A nil []byte must not
+// reach the driver as is: lib/pq sends it as an empty, non-NULL value, which is invalid json.
+func nullJSON(b []byte) any {
+	if len(b) == 0 {
+		return nil
+	}
+	return b
+}
+
+func (x *SaveExtras) parentIDs() []string {
+	if x == nil {
+		return nil
+	}
+	return x.ParentVersionIDs
+}
+
+// decodeParents reads parent_version_ids (NULL for versions that are not merges).
+func decodeParents(b []byte) []string {
+	var ids []string
+	if len(b) > 0 {
+		_ = json.Unmarshal(b, &ids)
+	}
+	return ids
 }
 
 // Repo persists AMG-APD analyses for versioning and compare.
@@ -105,7 +167,8 @@ func alignDiagramVAutoTitle(title string, nextVersion int) string {
 // Save stores a new version for the given user_id and chat_id. version_number is auto-incremented per (user_id, chat_id).
 // mergePreviousDiagram: when true, merges the latest row's canvas diagram_json into this save (layout + nodes/edges missing from analysis).
 // Set false after apply-suggestions so removed anti-pattern nodes are not reintroduced from the previous diagram.
-func (r *Repo) Save(userID, chatID, title, yamlContent string, graphJSON, detectionsJSON []byte, dotContent string, mergePreviousDiagram bool) (*VersionRow, error) {
+// extras may be nil.
+func (r *Repo) Save(userID, chatID, title, yamlContent string, graphJSON, detectionsJSON []byte, dotContent string, mergePreviousDiagram bool, extras *SaveExtras) (*VersionRow, error) {
 	if userID == "" {
 		userID = DefaultUserID
 	}
@@ -191,15 +254,21 @@ func (r *Repo) Save(userID, chatID, title, yamlContent string, graphJSON, detect
 			dot_content,
 			image_object_key,
 			spec_summary,
-			created_by
+			created_by,
+			detector_config,
+			metrics,
+			parent_version_ids
 		)
 		VALUES (
 			$1, $2, $3, $4, 'amg_apd', $5, $6, $7, $8,
 			NULLIF(TRIM($9), ''),
 			CASE WHEN TRIM(COALESCE($10::text, '')) = '' THEN NULL ELSE $10::jsonb END,
-			$11
+			$11,
+			$12,
+			$13,
+			$14
 		)
-	`, id, userID, chatID, nextVersion, title, yamlContent, diagramJSON, dotContent, imgKey, specJSON, createdBy)
+	`, id, userID, chatID, nextVersion, title, yamlContent, diagramJSON, dotContent, imgKey, specJSON, createdBy, nullJSON(extras.detectorConfig()), nullJSON(extras.metrics()), nullJSON(extras.parents()))
 	if err != nil {
 		return nil, err
 	}
@@ -224,6 +293,10 @@ func (r *Repo) Save(userID, chatID, title, yamlContent string, graphJSON, detect
 		GraphJSON:      graphJSON,
 		DOTContent:     dotContent,
 		DetectionsJSON: detectionsJSON,
+
+		DetectorConfigJSON: extras.detectorConfig(),
+		MetricsJSON:        extras.metrics(),
+		ParentVersionIDs:   extras.parentIDs(),
 	}
 	row.CreatedAt = time.Now().UTC()
 	return row, nil
@@ -273,12 +346,13 @@ func (r *Repo) GetByID(id string) (*VersionRow, error) {
 	row := &VersionRow{ID: id}
 	var diagramJSON []byte
 	var dotContent sql.NullString
+	var parents []byte
 	err := r.db.QueryRow(`
-		SELECT user_firebase_uid, project_public_id, version_number, title, yaml_content, diagram_json, dot_content, created_at, source
+		SELECT user_firebase_uid, project_public_id, version_number, title, yaml_content, diagram_json, dot_content, created_at, source, detector_config, metrics, parent_version_ids
 		FROM diagram_versions
 		WHERE id = $1
 	`, id).Scan(&row.UserID, &row.ChatID, &row.VersionNumber, &row.Title,
-		&row.YAMLContent, &diagramJSON, &dotContent, &row.CreatedAt, &row.Source)
+		&row.YAMLContent, &diagramJSON, &dotContent, &row.CreatedAt, &row.Source, &row.DetectorConfigJSON, &row.MetricsJSON, &parents)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -286,6 +360,7 @@ func (r *Repo) GetByID(id string) (*VersionRow, error) {
 		return nil, err
 	}
 	row.GraphJSON, row.DetectionsJSON = extractGraphAndDetectionsFromDiagramJSON(diagramJSON)
+	row.ParentVersionIDs = decodeParents(parents)
 	if dotContent.Valid {
 		row.DOTContent = dotContent.String
 	}
@@ -310,13 +385,18 @@ func (r *Repo) GetByIDForUserChat(id, userID, chatID string) (*VersionRow, error
 	return row, nil
 }
 
-// DeleteByID deletes a version by id. Returns whether a row was deleted.
+// DeleteByID deletes a version by id (and a baseline pinned to it). Returns whether a row was deleted.
 func (r *Repo) DeleteByID(id string) (bool, error) {
 	res, err := r.db.Exec(`DELETE FROM diagram_versions WHERE id = $1 AND source = 'amg_apd'`, id)
 	if err != nil {
 		return false, err
 	}
 	n, _ := res.RowsAffected()
+	if n > 0 {
+		if err := r.clearBaselineOf(id); err != nil {
+			return true, err
+		}
+	}
 	return n > 0, nil
 }
 
@@ -341,7 +421,8 @@ func (r *Repo) UpdateTitleByIDForUserChat(id, userID, chatID, title string) (boo
 	return n > 0, nil
 }
 
-// DeleteByIDForUserChat deletes a version by id only if it belongs to user_id and chat_id.
+// DeleteByIDForUserChat deletes a version by id only if it belongs to user_id and chat_id, and
+// clears a baseline pinned to it.
 func (r *Repo) DeleteByIDForUserChat(id, userID, chatID string) (bool, error) {
 	if userID == "" {
 		userID = DefaultUserID
@@ -354,10 +435,16 @@ func (r *Repo) DeleteByIDForUserChat(id, userID, chatID string) (bool, error) {
 		return false, err
 	}
 	n, _ := res.RowsAffected()
+	if n > 0 {
+		if err := r.clearBaselineOf(id); err != nil {
+			return true, err
+		}
+	}
 	return n > 0, nil
 }
 
-// DeleteByProject deletes all AMG-APD versions for the given user and project (e.g. when project is deleted).
+// DeleteByProject deletes all AMG-APD versions, the stored detector config, baseline and custom rules for the given user and project (e.g. when project is deleted).
+// Detection feedback is removed by repositories.FeedbackRepo.DeleteByProject.
 func (r *Repo) DeleteByProject(userID, projectPublicID string) (int64, error) {
 	if userID == "" {
 		userID = DefaultUserID
@@ -372,6 +459,15 @@ func (r *Repo) DeleteByProject(userID, projectPublicID string) (int64, error) {
 	if err != nil {
 		return 0, err
 	}
+	if _, err := r.DeleteDetectorConfig(userID, projectPublicID); err != nil {
+		return 0, err
+	}
+	if _, err := r.DeleteBaseline(userID, projectPublicID); err != nil {
+		return 0, err
+	}
+	if _, err := r.deleteCustomRules("", userID, projectPublicID, ""); err != nil {
+		return 0, err
+	}
 	return res.RowsAffected()
 }
 
@@ -426,15 +522,16 @@ func (r *Repo) GetLatestByUserProject(userID, projectPublicID string) (*VersionR
 	row := &VersionRow{}
 	var diagramJSON []byte
 	var dotContent sql.NullString
+	var parents []byte
 	err := r.db.QueryRow(`
-		SELECT id, user_firebase_uid, project_public_id, version_number, title, yaml_content, diagram_json, dot_content, created_at, source
+		SELECT id, user_firebase_uid, project_public_id, version_number, title, yaml_content, diagram_json, dot_content, created_at, source, detector_config, metrics, parent_version_ids
 		FROM diagram_versions
 		WHERE user_firebase_uid = $1 AND project_public_id = $2 AND source = 'amg_apd'
 		ORDER BY version_number DESC
 		LIMIT 1
 	`, userID, projectPublicID).Scan(
 		&row.ID, &row.UserID, &row.ChatID, &row.VersionNumber, &row.Title,
-		&row.YAMLContent, &diagramJSON, &dotContent, &row.CreatedAt, &row.Source,
+		&row.YAMLContent, &diagramJSON, &dotContent, &row.CreatedAt, &row.Source, &row.DetectorConfigJSON, &row.MetricsJSON, &parents,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -443,6 +540,7 @@ func (r *Repo) GetLatestByUserProject(userID, projectPublicID string) (*VersionR
 		return nil, err
 	}
 	row.GraphJSON, row.DetectionsJSON = extractGraphAndDetectionsFromDiagramJSON(diagramJSON)
+	row.ParentVersionIDs = decodeParents(parents)
 	if dotContent.Valid {
 		row.DOTContent = dotContent.String
 	}
@@ -506,8 +604,8 @@ func (r *Repo) GetLatestDiagramRowByUserProject(userID, projectPublicID string)
 
 // UpdateAnalysisByID updates the stored analysis fields for an existing AMG-APD version row.
 // This does NOT create a new version; it overwrites diagram_json/dot_content for the given id,
-// scoped to the given user_id + project_public_id.
-func (r *Repo) UpdateAnalysisByID(id, userID, projectPublicID string, graphJSON, detectionsJSON []byte, dotContent string) error {
+// scoped to the given user_id + project_public_id. extras may be nil (detector_config / metrics left unchanged).
+func (r *Repo) UpdateAnalysisByID(id, userID, projectPublicID string, graphJSON, detectionsJSON []byte, dotContent string, extras *SaveExtras) error {
 	if userID == "" {
 		userID = DefaultUserID
 	}
@@ -544,12 +642,14 @@ func (r *Repo) UpdateAnalysisByID(id, userID, projectPublicID string, graphJSON,
 		    spec_summary = CASE
 		      WHEN TRIM(COALESCE($6::text, '')) = '' THEN spec_summary
 		      ELSE $6::jsonb
-		    END
+		    END,
+		    detector_config = COALESCE($7::jsonb, detector_config),
+		    metrics = COALESCE($8::jsonb, metrics)
 		WHERE id = $3
 		  AND user_firebase_uid = $4
 		  AND project_public_id = $5
 		  AND source = 'amg_apd'
-	`, diagramJSON, dotContent, id, userID, projectPublicID, specSummary)
+	`, diagramJSON, dotContent, id, userID, projectPublicID, specSummary, nullJSON(extras.detectorConfig()), nullJSON(extras.metrics()))
 	if err != nil {
 		return err
 	}
@@ -569,7 +669,8 @@ func (r *Repo) UpdateAnalysisByID(id, userID, projectPublicID string, graphJSON,
 // (avoids mixing a different YAML’s graph onto a historic snapshot if version_id and YAML were mismatched).
 // Version 1 keeps its existing source (e.g. canvas_json from the main canvas); version 2+
 // are marked source = 'amg_apd' when analysis is written from the AMG-APD flow.
-func (r *Repo) UpdateDiagramVersionAnalysisByID(id, userID, projectPublicID string, graphJSON, detectionsJSON []byte, dotContent, yamlContent string, preserveCanvasMerge bool) error {
+// extras may be nil (detector_config / metrics left unchanged).
+func (r *Repo) UpdateDiagramVersionAnalysisByID(id, userID, projectPublicID string, graphJSON, detectionsJSON []byte, dotContent, yamlContent string, preserveCanvasMerge bool, extras *SaveExtras) error {
 	if userID == "" {
 		userID = DefaultUserID
 	}
@@ -612,9 +713,11 @@ func (r *Repo) UpdateDiagramVersionAnalysisByID(id, userID, projectPublicID stri
 		      WHEN TRIM(COALESCE($6::text, '')) = '' THEN spec_summary
 		      ELSE $6::jsonb
 		    END,
-		    source = CASE WHEN version_number = 1 THEN source ELSE 'amg_apd' END
+		    source = CASE WHEN version_number = 1 THEN source ELSE 'amg_apd' END,
+		    detector_config = COALESCE($8::jsonb, detector_config),
+		    metrics = COALESCE($9::jsonb, metrics)
 		WHERE id = $3 AND user_firebase_uid = $4 AND project_public_id = $5
-	`, diagramJSON, dotContent, id, userID, projectPublicID, specSummary, yamlContent)
+	`, diagramJSON, dotContent, id, userID, projectPublicID, specSummary, yamlContent, nullJSON(extras.detectorConfig()), nullJSON(extras.metrics()))
 	if err != nil {
 		return err
 	}
@@ -637,12 +740,13 @@ func (r *Repo) GetByIDForUserProject(id, userID, projectPublicID string) (*Versi
 	row := &VersionRow{ID: id}
 	var diagramJSON []byte
 	var dotContent sql.NullString
+	var parents []byte
 	err := r.db.QueryRow(`
-		SELECT user_firebase_uid, project_public_id, version_number, title, yaml_content, diagram_json, dot_content, created_at, source
+		SELECT user_firebase_uid, project_public_id, version_number, title, yaml_content, diagram_json, dot_content, created_at, source, detector_config, metrics, parent_version_ids
 		FROM diagram_versions
 		WHERE id = $1 AND user_firebase_uid = $2 AND project_public_id = $3
 	`, id, userID, projectPublicID).Scan(&row.UserID, &row.ChatID, &row.VersionNumber, &row.Title,
-		&row.YAMLContent, &diagramJSON, &dotContent, &row.CreatedAt, &row.Source)
+		&row.YAMLContent, &diagramJSON, &dotContent, &row.CreatedAt, &row.Source, &row.DetectorConfigJSON, &row.MetricsJSON, &parents)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -650,6 +754,7 @@ func (r *Repo) GetByIDForUserProject(id, userID, projectPublicID string) (*Versi
 		return nil, err
 	}
 	row.GraphJSON, row.DetectionsJSON = extractGraphAndDetectionsFromDiagramJSON(diagramJSON)
+	row.ParentVersionIDs = decodeParents(parents)
 	if dotContent.Valid {
 		row.DOTContent = dotContent.String
 	}
@@ -0,0 +1,126 @@
+package amg_apd_version
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+)
+
+// sqlNull matches an argument the driver receives as SQL NULL (an untyped nil, not a nil []byte).
+type sqlNull struct{}
+
+func (sqlNull) Match(v driver.Value) bool { return v == nil }
+
+func expectSave(mock sqlmock.Sqlmock, detectorConfig, metrics, parents driver.Value) {
+	mock.ExpectQuery(`SELECT COALESCE\(MAX\(version_number\), 0\) \+ 1`).
+		WithArgs("u1", "p1").
+		WillReturnRows(sqlmock.NewRows([]string{"next"}).AddRow(1))
+	mock.ExpectQuery(`SELECT image_object_key, spec_summary, created_by`).
+		WithArgs("u1", "p1").
+		WillReturnRows(sqlmock.NewRows([]string{"image_object_key", "spec_summary", "created_by", "diagram_json"}))
+	mock.ExpectExec(`INSERT INTO diagram_versions`).
+		WithArgs(sqlmock.AnyArg(), "u1", "p1", 1, "diagramV1", "services: []", sqlmock.AnyArg(), "", sqlmock.AnyArg(), sqlmock.AnyArg(), "u1",
+			detectorConfig, metrics, parents).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE projects`).WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func TestSave_NoExtrasStoresNULL(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	expectSave(mock, sqlNull{}, sqlNull{}, sqlNull{})
+
+	row, err := NewRepo(db).Save("u1", "p1", "", "services: []", []byte(`{"nodes":{},"edges":[]}`), nil, "", false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(row.ParentVersionIDs) != 0 {
+		t.Fatalf("parents: %v", row.ParentVersionIDs)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSave_NoParentsStoresNULL(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	expectSave(mock, sqlmock.AnyArg(), sqlNull{}, sqlNull{})
+
+	extras := &SaveExtras{DetectorConfigJSON: []byte(`{}`)}
+	if _, err := NewRepo(db).Save("u1", "p1", "", "services: []", []byte(`{"nodes":{},"edges":[]}`), nil, "", false, extras); err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSave_ParentsStoredAsJSON(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	expectSave(mock, sqlNull{}, sqlNull{}, []byte(`["v1","v2"]`))
+
+	extras := &SaveExtras{ParentVersionIDs: []string{"v1", "v2"}}
+	if _, err := NewRepo(db).Save("u1", "p1", "", "services: []", []byte(`{"nodes":{},"edges":[]}`), nil, "", false, extras); err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeleteByIDForUserChat_ClearsBaseline(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	mock.ExpectExec(`DELETE FROM diagram_versions`).
+		WithArgs("dver_1", "u1", "p1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM amg_apd_project_baselines WHERE version_id = \$1`).
+		WithArgs("dver_1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ok, err := NewRepo(db).DeleteByIDForUserChat("dver_1", "u1", "p1")
+	if err != nil || !ok {
+		t.Fatalf("delete: ok=%v err=%v", ok, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIsOrgMember(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM users WHERE firebase_uid = \$1 AND organization = \$2\)`).
+		WithArgs("u1", "acme").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	repo := NewRepo(db)
+	ok, err := repo.IsOrgMember("u1", "acme")
+	if err != nil || ok {
+		t.Fatalf("member: ok=%v err=%v", ok, err)
+	}
+	if ok, _ := repo.IsOrgMember("u1", ""); ok {
+		t.Fatal("empty org must not match")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
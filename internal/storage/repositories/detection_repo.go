@@ -1 +1 @@
-package internal
\ No newline at end of file
+package repositories
@@ -1 +1,117 @@
-package internal
\ No newline at end of file
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/domain"
+	"github.com/GoSim-25-26J-441/go-sim-backend/internal/architecture_modelling_antipattern_detection/feedback"
+)
+
+// FeedbackRepo stores AMG-APD detection feedback in amg_apd_detection_feedback.
+type FeedbackRepo struct {
+	db *sql.DB
+}
+
+func NewFeedbackRepo(db *sql.DB) *FeedbackRepo {
+	return &FeedbackRepo{db: db}
+}
+
+var _ feedback.Store = (*FeedbackRepo)(nil)
+
+// UpsertFeedback inserts or replaces the feedback for (user, project, detection key).
+func (r *FeedbackRepo) UpsertFeedback(f *feedback.Feedback) (*feedback.Feedback, error) {
+	var detJSON []byte
+	if f.Detection != nil {
+		b, err := json.Marshal(f.Detection)
+		if err != nil {
+			return nil, err
+		}
+		detJSON = b
+	}
+	out := *f
+	err := r.db.QueryRow(`
+		INSERT INTO amg_apd_detection_feedback (
+			user_firebase_uid, project_public_id, detection_key, kind, status, reason, expires_at, version_id, detection
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9::jsonb)
+		ON CONFLICT (user_firebase_uid, project_public_id, detection_key)
+		DO UPDATE SET kind = EXCLUDED.kind,
+		              status = EXCLUDED.status,
+		              reason = EXCLUDED.reason,
+		              expires_at = EXCLUDED.expires_at,
+		              version_id = COALESCE(EXCLUDED.version_id, amg_apd_detection_feedback.version_id),
+		              detection = COALESCE(EXCLUDED.detection, amg_apd_detection_feedback.detection),
+		              updated_at = now()
+		RETURNING created_at, updated_at
+	`, f.UserID, f.ProjectPublicID, f.DetectionKey, string(f.Kind), string(f.Status), f.Reason, f.ExpiresAt, f.VersionID, detJSON).
+		Scan(&out.CreatedAt, &out.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListFeedback returns all feedback for user + project, most recently updated first.
+func (r *FeedbackRepo) ListFeedback(userID, projectPublicID string) ([]feedback.Feedback, error) {
+	rows, err := r.db.Query(`
+		SELECT detection_key, kind, status, reason, expires_at, COALESCE(version_id, ''), detection, created_at, updated_at
+		FROM amg_apd_detection_feedback
+		WHERE user_firebase_uid = $1 AND project_public_id = $2
+		ORDER BY updated_at DESC
+	`, userID, projectPublicID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []feedback.Feedback
+	for rows.Next() {
+		f := feedback.Feedback{UserID: userID, ProjectPublicID: projectPublicID}
+		var kind, status string
+		var expires sql.NullTime
+		var detJSON []byte
+		if err := rows.Scan(&f.DetectionKey, &kind, &status, &f.Reason, &expires, &f.VersionID, &detJSON, &f.CreatedAt, &f.UpdatedAt); err != nil {
+			return nil, err
+		}
+		f.Kind = domain.AntiPatternKind(kind)
+		f.Status = feedback.Status(status)
+		if expires.Valid {
+			t := expires.Time
+			f.ExpiresAt = &t
+		}
+		if len(detJSON) > 0 {
+			var d domain.Detection
+			if err := json.Unmarshal(detJSON, &d); err == nil {
+				f.Detection = &d
+			}
+		}
+		list = append(list, f)
+	}
+	return list, rows.Err()
+}
+
+// DeleteFeedback removes feedback for one detection key. Returns whether a row was deleted.
+func (r *FeedbackRepo) DeleteFeedback(userID, projectPublicID, detectionKey string) (bool, error) {
+	res, err := r.db.Exec(`
+		DELETE FROM amg_apd_detection_feedback
+		WHERE user_firebase_uid = $1 AND project_public_id = $2 AND detection_key = $3
+	`, userID, projectPublicID, detectionKey)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// DeleteByProject removes all feedback for user + project (e.g. when the project is deleted).
+func (r *FeedbackRepo) DeleteByProject(userID, projectPublicID string) (int64, error) {
+	res, err := r.db.Exec(`
+		DELETE FROM amg_apd_detection_feedback
+		WHERE user_firebase_uid = $1 AND project_public_id = $2
+	`, userID, projectPublicID)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
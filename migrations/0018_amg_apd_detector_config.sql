@@ -0,0 +1,28 @@
+-- AMG-APD per-project detector configuration.
+--
+-- Detector thresholds used to come only from process-wide DETECT_* env vars.
+-- Projects can now store their own config (enable/disable per anti-pattern kind,
+-- thresholds, severity overrides), and each analyzed diagram version records the
+-- effective config it was produced with so results stay reproducible.
+
+CREATE TABLE IF NOT EXISTS amg_apd_detector_configs (
+  user_firebase_uid TEXT NOT NULL,
+  project_public_id TEXT NOT NULL,
+  config JSONB NOT NULL DEFAULT '{}',
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+  PRIMARY KEY (user_firebase_uid, project_public_id)
+);
+
+DROP TRIGGER IF EXISTS trg_amg_apd_detector_configs_updated_at ON amg_apd_detector_configs;
+CREATE TRIGGER trg_amg_apd_detector_configs_updated_at
+BEFORE UPDATE ON amg_apd_detector_configs
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE diagram_versions
+ADD COLUMN IF NOT EXISTS detector_config JSONB;
+
+COMMENT ON TABLE amg_apd_detector_configs IS 'Per-project AMG-APD detector config (rules: enabled/thresholds/severity per anti-pattern kind)';
+COMMENT ON COLUMN diagram_versions.detector_config IS 'Effective AMG-APD detector config used to produce the stored detections';
@@ -0,0 +1,10 @@
+-- AMG-APD structural graph metrics per diagram version.
+--
+-- Each analysis now computes fan-in/fan-out, Martin instability, betweenness
+-- centrality, longest sync depth, SCC count and density over the graph. Storing
+-- them with the version lets projects track structural health over time.
+
+ALTER TABLE diagram_versions
+ADD COLUMN IF NOT EXISTS metrics JSONB;
+
+COMMENT ON COLUMN diagram_versions.metrics IS 'AMG-APD structural graph metrics (per-graph and per-node) computed with the stored analysis';
@@ -0,0 +1,35 @@
+-- AMG-APD detection feedback.
+--
+-- Users can mark a detection as a false positive, accept the risk (with a reason
+-- and optional expiry) or flag it as "will fix". Feedback is keyed by the
+-- detection key (kind|sorted nodes), so later analyses of the same project can
+-- annotate or hide matching detections. The detection snapshot is kept so the
+-- feedback can be exported as training data for detector tuning.
+
+CREATE TABLE IF NOT EXISTS amg_apd_detection_feedback (
+  user_firebase_uid TEXT NOT NULL,
+  project_public_id TEXT NOT NULL,
+  detection_key TEXT NOT NULL,
+  kind TEXT NOT NULL DEFAULT '',
+  status TEXT NOT NULL CHECK (status IN ('false_positive', 'accepted_risk', 'will_fix')),
+  reason TEXT NOT NULL DEFAULT '',
+  expires_at TIMESTAMPTZ,
+  version_id TEXT,
+  detection JSONB,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+  PRIMARY KEY (user_firebase_uid, project_public_id, detection_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_amg_apd_detection_feedback_kind
+  ON amg_apd_detection_feedback (kind, status);
+
+DROP TRIGGER IF EXISTS trg_amg_apd_detection_feedback_updated_at ON amg_apd_detection_feedback;
+CREATE TRIGGER trg_amg_apd_detection_feedback_updated_at
+BEFORE UPDATE ON amg_apd_detection_feedback
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE amg_apd_detection_feedback IS 'Per-project AMG-APD detection feedback (false_positive / accepted_risk / will_fix) keyed by detection key';
+COMMENT ON COLUMN amg_apd_detection_feedback.detection IS 'Detection snapshot at feedback time (training data export)';
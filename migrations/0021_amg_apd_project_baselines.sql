@@ -0,0 +1,24 @@
+-- AMG-APD project baselines.
+--
+-- A project can pin one analyzed diagram version as its baseline. Later analyses
+-- are compared against the baseline's detections and only detections that are not
+-- already in the baseline are reported as failures (known anti-patterns are tolerated).
+
+CREATE TABLE IF NOT EXISTS amg_apd_project_baselines (
+  user_firebase_uid TEXT NOT NULL,
+  project_public_id TEXT NOT NULL,
+  version_id TEXT NOT NULL,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+  PRIMARY KEY (user_firebase_uid, project_public_id)
+);
+
+DROP TRIGGER IF EXISTS trg_amg_apd_project_baselines_updated_at ON amg_apd_project_baselines;
+CREATE TRIGGER trg_amg_apd_project_baselines_updated_at
+BEFORE UPDATE ON amg_apd_project_baselines
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE amg_apd_project_baselines IS 'Per-project AMG-APD baseline version; analyses report only detections not present in it';
+COMMENT ON COLUMN amg_apd_project_baselines.version_id IS 'diagram_versions.id whose detections form the baseline';
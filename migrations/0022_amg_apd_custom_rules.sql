@@ -0,0 +1,31 @@
+-- AMG-APD user-defined detection rules.
+--
+-- Built-in detectors are Go code; teams can now add their own rules as expressions over
+-- nodes, edges and graph metrics (see detection.CustomRule). Rules are stored either for a
+-- project (user_firebase_uid + project_public_id) or for an organization (org_id) and are
+-- validated on save. Project rules override org rules with the same kind.
+
+CREATE TABLE IF NOT EXISTS amg_apd_custom_rules (
+  org_id TEXT NOT NULL DEFAULT '',
+  user_firebase_uid TEXT NOT NULL DEFAULT '',
+  project_public_id TEXT NOT NULL DEFAULT '',
+  kind TEXT NOT NULL,
+  rule JSONB NOT NULL,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+  PRIMARY KEY (org_id, user_firebase_uid, project_public_id, kind),
+  CONSTRAINT amg_apd_custom_rules_scope_check CHECK (
+    (org_id <> '' AND user_firebase_uid = '' AND project_public_id = '')
+    OR (org_id = '' AND project_public_id <> '')
+  )
+);
+
+DROP TRIGGER IF EXISTS trg_amg_apd_custom_rules_updated_at ON amg_apd_custom_rules;
+CREATE TRIGGER trg_amg_apd_custom_rules_updated_at
+BEFORE UPDATE ON amg_apd_custom_rules
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE amg_apd_custom_rules IS 'User-defined AMG-APD detection rules per project or organization';
+COMMENT ON COLUMN amg_apd_custom_rules.rule IS 'detection.CustomRule JSON (kind, title, summary, severity, target, expr)';
@@ -0,0 +1,10 @@
+-- AMG-APD merge parents per diagram version.
+--
+-- A version produced by a three-way merge of two versions records both parents
+-- (ours first, then theirs) so the project history can show where it came from.
+-- Ordinary versions leave the column NULL.
+
+ALTER TABLE diagram_versions
+ADD COLUMN IF NOT EXISTS parent_version_ids JSONB;
+
+COMMENT ON COLUMN diagram_versions.parent_version_ids IS 'JSON array of diagram_versions.id this version was merged from (ours, theirs); NULL when not a merge';
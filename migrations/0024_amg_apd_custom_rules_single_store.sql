@@ -0,0 +1,18 @@
+-- AMG-APD custom rules in one place.
+--
+-- Project custom rules were accepted both in the stored detector config (config.custom_rules)
+-- and in amg_apd_custom_rules. The table is now the only store: rules found in a stored
+-- config move to the project's table rows (an existing table rule of the same kind wins)
+-- and are removed from the config.
+
+INSERT INTO amg_apd_custom_rules (user_firebase_uid, project_public_id, kind, rule)
+SELECT c.user_firebase_uid, c.project_public_id, r->>'kind', r
+FROM amg_apd_detector_configs c,
+     jsonb_array_elements(c.config->'custom_rules') AS r
+WHERE jsonb_typeof(c.config->'custom_rules') = 'array'
+  AND COALESCE(r->>'kind', '') <> ''
+ON CONFLICT (org_id, user_firebase_uid, project_public_id, kind) DO NOTHING;
+
+UPDATE amg_apd_detector_configs
+SET config = config - 'custom_rules'
+WHERE config ? 'custom_rules';